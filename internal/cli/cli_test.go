@@ -2,17 +2,53 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/kinetic-dev/kinetic/internal/subnet"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+// TestMain points the user config and data directories at a temporary
+// directory, so commands under test never load, migrate or write the
+// developer's own config
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "kinetic-cli-test")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Setenv("HOME", home)
+	os.Setenv("XDG_CONFIG_HOME", home)
+	os.Setenv("XDG_DATA_HOME", filepath.Join(home, "share"))
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
+// isolateConfigDir gives a test its own empty user config and data
+// directories, returning the config directory
+func isolateConfigDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(dir, "share"))
+	return dir
+}
+
 // testCommand is a helper function to execute a command and capture its output
 func testCommand(t *testing.T, cmd *cobra.Command, args []string) (string, error) {
 	t.Helper()
+	resetFlags(cmd)
 	buf := new(bytes.Buffer)
 	cmd.SetOut(buf)
 	cmd.SetErr(buf)
@@ -21,7 +57,35 @@ func testCommand(t *testing.T, cmd *cobra.Command, args []string) (string, error
 	return strings.TrimSpace(buf.String()), err
 }
 
+// resetFlags restores the flags of a command tree to their defaults, as flag
+// values otherwise persist between executions within a test binary
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			sv.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+// restoreContractCmd puts contractCmd back under rootCmd after a test adds
+// it to a standalone root, which takes it out of the real command tree
+func restoreContractCmd(t *testing.T) {
+	t.Cleanup(func() {
+		rootCmd.RemoveCommand(contractCmd)
+		rootCmd.AddCommand(contractCmd)
+	})
+}
+
 func TestContractCreateCommand(t *testing.T) {
+	restoreContractCmd(t)
 	// Create a temporary directory for test files
 	tmpDir, err := os.MkdirTemp("", "cli-test")
 	if err != nil {
@@ -155,6 +219,7 @@ contract {{.ContractName}} {
 }
 
 func TestContractDeployCommand(t *testing.T) {
+	restoreContractCmd(t)
 	tests := []struct {
 		name    string
 		args    []string
@@ -265,3 +330,518 @@ func TestFlagHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintResult(t *testing.T) {
+	result := struct {
+		Name    string `json:"name"`
+		Running bool   `json:"running"`
+	}{Name: "kinetic-node", Running: true}
+
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "text output",
+			format: "text",
+			want:   "kinetic-node is running",
+		},
+		{
+			name:   "json output",
+			format: "json",
+			want:   "{\n  \"name\": \"kinetic-node\",\n  \"running\": true\n}",
+		},
+		{
+			name:   "yaml output",
+			format: "yaml",
+			want:   "name: kinetic-node\nrunning: true",
+		},
+		{
+			name:    "invalid output",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{
+				Use: "test",
+				RunE: func(cmd *cobra.Command, args []string) error {
+					return printResult(cmd, result, func(w io.Writer) {
+						fmt.Fprintf(w, "%s is running\n", result.Name)
+					})
+				},
+			}
+			cmd.Flags().String("output", "text", "output format")
+
+			output, err := testCommand(t, cmd, []string{"--output", tt.format})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("command execution error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && output != tt.want {
+				t.Errorf("unexpected output.\nGot:\n%s\nWant:\n%s", output, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubnetPrecompiles(t *testing.T) {
+	const admin = "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"
+	const user = "0x0000000000000000000000000000000000000001"
+
+	tests := []struct {
+		name  string
+		args  []string
+		input string
+		check func(t *testing.T, p *subnet.Precompiles)
+	}{
+		{
+			name: "flags",
+			args: []string{"--tx-allowlist-admins", admin, "--tx-allowlist-enabled", user, "--native-minter-admins", admin},
+			check: func(t *testing.T, p *subnet.Precompiles) {
+				if p.TxAllowList == nil || p.TxAllowList.Admins[0] != admin || p.TxAllowList.Enabled[0] != user {
+					t.Errorf("unexpected tx allow list %+v", p.TxAllowList)
+				}
+				if p.NativeMinter == nil || p.DeployerAllowList != nil {
+					t.Errorf("expected only the tx allow list and native minter, got %+v", p)
+				}
+			},
+		},
+		{
+			name:  "interactive",
+			args:  []string{"--interactive", "--fee-manager-admins", admin},
+			input: "y\n" + admin + "\n" + user + ", " + admin + "\nn\nn\nyes\n" + admin + "\n\n" + admin + "\n",
+			check: func(t *testing.T, p *subnet.Precompiles) {
+				if p.DeployerAllowList == nil || len(p.DeployerAllowList.Enabled) != 2 {
+					t.Errorf("unexpected deployer allow list %+v", p.DeployerAllowList)
+				}
+				if p.TxAllowList != nil || p.NativeMinter != nil {
+					t.Error("expected declined precompiles to stay disabled")
+				}
+				if p.FeeManager == nil || p.RewardManager == nil || p.RewardAddress != admin {
+					t.Errorf("unexpected precompiles %+v", p)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *subnet.Precompiles
+			cmd := &cobra.Command{
+				Use: "test",
+				RunE: func(cmd *cobra.Command, args []string) error {
+					var err error
+					got, err = subnetPrecompiles(cmd)
+					return err
+				},
+			}
+			addPrecompileFlags(cmd.Flags())
+			cmd.SetIn(strings.NewReader(tt.input))
+
+			if _, err := testCommand(t, cmd, tt.args); err != nil {
+				t.Fatalf("command execution failed: %v", err)
+			}
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestConfigShow(t *testing.T) {
+	isolateConfigDir(t)
+	t.Setenv("KINETIC_NODE_API_PORT", "9700")
+
+	output, err := testCommand(t, rootCmd, []string{"config", "show", "--origin", "--output", "json"})
+	if err != nil {
+		t.Fatalf("command execution failed: %v", err)
+	}
+	var entries []configEntry
+	if err := json.Unmarshal([]byte(output), &entries); err != nil {
+		t.Fatalf("failed to parse output: %v\n%s", err, output)
+	}
+
+	origins := make(map[string]configEntry)
+	for _, e := range entries {
+		origins[e.Key] = e
+	}
+	if e := origins["node.api_port"]; fmt.Sprint(e.Value) != "9700" || e.Origin == nil || e.Origin.Source != "env" {
+		t.Errorf("unexpected node.api_port entry %+v", e)
+	}
+	if e := origins["node.port"]; e.Origin == nil || e.Origin.Source != "default" {
+		t.Errorf("unexpected node.port entry %+v", e)
+	}
+}
+
+func TestConfigSetGet(t *testing.T) {
+	home := isolateConfigDir(t)
+	path := filepath.Join(home, "kinetic", "config.yaml")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{name: "path", args: []string{"config", "path"}, want: path},
+		{name: "set", args: []string{"config", "set", "node.api_port", "9700"}, want: "Set node.api_port = 9700"},
+		{name: "set wrong type", args: []string{"config", "set", "node.api_port", "high"}, wantErr: true},
+		{name: "set unknown key", args: []string{"config", "set", "node.api_prot", "9700"}, wantErr: true},
+		{name: "get", args: []string{"config", "get", "node.api_port"}, want: "9700"},
+		{name: "list", args: []string{"config", "list"}, want: "node.api_port  9700"},
+		{name: "init existing", args: []string{"config", "init"}, wantErr: true},
+		{name: "unset", args: []string{"config", "unset", "node.api_port"}, want: "Removed node.api_port"},
+		{name: "get default", args: []string{"config", "get", "node.api_port"}, want: "9651"},
+		{name: "init force", args: []string{"config", "init", "--force"}, want: "Wrote default config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := testCommand(t, rootCmd, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !strings.Contains(output, tt.want) {
+				t.Errorf("expected output to contain %q, got %q", tt.want, output)
+			}
+		})
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	isolateConfigDir(t)
+	t.Setenv("KINETIC_NODE_NETWORK_ID", "1")
+
+	if _, err := testCommand(t, rootCmd, []string{"subnet", "list"}); err == nil || !strings.Contains(err.Error(), "node.network_id: network ID 1 is mainnet") {
+		t.Errorf("expected invalid config error, got %v", err)
+	}
+	if _, err := testCommand(t, rootCmd, []string{"config", "validate"}); err == nil || !strings.Contains(err.Error(), "KINETIC_NODE_NETWORK_ID") {
+		t.Errorf("expected config validate to fail, got %v", err)
+	}
+
+	// Config commands still run so the problem can be fixed, and a problem
+	// set elsewhere does not block unrelated edits
+	if _, err := testCommand(t, rootCmd, []string{"config", "set", "node.api_port", "9700"}); err != nil {
+		t.Fatalf("config set on an invalid config failed: %v", err)
+	}
+	t.Setenv("KINETIC_NODE_NETWORK_ID", "")
+	os.Unsetenv("KINETIC_NODE_NETWORK_ID")
+	output, err := testCommand(t, rootCmd, []string{"config", "validate"})
+	if err != nil || !strings.Contains(output, "Config is valid") {
+		t.Errorf("expected valid config, got %q, %v", output, err)
+	}
+}
+
+func TestConfigSetRejectsInvalid(t *testing.T) {
+	home := isolateConfigDir(t)
+	path := filepath.Join(home, "kinetic", "config.yaml")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{name: "conflicting port", args: []string{"config", "set", "node.api_port", "9650"}, wantErr: "node.api_port: port 9650 is also used by node.port"},
+		{name: "not saved", args: []string{"config", "list"}, want: "No values set"},
+		{name: "set port", args: []string{"config", "set", "node.port", "9700"}, want: "Set node.port = 9700"},
+		{name: "conflict after set", args: []string{"config", "set", "node.api_port", "9700"}, wantErr: "the config would be invalid"},
+		{name: "api port on former staking port", args: []string{"config", "set", "node.api_port", "9650"}, want: "Set node.api_port = 9650"},
+		{name: "unset refused", args: []string{"config", "unset", "node.port"}, wantErr: "not removing node.port from " + path},
+		{name: "unchanged", args: []string{"config", "get", "node.port"}, want: "9700"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := testCommand(t, rootCmd, tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("command execution failed: %v", err)
+			}
+			if !strings.Contains(output, tt.want) {
+				t.Errorf("expected output to contain %q, got %q", tt.want, output)
+			}
+		})
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected config file after successful sets: %v", err)
+	}
+}
+
+func TestProfileCommands(t *testing.T) {
+	isolateConfigDir(t)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{name: "list empty", args: []string{"profile", "list"}, want: "No profiles defined"},
+		{name: "create", args: []string{"profile", "create", "devnet", "node.api_port=9800", "docker.container_name=devnet-node"}, want: "Created profile devnet"},
+		{name: "create bad value", args: []string{"profile", "create", "fuji", "node.api_port=high"}, wantErr: true},
+		{name: "create fuji", args: []string{"profile", "create", "fuji", "network.name=fuji"}, want: "Created profile fuji"},
+		{name: "use undefined", args: []string{"profile", "use", "staging"}, wantErr: true},
+		{name: "use", args: []string{"profile", "use", "devnet"}, want: "Using profile devnet"},
+		{name: "list", args: []string{"profile", "list"}, want: "*  devnet"},
+		{name: "get from profile", args: []string{"config", "get", "node.api_port"}, want: "9800"},
+		{name: "flag selects profile", args: []string{"--profile", "fuji", "config", "get", "network.name"}, want: "fuji"},
+		{name: "clear", args: []string{"profile", "use", "--clear"}, want: "Cleared the active profile"},
+		{name: "get without profile", args: []string{"config", "get", "node.api_port"}, want: "9651"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := testCommand(t, rootCmd, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !strings.Contains(output, tt.want) {
+				t.Errorf("expected output to contain %q, got %q", tt.want, output)
+			}
+		})
+	}
+}
+
+func TestInitCommand(t *testing.T) {
+	isolateConfigDir(t)
+	root := filepath.Join(t.TempDir(), "my-dapp")
+
+	output, err := testCommand(t, rootCmd, []string{"init", root})
+	if err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if !strings.Contains(output, "kinetic.yaml") || !strings.Contains(output, "git submodule add") {
+		t.Errorf("unexpected init output %q", output)
+	}
+	if _, err := testCommand(t, rootCmd, []string{"init", root}); err == nil {
+		t.Error("expected init of an existing project to fail")
+	}
+
+	// Commands in a subdirectory find the project root
+	nested := filepath.Join(root, "contracts")
+	cwd, _ := os.Getwd()
+	if err := os.Chdir(nested); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(cwd) })
+
+	output, err = testCommand(t, rootCmd, []string{"config", "path", "--project"})
+	if err != nil || strings.TrimSpace(output) != filepath.Join(root, "kinetic.yaml") {
+		t.Errorf("expected the project config, got %q, %v", output, err)
+	}
+	output, err = testCommand(t, rootCmd, []string{"profile", "list"})
+	if err != nil || !strings.Contains(output, "fuji") {
+		t.Errorf("expected the project's fuji profile, got %q, %v", output, err)
+	}
+}
+
+func TestContractCreateInProject(t *testing.T) {
+	isolateConfigDir(t)
+	root := filepath.Join(t.TempDir(), "my-dapp")
+	if _, err := testCommand(t, rootCmd, []string{"init", root}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	cwd, _ := os.Getwd()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(cwd) })
+
+	output, err := testCommand(t, rootCmd, []string{"contract", "create", "ERC20", "Tok", "--output", "json"})
+	if err != nil {
+		t.Fatalf("contract create failed: %v\n%s", err, output)
+	}
+	var result struct {
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, output)
+	}
+	want := filepath.Join(root, "contracts", "Tok.sol")
+	if result.Path != want {
+		t.Errorf("path = %s, want %s", result.Path, want)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Errorf("contract not written: %v", err)
+	}
+}
+
+func TestContractCreateOptions(t *testing.T) {
+	home := isolateConfigDir(t)
+	dir := t.TempDir()
+	configFile := filepath.Join(home, "custom.yaml")
+
+	args := []string{"contract", "create", "ERC20", "Tok", "--output-dir", dir, "--is-mintable", "--config", configFile, "--profile", ""}
+	if output, err := testCommand(t, rootCmd, args); err != nil {
+		t.Fatalf("contract create failed: %v\n%s", err, output)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "Tok.sol"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "function mint(") {
+		t.Errorf("expected --is-mintable to add mint:\n%s", content)
+	}
+
+	// Only the template's own options are passed, not global flags
+	options, err := templateOptions(contractCreateCmd, "ERC20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(options) != 1 || options["IsMintable"] != true {
+		t.Errorf("templateOptions() = %v, want only IsMintable", options)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	isolateConfigDir(t)
+
+	store, err := subnet.DefaultStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(&subnet.Subnet{Name: "alpha", VM: "subnet-evm"}, []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testCommand(t, rootCmd, []string{"profile", "create", "devnet"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		notWant string
+		wantErr bool
+	}{
+		{name: "bash script", args: []string{"completion", "bash"}, want: []string{"bash completion V2 for kinetic"}},
+		{name: "zsh script", args: []string{"completion", "zsh"}, want: []string{"#compdef kinetic"}},
+		{name: "fish script", args: []string{"completion", "fish"}, want: []string{"complete -c kinetic"}},
+		{name: "powershell script", args: []string{"completion", "powershell"}, want: []string{"Register-ArgumentCompleter"}},
+		{name: "unknown shell", args: []string{"completion", "tcsh"}, wantErr: true},
+		{name: "shells", args: []string{"__complete", "completion", ""}, want: []string{"bash", "powershell"}},
+		{name: "subnet names", args: []string{"__complete", "subnet", "describe", ""}, want: []string{"alpha\tsubnet-evm"}},
+		{name: "subnet name given", args: []string{"__complete", "subnet", "describe", "alpha", ""}, notWant: "alpha"},
+		{name: "relayer destination", args: []string{"__complete", "subnet", "relayer", "alpha", "a"}, want: []string{"alpha"}},
+		{name: "subnet config action", args: []string{"__complete", "subnet", "config", "alpha", "s"}, want: []string{"show", "set"}},
+		{name: "subnet config keys", args: []string{"__complete", "subnet", "config", "alpha", "set", "pruning"}, want: []string{"pruning-enabled="}},
+		{name: "networks", args: []string{"__complete", "subnet", "deploy", "alpha", "--network", ""}, want: []string{"local", "fuji", "mainnet"}},
+		{name: "runtimes", args: []string{"__complete", "node", "start", "--runtime", "p"}, want: []string{"podman"}, notWant: "docker"},
+		{name: "profiles", args: []string{"__complete", "--profile", ""}, want: []string{"devnet"}},
+		{name: "profile use", args: []string{"__complete", "profile", "use", "d"}, want: []string{"devnet"}},
+		{name: "config keys", args: []string{"__complete", "config", "get", "node.api"}, want: []string{"node.api_port"}, notWant: "version"},
+		{name: "config bool value", args: []string{"__complete", "config", "set", "compiler.optimizer", ""}, want: []string{"true", "false"}},
+		{name: "config network value", args: []string{"__complete", "config", "set", "network.name", "f"}, want: []string{"fuji"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := testCommand(t, rootCmd, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("expected output to contain %q, got %q", want, output)
+				}
+			}
+			if tt.notWant != "" && strings.Contains(output, tt.notWant) {
+				t.Errorf("expected output not to contain %q, got %q", tt.notWant, output)
+			}
+		})
+	}
+}
+
+func TestVersionCommand(t *testing.T) {
+	isolateConfigDir(t)
+
+	// Point the node API at a closed port, so no node is found
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	l.Close()
+	t.Setenv("KINETIC_NODE_API_PORT", port)
+
+	output, err := testCommand(t, rootCmd, []string{"version", "--output", "json"})
+	if err != nil {
+		t.Fatalf("command execution failed: %v", err)
+	}
+	var result struct {
+		Kinetic struct {
+			Version string `json:"version"`
+		} `json:"kinetic"`
+		Node *struct{} `json:"node"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, output)
+	}
+	if result.Kinetic.Version == "" || result.Node != nil {
+		t.Errorf("unexpected version output: %s", output)
+	}
+
+	if output, err := testCommand(t, rootCmd, []string{"--version"}); err != nil || !strings.Contains(output, "kinetic version") {
+		t.Errorf("--version output = %q, err = %v", output, err)
+	}
+}
+
+func TestErrorsOmitUsage(t *testing.T) {
+	isolateConfigDir(t)
+	t.Setenv("KINETIC_NODE_NETWORK_ID", "1")
+
+	output, err := testCommand(t, rootCmd, []string{"node", "status"})
+	if err == nil {
+		t.Fatal("expected an invalid config error")
+	}
+	if strings.Contains(output, "Usage:") {
+		t.Errorf("runtime errors should not print usage:\n%s", output)
+	}
+}
+
+func TestValidatorsRequireNodeIDs(t *testing.T) {
+	isolateConfigDir(t)
+
+	for _, action := range []string{"add", "remove"} {
+		_, err := testCommand(t, rootCmd, []string{"subnet", "validators", action, "mysubnet"})
+		if !errors.Is(err, subnet.ErrNoNodeIDs) || !strings.Contains(err.Error(), "--node-id") {
+			t.Errorf("validators %s without --node-id: expected node ID guidance, got %v", action, err)
+		}
+	}
+}
+
+func TestDoctorCommand(t *testing.T) {
+	isolateConfigDir(t)
+	// An invalid config is reported by doctor rather than stopping it
+	t.Setenv("KINETIC_NODE_NETWORK_ID", "1")
+
+	output, err := testCommand(t, rootCmd, []string{"doctor", "--output", "json"})
+	if err == nil || !strings.Contains(err.Error(), "checks failed") {
+		t.Errorf("expected failed checks, got %v", err)
+	}
+	if strings.Contains(output, "Usage:") {
+		t.Errorf("failed checks should not print usage:\n%s", output)
+	}
+	var result struct {
+		Checks []struct {
+			Name   string `json:"name"`
+			Status string `json:"status"`
+			Hint   string `json:"hint"`
+		} `json:"checks"`
+		Failures int `json:"failures"`
+	}
+	if err := json.NewDecoder(strings.NewReader(output)).Decode(&result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, output)
+	}
+	if len(result.Checks) == 0 || result.Checks[0].Name != "config" || result.Checks[0].Status != "fail" || result.Checks[0].Hint == "" {
+		t.Errorf("expected a failed config check first, got %s", output)
+	}
+	if result.Failures == 0 {
+		t.Errorf("expected failures to be counted, got %s", output)
+	}
+}
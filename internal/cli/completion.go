@@ -0,0 +1,260 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/contracts"
+	"github.com/kinetic-dev/kinetic/internal/subnet"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate a shell completion script",
+	Long: `Generate a completion script for your shell. Besides commands and flags,
+Kinetic completes contract templates and their options, networks, subnet
+names, profiles and config keys from your current setup.
+
+Bash (needs the bash-completion package):
+  source <(kinetic completion bash)
+  # or, for every session on Linux:
+  kinetic completion bash > /etc/bash_completion.d/kinetic
+
+Zsh:
+  # enable completion once, if it is not already:
+  echo "autoload -U compinit; compinit" >> ~/.zshrc
+  kinetic completion zsh > "${fpath[1]}/_kinetic"
+
+Fish:
+  kinetic completion fish > ~/.config/fish/completions/kinetic.fish
+
+PowerShell:
+  kinetic completion powershell | Out-String | Invoke-Expression
+  # add the line above to your $PROFILE for every session
+
+Start a new shell for the completions to take effect.`,
+	DisableFlagsInUseLine: true,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	RunE:                  runCompletion,
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+	root := cmd.Root()
+	switch args[0] {
+	case "bash":
+		return root.GenBashCompletionV2(out, true)
+	case "zsh":
+		return root.GenZshCompletion(out)
+	case "fish":
+		return root.GenFishCompletion(out, true)
+	case "powershell":
+		return root.GenPowerShellCompletionWithDesc(out)
+	}
+	return fmt.Errorf("unsupported shell %q", args[0])
+}
+
+// completes reports whether cmd generates or answers shell completions,
+// which must work even when the config is invalid
+func completes(cmd *cobra.Command) bool {
+	return cmd == completionCmd || cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd
+}
+
+// completionConfig loads the config for a completion function, which runs
+// without the usual config loading. Validation problems are ignored.
+func completionConfig(cmd *cobra.Command) *config.Config {
+	configFile, _ := cmd.Flags().GetString("config")
+	cfg, _ := config.LoadWithOptions(config.LoadOptions{ConfigFile: configFile})
+	if cfg == nil {
+		return config.DefaultConfig()
+	}
+	return cfg
+}
+
+// filterCompletions keeps the completions, written as value or
+// value\tdescription, that start with prefix
+func filterCompletions(completions []string, prefix string) []string {
+	var matches []string
+	for _, c := range completions {
+		if strings.HasPrefix(c, prefix) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+// completeValues returns a completion function for a fixed set of values
+func completeValues(values ...string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return filterCompletions(values, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeNetworks completes the networks deploy commands target
+var completeNetworks = completeValues(subnet.Networks...)
+
+// completeRuntimes completes the node runtimes
+var completeRuntimes = completeValues(config.Runtimes...)
+
+// subnetNames returns the names of the defined subnets with their VMs
+func subnetNames() []string {
+	store, err := subnet.DefaultStore()
+	if err != nil {
+		return nil
+	}
+	subnets, err := store.List()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(subnets))
+	for _, sn := range subnets {
+		names = append(names, sn.Name+"\t"+sn.VM)
+	}
+	return names
+}
+
+// completeSubnetArgs returns a completion function for commands whose first
+// n arguments are subnet names
+func completeSubnetArgs(n int) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= n {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return filterCompletions(subnetNames(), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeSubnetConfig completes the subnet, the action and the chain config
+// keys of subnet config
+func completeSubnetConfig(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch {
+	case len(args) == 0:
+		return filterCompletions(subnetNames(), toComplete), cobra.ShellCompDirectiveNoFileComp
+	case len(args) == 1:
+		return filterCompletions([]string{"show", "set", "unset", "keys"}, toComplete), cobra.ShellCompDirectiveNoFileComp
+	case args[1] == "set":
+		var keys []string
+		for key, k := range subnet.SubnetEVMConfigKeys {
+			keys = append(keys, key+"=\t"+k.Description)
+		}
+		sort.Strings(keys)
+		return filterCompletions(keys, toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	case args[1] == "unset":
+		cfg, err := loadChainConfig(args[0])
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return filterCompletions(subnet.ConfigKeys(cfg), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// loadChainConfig reads the chain config of a subnet
+func loadChainConfig(name string) (map[string]any, error) {
+	store, err := subnet.DefaultStore()
+	if err != nil {
+		return nil, err
+	}
+	return subnet.LoadChainConfig(store, name)
+}
+
+// profileNames returns the profiles defined in the config files
+func profileNames(cfg *config.Config) []string {
+	var names []string
+	for _, p := range cfg.Profiles() {
+		names = append(names, p.Name+"\t"+strings.Join(p.Files, ", "))
+	}
+	return names
+}
+
+// completeProfiles completes profile names
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return filterCompletions(profileNames(completionConfig(cmd)), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeConfigArgs completes the key of config get, set and unset, and
+// the value of config set where the key has a known set of values
+func completeConfigArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		var keys []string
+		for _, key := range config.Keys() {
+			if key != "version" {
+				keys = append(keys, key)
+			}
+		}
+		return filterCompletions(keys, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+	if cmd.Name() != "set" || len(args) > 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var values []string
+	switch key := args[0]; key {
+	case "profile":
+		values = profileNames(completionConfig(cmd))
+	case "node.runtime":
+		values = config.Runtimes
+	case "network.name":
+		values = subnet.Networks
+	default:
+		value, ok := config.DefaultConfig().Value(key)
+		if !ok {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		if reflect.TypeOf(value).Kind() == reflect.Bool {
+			values = []string{"true", "false"}
+		} else if strings.HasSuffix(key, "_dir") || strings.HasSuffix(key, "_path") {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+	}
+	return filterCompletions(values, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeContractCreate completes the template names from the template
+// config, then, once the contract is named, the option flags of the template
+func completeContractCreate(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	templates, err := contracts.LoadTemplateConfig()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	switch len(args) {
+	case 0:
+		var names []string
+		for _, name := range templates.TemplateNames() {
+			names = append(names, name+"\t"+templates.Templates[name].Description)
+		}
+		return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		// The contract name is free form
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	tmpl, ok := templates.Templates[args[0]]
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var flags []string
+	for option, opt := range tmpl.Options {
+		flag := cmd.Flags().Lookup(contracts.OptionFlag(option))
+		if flag == nil || flag.Changed {
+			continue
+		}
+		flags = append(flags, "--"+flag.Name+"\t"+opt.Description)
+	}
+	sort.Strings(flags)
+	return filterCompletions(flags, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	// completionCmd replaces cobra's default, documenting every shell
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+}
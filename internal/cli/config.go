@@ -0,0 +1,482 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage Kinetic configuration",
+	Long: `Commands for the layered Kinetic configuration: built-in defaults, the user
+config file, the project kinetic.yaml, KINETIC_* environment variables and
+flags.
+
+get and show report effective values. set, unset, list, edit, path and init
+operate on the user config file (or the file given with --config), or on the
+project kinetic.yaml with --project. Keys are dotted, e.g. node.api_port.`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:               "get [key]",
+	Short:             "Print the effective value of a config key",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeConfigArgs,
+	RunE:              runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "Set a config key in the config file",
+	Long: `Set a config key in the config file. The value is checked against the key's
+type; lists are comma separated. A value that would make the merged config
+invalid, such as an API port equal to the staking port, is refused and the
+file left as it was.
+
+Example:
+  kinetic config set node.api_port 9700
+  kinetic config set node.track_subnets subnetA,subnetB
+  kinetic config set docker.container_name team-node --project`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeConfigArgs,
+	RunE:              runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset [key]",
+	Short: "Remove a config key from the config file",
+	Long: `Remove a config key from the config file, so a later layer or the default
+applies. The removal is refused if it would make the merged config invalid.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeConfigArgs,
+	RunE:              runConfigUnset,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the values set in the config file",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigList,
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in an editor",
+	Long: `Open the config file in $VISUAL or $EDITOR, creating it with the commented
+defaults if it does not exist. The file is validated after editing.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigEdit,
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the config file",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigPath,
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a commented config file with the defaults",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigInit,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the effective configuration for problems",
+	Long: `Check the effective configuration: port ranges and conflicts, the network ID,
+the runtime, the node image and container name, and that the database, log and
+staking directories differ. Every problem is listed with the key and the file,
+environment variable or flag that set it.
+
+'kinetic doctor' also checks that the ports are free and the node
+directories writable.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigValidate,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective configuration",
+	Long: `Show every config value after all layers are applied. With --origin, also
+show the layer each value came from and the file, environment variable or
+flag that set it.
+
+Example:
+  kinetic config show --origin
+  KINETIC_NODE_API_PORT=9700 kinetic config show --origin`,
+	Args: cobra.NoArgs,
+	RunE: runConfigShow,
+}
+
+// configEntry is a config value in the structured output of config show
+type configEntry struct {
+	Key    string         `json:"key"`
+	Value  any            `json:"value"`
+	Origin *config.Origin `json:"origin,omitempty"`
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	cfg := config.Get()
+	withOrigin, _ := cmd.Flags().GetBool("origin")
+
+	var entries []configEntry
+	for _, key := range config.Keys() {
+		value, _ := cfg.Value(key)
+		entry := configEntry{Key: key, Value: value}
+		if withOrigin {
+			origin := cfg.Origin(key)
+			entry.Origin = &origin
+		}
+		entries = append(entries, entry)
+	}
+
+	return printResult(cmd, entries, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, e := range entries {
+			fmt.Fprintf(tw, "%s\t%s", e.Key, formatConfigValue(e.Value))
+			if e.Origin != nil {
+				fmt.Fprintf(tw, "\t%s", e.Origin.Source)
+				if e.Origin.Profile != "" {
+					fmt.Fprintf(tw, " %s", e.Origin.Profile)
+				}
+				if e.Origin.Location != "" {
+					fmt.Fprintf(tw, " (%s)", e.Origin.Location)
+				}
+			}
+			fmt.Fprintln(tw)
+		}
+		tw.Flush()
+	})
+}
+
+// formatConfigValue renders a config value for humans, quoting empty strings
+// and showing lists as JSON
+func formatConfigValue(v any) string {
+	switch value := v.(type) {
+	case string:
+		if value == "" {
+			return `""`
+		}
+		return value
+	case []string:
+		if len(value) == 0 {
+			return "[]"
+		}
+		data, _ := json.Marshal(value)
+		return string(data)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	if err := config.Get().Validate(); err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "Config is valid")
+	return nil
+}
+
+// configTarget returns the config file the config commands edit: the project
+// config with --project, found from the project root or else the working
+// directory, otherwise the --config or user config file
+func configTarget(cmd *cobra.Command) (string, error) {
+	if project, _ := cmd.Flags().GetBool("project"); project {
+		dir, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get working directory: %w", err)
+		}
+		if root := config.FindProjectRoot(dir); root != "" {
+			dir = root
+		}
+		return config.ProjectConfigFile(dir), nil
+	}
+	return config.Get().Path()
+}
+
+// configValueOutput is the structured result of config get and set
+type configValueOutput struct {
+	Key    string        `json:"key"`
+	Value  any           `json:"value"`
+	Origin config.Origin `json:"origin"`
+	Path   string        `json:"path,omitempty"`
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	cfg := config.Get()
+	value, ok := cfg.Value(key)
+	if !ok {
+		return fmt.Errorf("unknown config key %s", key)
+	}
+
+	result := configValueOutput{Key: key, Value: value, Origin: cfg.Origin(key)}
+	return printResult(cmd, result, func(w io.Writer) {
+		fmt.Fprintln(w, formatConfigValue(value))
+	})
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	value, err := config.ParseValue(key, args[1])
+	if err != nil {
+		return err
+	}
+	path, err := configTarget(cmd)
+	if err != nil {
+		return err
+	}
+	err = checkedEdit(cmd, path, func() error {
+		return config.SetFileValue(path, key, value)
+	})
+	if err != nil {
+		return fmt.Errorf("not setting %s = %s in %s: %w", key, formatConfigValue(value), path, err)
+	}
+
+	origin := config.Get().Origin(key)
+	result := configValueOutput{Key: key, Value: value, Origin: origin, Path: path}
+	return printResult(cmd, result, func(w io.Writer) {
+		fmt.Fprintf(w, "Set %s = %s in %s\n", key, formatConfigValue(value), path)
+		if overridden(cmd, origin) {
+			fmt.Fprintf(w, "Note: %s is currently overridden by %s (%s)\n", key, origin.Source, origin.Location)
+		}
+	})
+}
+
+// checkedEdit applies edit to the config file at path and reloads the config.
+// If the merged config has problems it did not have before, the file is
+// restored and the new problems returned, so an edit cannot leave a config
+// that fails validation.
+func checkedEdit(cmd *cobra.Command, path string, edit func() error) error {
+	known := make(map[string]bool)
+	for _, problem := range validationErrors(config.Get().Validate()) {
+		known[problem.Key+": "+problem.Message] = true
+	}
+
+	original, readErr := os.ReadFile(path)
+	if readErr != nil && !os.IsNotExist(readErr) {
+		return fmt.Errorf("failed to read config file: %w", readErr)
+	}
+	if err := edit(); err != nil {
+		return err
+	}
+
+	_, loadErr := config.LoadWithOptions(loadOptions(cmd))
+	invalid := validationErrors(loadErr)
+	var problems []string
+	for _, problem := range invalid {
+		if !known[problem.Key+": "+problem.Message] {
+			problems = append(problems, "  "+problem.Error())
+		}
+	}
+	if len(problems) == 0 && (loadErr == nil || len(invalid) > 0) {
+		return nil
+	}
+
+	var err error
+	if os.IsNotExist(readErr) {
+		err = os.Remove(path)
+	} else {
+		err = os.WriteFile(path, original, 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore config file: %w", err)
+	}
+	config.LoadWithOptions(loadOptions(cmd))
+	if len(problems) == 0 {
+		return fmt.Errorf("the config would not load: %w", loadErr)
+	}
+	return fmt.Errorf("the config would be invalid:\n%s", strings.Join(problems, "\n"))
+}
+
+// validationErrors returns the problems listed by a Validate or load error
+func validationErrors(err error) config.ValidationErrors {
+	var invalid config.ValidationErrors
+	errors.As(err, &invalid)
+	return invalid
+}
+
+// overridden reports whether a value's origin takes precedence over the
+// config file being edited
+func overridden(cmd *cobra.Command, origin config.Origin) bool {
+	switch origin.Source {
+	case config.SourceProfile, config.SourceEnv, config.SourceFlag:
+		return true
+	case config.SourceProject:
+		project, _ := cmd.Flags().GetBool("project")
+		return !project
+	default:
+		return false
+	}
+}
+
+// configUnsetOutput is the structured result of config unset
+type configUnsetOutput struct {
+	Key     string `json:"key"`
+	Path    string `json:"path"`
+	Removed bool   `json:"removed"`
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	if _, ok := config.Get().Value(key); !ok {
+		return fmt.Errorf("unknown config key %s", key)
+	}
+	path, err := configTarget(cmd)
+	if err != nil {
+		return err
+	}
+	var removed bool
+	err = checkedEdit(cmd, path, func() (err error) {
+		removed, err = config.UnsetFileValue(path, key)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("not removing %s from %s: %w", key, path, err)
+	}
+
+	result := configUnsetOutput{Key: key, Path: path, Removed: removed}
+	return printResult(cmd, result, func(w io.Writer) {
+		if !removed {
+			fmt.Fprintf(w, "%s is not set in %s\n", key, path)
+			return
+		}
+		fmt.Fprintf(w, "Removed %s from %s\n", key, path)
+	})
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	path, err := configTarget(cmd)
+	if err != nil {
+		return err
+	}
+	values, err := config.FileValues(path)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	entries := make([]configEntry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, configEntry{Key: key, Value: values[key]})
+	}
+
+	return printResult(cmd, entries, func(w io.Writer) {
+		if len(entries) == 0 {
+			fmt.Fprintf(w, "No values set in %s\n", path)
+			return
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, e := range entries {
+			fmt.Fprintf(tw, "%s\t%s\n", e.Key, formatConfigValue(e.Value))
+		}
+		tw.Flush()
+	})
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	path, err := configTarget(cmd)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := config.WriteDefaultFile(path); err != nil {
+			return err
+		}
+	}
+
+	editor := strings.Fields(os.Getenv("VISUAL"))
+	if len(editor) == 0 {
+		editor = strings.Fields(os.Getenv("EDITOR"))
+	}
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+		if runtime.GOOS == "windows" {
+			editor = []string{"notepad"}
+		}
+	}
+
+	edit := exec.CommandContext(cmd.Context(), editor[0], append(editor[1:], path)...)
+	edit.Stdin, edit.Stdout, edit.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := edit.Run(); err != nil {
+		return fmt.Errorf("failed to run editor %s: %w", editor[0], err)
+	}
+
+	if err := config.ValidateFile(path); err != nil {
+		return fmt.Errorf("config file is invalid; run 'kinetic config edit' again to fix it: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Saved %s\n", path)
+	return nil
+}
+
+// configPathOutput is the structured result of config path
+type configPathOutput struct {
+	Path   string `json:"path"`
+	Exists bool   `json:"exists"`
+}
+
+func runConfigPath(cmd *cobra.Command, args []string) error {
+	path, err := configTarget(cmd)
+	if err != nil {
+		return err
+	}
+	_, statErr := os.Stat(path)
+
+	result := configPathOutput{Path: path, Exists: statErr == nil}
+	return printResult(cmd, result, func(w io.Writer) {
+		fmt.Fprintln(w, path)
+	})
+}
+
+func runConfigInit(cmd *cobra.Command, args []string) error {
+	path, err := configTarget(cmd)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		if force, _ := cmd.Flags().GetBool("force"); !force {
+			return fmt.Errorf("config file %s already exists; use --force to overwrite it", path)
+		}
+	}
+	if err := config.WriteDefaultFile(path); err != nil {
+		return err
+	}
+
+	result := configPathOutput{Path: path, Exists: true}
+	return printResult(cmd, result, func(w io.Writer) {
+		fmt.Fprintf(w, "Wrote default config to %s\n", path)
+	})
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configValidateCmd)
+
+	configCmd.PersistentFlags().Bool("project", false, "Use the project kinetic.yaml instead of the user config file")
+	configShowCmd.Flags().Bool("origin", false, "Show where each value came from")
+	configInitCmd.Flags().BoolP("force", "f", false, "Overwrite an existing config file")
+}
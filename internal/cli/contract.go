@@ -2,11 +2,12 @@ package cli
 
 import (
 	"fmt"
-	"strings"
+	"io"
+	"strconv"
 
 	"github.com/kinetic-dev/kinetic/internal/contracts"
+	"github.com/kinetic-dev/kinetic/internal/project"
 	"github.com/spf13/cobra"
-	"github.com/spf13/pflag"
 )
 
 var contractCmd = &cobra.Command{
@@ -25,8 +26,9 @@ Example:
   kinetic contract create ERC20 MyToken --output-dir ./contracts
   kinetic contract create ERC721 MyNFT --output-dir ./src/contracts --has-max-supply
   kinetic contract create Basic MyContract --output-dir ./solidity`,
-	Args: cobra.ExactArgs(2),
-	RunE: runContractCreate,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeContractCreate,
+	RunE:              runContractCreate,
 }
 
 var contractDeployCmd = &cobra.Command{
@@ -35,13 +37,28 @@ var contractDeployCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		contract := args[0]
-		network, _ := cmd.Flags().GetString("network")
-		fmt.Printf("Deploying contract '%s' to '%s'...\n", contract, network)
+		network := targetNetwork()
 		// TODO: Implement contract deployment logic
-		return nil
+		result := contractDeployOutput{Contract: contract, Network: network}
+		return printResult(cmd, result, func(w io.Writer) {
+			fmt.Fprintf(w, "Deploying contract '%s' to '%s'...\n", contract, network)
+		})
 	},
 }
 
+// contractCreateOutput is the structured result of contract create
+type contractCreateOutput struct {
+	Template string `json:"template"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+}
+
+// contractDeployOutput is the structured result of contract deploy
+type contractDeployOutput struct {
+	Contract string `json:"contract"`
+	Network  string `json:"network"`
+}
+
 func runContractCreate(cmd *cobra.Command, args []string) error {
 	templateName := args[0]
 	contractName := args[1]
@@ -52,13 +69,10 @@ func runContractCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get output directory flag: %w", err)
 	}
 
-	// Collect template flags
-	templateFlags := make(map[string]interface{})
-	cmd.Flags().Visit(func(f *pflag.Flag) {
-		if f.Name != "output-dir" {
-			templateFlags[camelCase(f.Name)] = f.Value
-		}
-	})
+	templateFlags, err := templateOptions(cmd, templateName)
+	if err != nil {
+		return err
+	}
 
 	// Create contract using the contracts package
 	opts := contracts.CreateOptions{
@@ -68,20 +82,62 @@ func runContractCreate(cmd *cobra.Command, args []string) error {
 		TemplateFlags: templateFlags,
 	}
 
+	// Inside a project, contracts go to its contracts directory and relative
+	// output directories resolve against its root
+	if p, err := project.Current(); err == nil {
+		opts.BaseDir = p.Root
+		if opts.OutputDir == "" {
+			opts.OutputDir = project.ContractsDir
+		}
+	}
+
 	if err := contracts.Create(opts); err != nil {
 		return err
 	}
 
-	fmt.Printf("Contract created successfully: %s\n", contractName)
-	return nil
+	path, err := contracts.OutputPath(opts)
+	if err != nil {
+		return err
+	}
+
+	result := contractCreateOutput{Template: templateName, Name: contractName, Path: path}
+	return printResult(cmd, result, func(w io.Writer) {
+		fmt.Fprintf(w, "Contract created successfully: %s\n", contractName)
+	})
 }
 
-func camelCase(s string) string {
-	parts := strings.Split(s, "-")
-	for i := 1; i < len(parts); i++ {
-		parts[i] = strings.Title(parts[i])
+// templateOptions returns the options of a template set by its flags, keyed
+// by option name. Only the command's own flags that match an option of the
+// template are used, so global flags such as --config never reach it.
+func templateOptions(cmd *cobra.Command, templateName string) (map[string]interface{}, error) {
+	templates, err := contracts.LoadTemplateConfig()
+	if err != nil {
+		return nil, err
+	}
+	options := make(map[string]interface{})
+	// Create reports an unknown template
+	tmpl, ok := templates.Templates[templateName]
+	if !ok {
+		return options, nil
+	}
+
+	flags := cmd.LocalFlags()
+	for option := range tmpl.Options {
+		f := flags.Lookup(contracts.OptionFlag(option))
+		if f == nil || !f.Changed {
+			continue
+		}
+		if f.Value.Type() != "bool" {
+			options[option] = f.Value.String()
+			continue
+		}
+		value, err := strconv.ParseBool(f.Value.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --%s: %w", f.Name, err)
+		}
+		options[option] = value
 	}
-	return strings.Join(parts, "")
+	return options, nil
 }
 
 func init() {
@@ -89,7 +145,7 @@ func init() {
 	contractCmd.AddCommand(contractDeployCmd)
 
 	// Update output directory flag description
-	contractCreateCmd.Flags().StringP("output-dir", "o", "", "Output directory for generated contracts (default: current directory)")
+	contractCreateCmd.Flags().StringP("output-dir", "o", "", "Output directory for generated contracts, relative to the project root inside a project (default: the project's contracts/, or the current directory)")
 
 	// Add template-specific flags
 	contractCreateCmd.Flags().Bool("has-cap", false, "Add maximum supply cap (ERC20)")
@@ -109,6 +165,9 @@ func init() {
 	contractCreateCmd.Flags().Bool("is-pausable", false, "Allow pausing transfers")
 	contractCreateCmd.Flags().Bool("only-owner-can-mint", true, "Only owner can mint tokens (ERC721)")
 
-	contractDeployCmd.Flags().StringP("network", "n", "local", "Target network (local, fuji, mainnet)")
-	contractDeployCmd.Flags().StringP("private-key", "k", "", "Private key for deployment")
+	contractDeployCmd.Flags().StringP("network", "n", "", "Target network: local, fuji or mainnet (default is network.name, local unless configured)")
+	contractDeployCmd.Flags().StringP("private-key", "k", "", "Private key for deployment (default is network.default_key)")
+	bindConfigFlag(contractDeployCmd.Flags(), "network", "network.name")
+	bindConfigFlag(contractDeployCmd.Flags(), "private-key", "network.default_key")
+	contractDeployCmd.RegisterFlagCompletionFunc("network", completeNetworks)
 }
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/doctor"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that this machine can run Kinetic and a local node",
+	Long: `Check the environment Kinetic needs: the config, the container daemon
+(or native avalanchego binary) and the node image, free node ports, disk
+space (20GB) and memory (4GB, 8GB recommended), writable data directories
+and solc. Each check passes, warns or fails, with a hint to fix it.
+
+Exits with an error if any check fails.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+// doctorOutput is the structured result of doctor
+type doctorOutput struct {
+	Checks   []doctor.Result `json:"checks"`
+	Warnings int             `json:"warnings"`
+	Failures int             `json:"failures"`
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	result := doctorOutput{Checks: doctor.New(config.Get()).Run(cmd.Context())}
+	for _, check := range result.Checks {
+		switch check.Status {
+		case doctor.StatusWarn:
+			result.Warnings++
+		case doctor.StatusFail:
+			result.Failures++
+		}
+	}
+
+	err := printResult(cmd, result, func(w io.Writer) {
+		for _, check := range result.Checks {
+			fmt.Fprintf(w, "%-4s  %-14s %s\n", strings.ToUpper(string(check.Status)), check.Name, check.Message)
+			if check.Hint != "" {
+				fmt.Fprintf(w, "      %-14s fix: %s\n", "", check.Hint)
+			}
+		}
+		fmt.Fprintf(w, "\n%d checks, %d warnings, %d failures\n", len(result.Checks), result.Warnings, result.Failures)
+	})
+	if err != nil {
+		return err
+	}
+	if result.Failures > 0 {
+		return fmt.Errorf("%d of %d checks failed", result.Failures, len(result.Checks))
+	}
+	return nil
+}
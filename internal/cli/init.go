@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kinetic-dev/kinetic/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init [dir]",
+	Short: "Create a Kinetic project",
+	Long: `Create a project in dir (default the working directory) with contracts/,
+scripts/, test/ and deployments/ directories, a kinetic.yaml with network and
+compiler settings, a .gitignore and remappings.txt mapping OpenZeppelin imports
+to lib/.
+
+Commands run anywhere inside the project use its root: contract create writes
+to contracts/ and deployments are recorded under deployments/.
+
+Example:
+  kinetic init my-dapp`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runInit,
+}
+
+// initOutput is the structured result of init
+type initOutput struct {
+	Root    string   `json:"root"`
+	Created []string `json:"created"`
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	force, _ := cmd.Flags().GetBool("force")
+
+	p, created, err := project.Init(project.InitOptions{Dir: dir, Force: force})
+	if err != nil {
+		return err
+	}
+
+	result := initOutput{Root: p.Root, Created: created}
+	return printResult(cmd, result, func(w io.Writer) {
+		fmt.Fprintf(w, "Created project in %s\n", p.Root)
+		for _, path := range created {
+			fmt.Fprintf(w, "  %s\n", p.Rel(path))
+		}
+		fmt.Fprintln(w, "\nVendor OpenZeppelin with:")
+		fmt.Fprintf(w, "  %s\n", project.VendorOpenZeppelin)
+	})
+}
+
+func init() {
+	initCmd.Flags().BoolP("force", "f", false, "Overwrite an existing kinetic.yaml and remappings.txt")
+}
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"fmt"
+	"io"
+	"sort"
 	"time"
 
 	"github.com/kinetic-dev/kinetic/internal/config"
@@ -33,39 +35,68 @@ var nodeStatusCmd = &cobra.Command{
 	RunE:  runNodeStatus,
 }
 
+var nodeUpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade the node to a specific avalanchego version",
+	Long: `Install the given avalanchego version and pin the node to it. Container
+runtimes pin the image digest; the native runtime downloads the release binary.
+If the node is running it is restarted on the new version.
+
+Example:
+  kinetic node upgrade --version v1.11.3`,
+	RunE: runNodeUpgrade,
+}
+
 func runNodeStart(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 	cfg := config.Get()
 
-	// Override config with flags if provided
-	if apiPort, _ := cmd.Flags().GetInt("api-port"); apiPort != 0 {
-		cfg.Node.APIPort = apiPort
-	}
-	if nodePort, _ := cmd.Flags().GetInt("node-port"); nodePort != 0 {
-		cfg.Node.Port = nodePort
-	}
-
 	manager, err := node.NewManager(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create node manager: %w", err)
 	}
 	defer manager.Close()
 
+	progress := progressWriter(cmd)
+	manager.SetOutput(progress)
+
+	if err := cfg.CheckDirs(); err != nil {
+		return err
+	}
+	// A running node holds its own ports; Start reports that case itself
+	if status, err := manager.Status(ctx); err == nil && !status.IsRunning {
+		if err := cfg.CheckPorts(); err != nil {
+			return err
+		}
+	}
+
 	if err := manager.Start(ctx, cfg); err != nil {
 		return fmt.Errorf("failed to start node: %w", err)
 	}
 
-	fmt.Println("Starting Avalanche node...")
-	fmt.Printf("API endpoint: http://localhost:%d\n", cfg.Node.APIPort)
+	fmt.Fprintln(progress, "Starting Avalanche node...")
+	fmt.Fprintf(progress, "API endpoint: http://localhost:%d\n", cfg.Node.APIPort)
 
 	// Wait for node to become healthy with a 2-minute timeout
-	fmt.Println("Waiting for node to become healthy...")
+	fmt.Fprintln(progress, "Waiting for node to become healthy...")
 	if err := manager.WaitForHealthy(ctx, 2*time.Minute); err != nil {
 		return fmt.Errorf("node failed to become healthy: %w", err)
 	}
 
-	fmt.Println("Node is healthy and ready!")
-	return nil
+	status, err := manager.CheckHealth(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check node status: %w", err)
+	}
+
+	return printResult(cmd, status, func(w io.Writer) {
+		fmt.Fprintln(w, "Node is healthy and ready!")
+	})
+}
+
+// nodeStopOutput is the structured result of node stop
+type nodeStopOutput struct {
+	Stopped bool   `json:"stopped"`
+	Runtime string `json:"runtime"`
 }
 
 func runNodeStop(cmd *cobra.Command, args []string) error {
@@ -82,8 +113,45 @@ func runNodeStop(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to stop node: %w", err)
 	}
 
-	fmt.Println("Node stopped successfully")
-	return nil
+	result := nodeStopOutput{Stopped: true, Runtime: cfg.Node.Runtime}
+	return printResult(cmd, result, func(w io.Writer) {
+		fmt.Fprintln(w, "Node stopped successfully")
+	})
+}
+
+// nodeUpgradeOutput is the structured result of node upgrade
+type nodeUpgradeOutput struct {
+	Version string `json:"version"`
+	Pinned  string `json:"pinned"`
+	Runtime string `json:"runtime"`
+}
+
+func runNodeUpgrade(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg := config.Get()
+
+	version, _ := cmd.Flags().GetString("version")
+
+	manager, err := node.NewManager(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create node manager: %w", err)
+	}
+	defer manager.Close()
+	manager.SetOutput(progressWriter(cmd))
+
+	pinned, err := manager.Upgrade(ctx, version)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade node: %w", err)
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	result := nodeUpgradeOutput{Version: version, Pinned: pinned, Runtime: cfg.Node.Runtime}
+	return printResult(cmd, result, func(w io.Writer) {
+		fmt.Fprintf(w, "Node pinned to %s\n", pinned)
+	})
 }
 
 func runNodeStatus(cmd *cobra.Command, args []string) error {
@@ -101,29 +169,67 @@ func runNodeStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to check node status: %w", err)
 	}
 
-	fmt.Printf("Node Status:\n")
-	fmt.Printf("  Running: %v\n", status.IsRunning)
+	return printResult(cmd, status, func(w io.Writer) {
+		printNodeStatus(w, status)
+	})
+}
+
+// printNodeStatus renders a health status for humans
+func printNodeStatus(w io.Writer, status *node.HealthStatus) {
+	fmt.Fprintf(w, "Node Status:\n")
+	fmt.Fprintf(w, "  Running: %v\n", status.IsRunning)
 	if status.IsRunning {
-		fmt.Printf("  Healthy: %v\n", status.IsHealthy)
-		fmt.Printf("  Bootstrapped: %v\n", status.IsBootstrapped)
-		fmt.Printf("  Version: %s\n", status.Version)
-		fmt.Printf("  Network ID: %d\n", status.NetworkID)
-		fmt.Printf("  API Endpoint: http://localhost:%d\n", cfg.Node.APIPort)
-		fmt.Printf("  Last Checked: %s\n", status.LastChecked.Format(time.RFC3339))
+		fmt.Fprintf(w, "  Healthy: %v\n", status.IsHealthy)
+		fmt.Fprintf(w, "  Bootstrapped: %v\n", status.IsBootstrapped)
+		fmt.Fprintf(w, "  Version: %s\n", status.Version)
+		fmt.Fprintf(w, "  Network ID: %d\n", status.NetworkID)
+		fmt.Fprintf(w, "  API Endpoint: %s\n", status.APIEndpoint)
+		fmt.Fprintf(w, "  Last Checked: %s\n", status.LastChecked.Format(time.RFC3339))
+
+		if len(status.Chains) > 0 {
+			fmt.Fprintf(w, "  Chains:\n")
+			for _, chain := range node.PrimaryChains {
+				fmt.Fprintf(w, "    %s: bootstrapped=%v\n", chain, status.Chains[chain])
+			}
+		}
+
+		if len(status.Checks) > 0 {
+			fmt.Fprintf(w, "  Health Checks:\n")
+			names := make([]string, 0, len(status.Checks))
+			for name := range status.Checks {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				check := status.Checks[name]
+				if check.Healthy {
+					fmt.Fprintf(w, "    %s: ok\n", name)
+				} else {
+					fmt.Fprintf(w, "    %s: FAILING (%s)\n", name, check.Error)
+				}
+			}
+		}
 	}
 	if status.Error != "" {
-		fmt.Printf("  Error: %s\n", status.Error)
+		fmt.Fprintf(w, "  Error: %s\n", status.Error)
 	}
-
-	return nil
 }
 
 func init() {
 	nodeCmd.AddCommand(nodeStartCmd)
 	nodeCmd.AddCommand(nodeStopCmd)
 	nodeCmd.AddCommand(nodeStatusCmd)
+	nodeCmd.AddCommand(nodeUpgradeCmd)
 
 	// Add flags
+	nodeCmd.PersistentFlags().String("runtime", "", "Node runtime (docker, podman, native)")
 	nodeStartCmd.Flags().IntP("node-port", "p", 9650, "Node port")
 	nodeStartCmd.Flags().IntP("api-port", "a", 9651, "API port")
+	bindConfigFlag(nodeCmd.PersistentFlags(), "runtime", "node.runtime")
+	nodeCmd.RegisterFlagCompletionFunc("runtime", completeRuntimes)
+	bindConfigFlag(nodeStartCmd.Flags(), "node-port", "node.port")
+	bindConfigFlag(nodeStartCmd.Flags(), "api-port", "node.api_port")
+
+	nodeUpgradeCmd.Flags().String("version", "", "avalanchego version to install (e.g. v1.11.3)")
+	nodeUpgradeCmd.MarkFlagRequired("version")
 }
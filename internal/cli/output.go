@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by the global --output flag
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// outputFormat returns the selected output format, defaulting to text for
+// commands mounted outside the root command
+func outputFormat(cmd *cobra.Command) (string, error) {
+	format, err := cmd.Flags().GetString("output")
+	if err != nil || format == "" {
+		return outputText, nil
+	}
+	switch format {
+	case outputText, outputJSON, outputYAML:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid output format %q (supported: text, json, yaml)", format)
+	}
+}
+
+// isMachineOutput reports whether a structured output format was selected
+func isMachineOutput(cmd *cobra.Command) bool {
+	format, _ := outputFormat(cmd)
+	return format != outputText
+}
+
+// progressWriter returns where progress messages should go, keeping stdout
+// clean for structured output
+func progressWriter(cmd *cobra.Command) io.Writer {
+	if isMachineOutput(cmd) {
+		return cmd.ErrOrStderr()
+	}
+	return cmd.OutOrStdout()
+}
+
+// printResult writes v in the selected output format. text renders the
+// human-readable form.
+func printResult(cmd *cobra.Command, v any, text func(w io.Writer)) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case outputJSON:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case outputYAML:
+		data, err := toYAML(v)
+		if err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+		fmt.Fprint(out, string(data))
+	default:
+		text(out)
+	}
+	return nil
+}
+
+// toYAML encodes v as YAML using its JSON field names and ordering, so both
+// structured formats share one schema
+func toYAML(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	clearStyle(&node)
+
+	return yaml.Marshal(&node)
+}
+
+// clearStyle switches a node tree decoded from JSON to block style
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage config profiles",
+	Long: `Profiles are named sets of config values, such as a personal local node, a
+shared team devnet and Fuji, kept under profiles.<name> in the user config file
+or the project kinetic.yaml. The active profile, chosen with --profile,
+KINETIC_PROFILE or 'kinetic profile use', overrides the config files;
+environment variables and flags still override the profile.
+
+Example:
+  kinetic profile create devnet node.api_port=9700 docker.container_name=devnet-node
+  kinetic profile use devnet
+  kinetic --profile fuji contract deploy MyToken`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the defined profiles",
+	Args:  cobra.NoArgs,
+	RunE:  runProfileList,
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use [name]",
+	Short: "Set the active profile in the config file",
+	Long: `Set the active profile in the config file, or clear it with --clear. The
+--profile flag and KINETIC_PROFILE still take precedence.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProfiles,
+	RunE:              runProfileUse,
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create [name] [key=value...]",
+	Short: "Create a profile in the config file",
+	Long: `Create a profile in the config file with the given values. Keys are dotted
+config keys; more can be added later with
+'kinetic config set profiles.<name>.<key> <value>'.
+
+Example:
+  kinetic profile create devnet node.api_port=9700 node.network_id=1337
+  kinetic profile create fuji network.name=fuji --project`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runProfileCreate,
+}
+
+// profileEntry is a profile in the structured output of profile list
+type profileEntry struct {
+	config.Profile
+	Active bool `json:"active"`
+}
+
+func runProfileList(cmd *cobra.Command, args []string) error {
+	cfg := config.Get()
+	var entries []profileEntry
+	for _, p := range cfg.Profiles() {
+		entries = append(entries, profileEntry{Profile: p, Active: p.Name == cfg.Profile})
+	}
+
+	return printResult(cmd, entries, func(w io.Writer) {
+		if len(entries) == 0 {
+			fmt.Fprintln(w, "No profiles defined; create one with 'kinetic profile create'")
+			return
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "\tNAME\tFILES")
+		for _, e := range entries {
+			marker := ""
+			if e.Active {
+				marker = "*"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", marker, e.Name, strings.Join(e.Files, ", "))
+		}
+		tw.Flush()
+	})
+}
+
+// profileUseOutput is the structured result of profile use
+type profileUseOutput struct {
+	Profile string `json:"profile"`
+	Path    string `json:"path"`
+}
+
+func runProfileUse(cmd *cobra.Command, args []string) error {
+	clearProfile, _ := cmd.Flags().GetBool("clear")
+	if clearProfile == (len(args) == 1) {
+		return fmt.Errorf("give a profile name or --clear")
+	}
+	path, err := configTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Get()
+	name := ""
+	if clearProfile {
+		if _, err := config.UnsetFileValue(path, "profile"); err != nil {
+			return err
+		}
+	} else {
+		name = strings.ToLower(args[0])
+		if !hasProfile(cfg, name) {
+			return fmt.Errorf("profile %s is not defined; create it with 'kinetic profile create %s'", name, name)
+		}
+		if err := config.SetFileValue(path, "profile", name); err != nil {
+			return err
+		}
+	}
+
+	origin := cfg.Origin("profile")
+	result := profileUseOutput{Profile: name, Path: path}
+	return printResult(cmd, result, func(w io.Writer) {
+		if clearProfile {
+			fmt.Fprintf(w, "Cleared the active profile in %s\n", path)
+		} else {
+			fmt.Fprintf(w, "Using profile %s (saved in %s)\n", name, path)
+		}
+		if origin.Source == config.SourceEnv || origin.Source == config.SourceFlag {
+			fmt.Fprintf(w, "Note: %s currently selects profile %q\n", origin.Location, cfg.Profile)
+		}
+	})
+}
+
+// hasProfile reports whether a profile is defined in the loaded config files
+func hasProfile(cfg *config.Config, name string) bool {
+	for _, p := range cfg.Profiles() {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// profileCreateOutput is the structured result of profile create
+type profileCreateOutput struct {
+	Profile string         `json:"profile"`
+	Path    string         `json:"path"`
+	Values  map[string]any `json:"values"`
+}
+
+func runProfileCreate(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	values := make(map[string]any)
+	for _, arg := range args[1:] {
+		key, raw, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("invalid value %q: expected key=value", arg)
+		}
+		value, err := config.ParseValue(key, raw)
+		if err != nil {
+			return err
+		}
+		values[key] = value
+	}
+
+	path, err := configTarget(cmd)
+	if err != nil {
+		return err
+	}
+	if err := config.CreateProfile(path, name, values); err != nil {
+		return err
+	}
+	if use, _ := cmd.Flags().GetBool("use"); use {
+		if err := config.SetFileValue(path, "profile", name); err != nil {
+			return err
+		}
+	}
+
+	result := profileCreateOutput{Profile: name, Path: path, Values: values}
+	return printResult(cmd, result, func(w io.Writer) {
+		fmt.Fprintf(w, "Created profile %s in %s\n", name, path)
+	})
+}
+
+func init() {
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileCreateCmd)
+
+	profileCmd.PersistentFlags().Bool("project", false, "Use the project kinetic.yaml instead of the user config file")
+	profileUseCmd.Flags().Bool("clear", false, "Clear the active profile")
+	profileCreateCmd.Flags().Bool("use", false, "Make the new profile active")
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// prompter asks questions on an interactive terminal
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{in: bufio.NewReader(in), out: out}
+}
+
+// ask prints a question and returns the trimmed answer
+func (p *prompter) ask(question string) (string, error) {
+	fmt.Fprintf(p.out, "%s: ", question)
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("failed to read answer: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// confirm asks a yes/no question, defaulting to no
+func (p *prompter) confirm(question string) (bool, error) {
+	answer, err := p.ask(question + " [y/N]")
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// list asks for a comma-separated list
+func (p *prompter) list(question string) ([]string, error) {
+	answer, err := p.ask(question + " (comma-separated)")
+	if err != nil {
+		return nil, err
+	}
+	var items []string
+	for _, item := range strings.Split(answer, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
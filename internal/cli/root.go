@@ -1,25 +1,137 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/version"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+// configKeyAnnotation marks flags that override a config key
+const configKeyAnnotation = "kinetic_config_key"
+
 var rootCmd = &cobra.Command{
 	Use:   "kinetic",
 	Short: "Kinetic - Avalanche development toolkit",
 	Long: `Kinetic is a development toolkit for building applications on Avalanche.
-It provides tools for managing local nodes, deploying contracts, and more.`,
+It provides tools for managing local nodes, deploying contracts, and more.
+
+Configuration is read in layers, each overriding the ones before: built-in
+defaults, the user config file, kinetic.yaml in the working directory, the
+active profile (--profile), KINETIC_* environment variables (e.g.
+KINETIC_NODE_API_PORT) and flags. Config files may be YAML, JSON or TOML.`,
+	// Errors say what went wrong; the usage text is one --help away
+	SilenceUsage: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if completes(cmd) {
+			return nil
+		}
+		if _, err := outputFormat(cmd); err != nil {
+			return err
+		}
+		return loadConfig(cmd)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
 }
 
 func Execute() error {
 	return rootCmd.Execute()
 }
 
+// loadConfig loads the layered config, applying the --config file and any
+// flags of cmd bound to config keys
+func loadConfig(cmd *cobra.Command) error {
+	opts := loadOptions(cmd)
+	if opts.ConfigFile != "" {
+		if _, err := os.Stat(opts.ConfigFile); os.IsNotExist(err) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Config file %s does not exist; using defaults until it is saved\n", opts.ConfigFile)
+		}
+	}
+
+	cfg, err := config.LoadWithOptions(opts)
+	if cfg != nil {
+		for _, m := range cfg.Migrations() {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Upgraded config file %s from version %d to %d (backup at %s): %s\n",
+				m.Path, m.From, m.To, m.Backup, m.Description())
+		}
+	}
+	var invalid config.ValidationErrors
+	if errors.As(err, &invalid) && (editsConfig(cmd) || cmd == doctorCmd) {
+		// Config and profile commands must run so an invalid config can be
+		// fixed, and doctor reports it as one of its checks
+		if cmd != configValidateCmd && cmd != doctorCmd {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", invalid)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	return nil
+}
+
+// loadOptions returns the options loading the config for cmd: the --config
+// file and the flags of cmd bound to config keys
+func loadOptions(cmd *cobra.Command) config.LoadOptions {
+	configFile, _ := cmd.Flags().GetString("config")
+	opts := config.LoadOptions{ConfigFile: configFile, Flags: make(map[string]*pflag.Flag)}
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if keys := f.Annotations[configKeyAnnotation]; len(keys) > 0 {
+			opts.Flags[keys[0]] = f
+		}
+	})
+	return opts
+}
+
+// editsConfig reports whether cmd is a config or profile command
+func editsConfig(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == configCmd || c == profileCmd {
+			return true
+		}
+	}
+	return false
+}
+
+// targetNetwork returns the network deploy commands target: --network, or
+// network.name from the config
+func targetNetwork() string {
+	if name := config.Get().Network.Name; name != "" {
+		return name
+	}
+	return "local"
+}
+
+// bindConfigFlag makes a flag override a config key when set
+func bindConfigFlag(flags *pflag.FlagSet, name, key string) {
+	flags.SetAnnotation(name, configKeyAnnotation, []string{key})
+}
+
 func init() {
+	rootCmd.Version = version.Get().String()
+
 	// Global flags
-	rootCmd.PersistentFlags().StringP("config", "c", "", "config file (default is $HOME/.config/kinetic/config.yaml)")
+	rootCmd.PersistentFlags().StringP("config", "c", "", "config file (default is the user config.yaml, e.g. $HOME/.config/kinetic/config.yaml)")
+	rootCmd.PersistentFlags().String("output", outputText, "output format (text, json, yaml)")
+	rootCmd.PersistentFlags().String("profile", "", "config profile to use (overrides KINETIC_PROFILE and the saved profile)")
+	bindConfigFlag(rootCmd.PersistentFlags(), "profile", "profile")
+	rootCmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+	rootCmd.RegisterFlagCompletionFunc("output", completeValues(outputText, outputJSON, outputYAML))
 
 	// Add commands
 	rootCmd.AddCommand(nodeCmd)
 	rootCmd.AddCommand(contractCmd)
+	rootCmd.AddCommand(subnetCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(profileCmd)
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(doctorCmd)
 }
@@ -1,9 +1,20 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/node"
+	"github.com/kinetic-dev/kinetic/internal/project"
+	"github.com/kinetic-dev/kinetic/internal/subnet"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var subnetCmd = &cobra.Command{
@@ -15,48 +26,479 @@ var subnetCmd = &cobra.Command{
 var subnetListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all subnets",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println("Listing subnets...")
-		// TODO: Implement subnet listing logic
-		return nil
-	},
+	RunE:  runSubnetList,
+}
+
+var subnetDescribeCmd = &cobra.Command{
+	Use:               "describe [name]",
+	Short:             "Show a subnet's definition, genesis and deployments",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSubnetArgs(1),
+	RunE:              runSubnetDescribe,
+}
+
+var subnetDeleteCmd = &cobra.Command{
+	Use:               "delete [name]",
+	Short:             "Delete a subnet definition",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSubnetArgs(1),
+	RunE:              runSubnetDelete,
 }
 
 var subnetCreateCmd = &cobra.Command{
 	Use:   "create [name]",
 	Short: "Create a new subnet",
-	Args:  cobra.ExactArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		name := args[0]
-		vmType, _ := cmd.Flags().GetString("vm")
-		fmt.Printf("Creating subnet '%s' with VM type '%s'...\n", name, vmType)
-		// TODO: Implement subnet creation logic
-		return nil
-	},
+	Long: `Create a new subnet definition and generate its subnet-evm genesis.
+
+With --vm custom, register your own VM instead: give a prebuilt plugin with
+--vm-binary or a Go module to build with --vm-module, and a genesis file that is
+used verbatim. The VM ID is derived from --vm-name as avalanchego does.
+
+Example:
+  kinetic subnet create mysubnet --chain-id 99999 --token-name MYT
+  kinetic subnet create mysubnet --chain-id 99999 --token-name MYT \
+    --gas-limit 15000000 --target-block-rate 1 \
+    --alloc 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC=1000000
+  kinetic subnet create mysubnet --chain-id 99999 --token-name MYT \
+    --tx-allowlist-admins 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC \
+    --native-minter-admins 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC
+  kinetic subnet create mysubnet --chain-id 99999 --interactive
+  kinetic subnet create mysubnet --chain-id 99999 --token-name MYT --warp
+  kinetic subnet create myvm --vm custom --vm-binary ./build/myvm --genesis genesis.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSubnetCreate,
 }
 
 var subnetDeployCmd = &cobra.Command{
 	Use:   "deploy [name]",
 	Short: "Deploy a subnet",
-	Args:  cobra.ExactArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		name := args[0]
-		network, _ := cmd.Flags().GetString("network")
-		fmt.Printf("Deploying subnet '%s' to '%s'...\n", name, network)
-		// TODO: Implement subnet deployment logic
-		return nil
-	},
+	Long: `Deploy a subnet to a network. Deploying to local creates the subnet and
+its blockchain on the running local node with the prefunded ewoq key, installs
+the subnet-evm plugin and restarts the node to track the subnet. If a
+deployment fails after creating the subnet, running deploy again finishes it
+with the same subnet and blockchain.
+
+Example:
+  kinetic subnet deploy mysubnet --network local`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSubnetArgs(1),
+	RunE:              runSubnetDeploy,
+}
+
+// subnetListItem is one entry in the structured result of subnet list
+type subnetListItem struct {
+	Name        string                        `json:"name"`
+	VM          string                        `json:"vm"`
+	ChainID     uint64                        `json:"chain_id"`
+	Deployments map[string]*subnet.Deployment `json:"deployments"`
+}
+
+func runSubnetList(cmd *cobra.Command, args []string) error {
+	store, err := subnet.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	subnets, err := store.List()
+	if err != nil {
+		return fmt.Errorf("failed to list subnets: %w", err)
+	}
+
+	items := make([]subnetListItem, 0, len(subnets))
+	for _, sn := range subnets {
+		deployments := sn.Deployments
+		if deployments == nil {
+			deployments = map[string]*subnet.Deployment{}
+		}
+		items = append(items, subnetListItem{
+			Name:        sn.Name,
+			VM:          sn.VM,
+			ChainID:     sn.ChainID,
+			Deployments: deployments,
+		})
+	}
+
+	return printResult(cmd, items, func(w io.Writer) {
+		if len(items) == 0 {
+			fmt.Fprintln(w, "No subnets found. Create one with 'kinetic subnet create'.")
+			return
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "NAME\tVM\tCHAIN ID\t%s\n", strings.ToUpper(strings.Join(subnet.Networks, "\t")))
+		for _, item := range items {
+			fmt.Fprintf(tw, "%s\t%s\t%d", item.Name, item.VM, item.ChainID)
+			for _, network := range subnet.Networks {
+				status := "-"
+				if item.Deployments[network] != nil {
+					status = "deployed"
+				}
+				fmt.Fprintf(tw, "\t%s", status)
+			}
+			fmt.Fprintln(tw)
+		}
+		tw.Flush()
+	})
+}
+
+// subnetDescribeOutput is the structured result of subnet describe
+type subnetDescribeOutput struct {
+	*subnet.Subnet
+	GenesisPath string          `json:"genesis_path"`
+	Genesis     json.RawMessage `json:"genesis"`
+}
+
+func runSubnetDescribe(cmd *cobra.Command, args []string) error {
+	store, err := subnet.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	sn, err := store.Get(args[0])
+	if err != nil {
+		return err
+	}
+	genesis, err := store.Genesis(sn.Name)
+	if err != nil {
+		return err
+	}
+
+	result := subnetDescribeOutput{Subnet: sn, GenesisPath: store.GenesisPath(sn.Name), Genesis: genesis}
+	return printResult(cmd, result, func(w io.Writer) {
+		fmt.Fprintf(w, "Subnet: %s\n", sn.Name)
+		fmt.Fprintf(w, "  VM: %s\n", sn.VM)
+		if sn.VMName != "" {
+			fmt.Fprintf(w, "  VM Name: %s\n", sn.VMName)
+		}
+		fmt.Fprintf(w, "  VM ID: %s\n", sn.VMID)
+		fmt.Fprintf(w, "  Chain ID: %d\n", sn.ChainID)
+		fmt.Fprintf(w, "  Token: %s\n", sn.TokenName)
+		if sn.VM == subnet.VMSubnetEVM && subnet.WarpEnabled(genesis) {
+			fmt.Fprintln(w, "  Warp: enabled")
+		}
+		fmt.Fprintf(w, "  Created: %s\n", sn.CreatedAt.Format(time.RFC3339))
+		fmt.Fprintf(w, "  Genesis: %s\n", result.GenesisPath)
+
+		for _, network := range subnet.Networks {
+			d := sn.Deployment(network)
+			if p := sn.PendingDeployment(network); d == nil && p != nil {
+				fmt.Fprintf(w, "  %s: unfinished (subnet ID %s); run 'kinetic subnet deploy %s' to finish\n", network, p.SubnetID, sn.Name)
+				continue
+			}
+			if d == nil {
+				fmt.Fprintf(w, "  %s: not deployed\n", network)
+				continue
+			}
+			fmt.Fprintf(w, "  %s:\n", network)
+			fmt.Fprintf(w, "    Subnet ID: %s\n", d.SubnetID)
+			fmt.Fprintf(w, "    Blockchain ID: %s\n", d.BlockchainID)
+			fmt.Fprintf(w, "    RPC URL: %s\n", d.RPCURL)
+			if len(d.Validators) > 0 {
+				fmt.Fprintf(w, "    Validators: %s\n", strings.Join(d.Validators, ", "))
+			}
+			fmt.Fprintf(w, "    Deployed: %s\n", d.DeployedAt.Format(time.RFC3339))
+		}
+	})
+}
+
+// subnetDeleteOutput is the structured result of subnet delete
+type subnetDeleteOutput struct {
+	Name    string `json:"name"`
+	Deleted bool   `json:"deleted"`
+}
+
+func runSubnetDelete(cmd *cobra.Command, args []string) error {
+	store, err := subnet.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	sn, err := store.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	if (len(sn.Deployments) > 0 || len(sn.PendingDeployments) > 0) && !force {
+		return fmt.Errorf("subnet %s has deployments; use --force to delete its definition anyway", sn.Name)
+	}
+
+	if err := store.Delete(sn.Name); err != nil {
+		return err
+	}
+
+	result := subnetDeleteOutput{Name: sn.Name, Deleted: true}
+	return printResult(cmd, result, func(w io.Writer) {
+		fmt.Fprintf(w, "Subnet '%s' deleted\n", sn.Name)
+	})
+}
+
+// subnetCreateOutput is the structured result of subnet create
+type subnetCreateOutput struct {
+	Name        string `json:"name"`
+	VM          string `json:"vm"`
+	ChainID     uint64 `json:"chain_id"`
+	TokenName   string `json:"token_name"`
+	GenesisPath string `json:"genesis_path"`
+}
+
+func runSubnetCreate(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	vmType, _ := cmd.Flags().GetString("vm")
+	tokenName, _ := cmd.Flags().GetString("token-name")
+
+	opts := subnet.CreateOptions{Name: name, VM: vmType, TokenName: tokenName}
+	if vmType == subnet.VMCustom {
+		opts.CustomVM.Name, _ = cmd.Flags().GetString("vm-name")
+		opts.CustomVM.BinaryPath, _ = cmd.Flags().GetString("vm-binary")
+		opts.CustomVM.Module, _ = cmd.Flags().GetString("vm-module")
+		opts.CustomVM.GenesisPath, _ = cmd.Flags().GetString("genesis")
+		opts.CustomVM.GOOS = subnet.PluginOS(config.Get())
+		if opts.CustomVM.Module != "" {
+			fmt.Fprintf(progressWriter(cmd), "Building VM from %s...\n", opts.CustomVM.Module)
+		}
+	} else {
+		genesisOpts, err := subnetGenesisOptions(cmd)
+		if err != nil {
+			return err
+		}
+		opts.Genesis = genesisOpts
+	}
+
+	store, err := subnet.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	sn, err := subnet.Create(store, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create subnet: %w", err)
+	}
+
+	result := subnetCreateOutput{
+		Name:        sn.Name,
+		VM:          sn.VM,
+		ChainID:     sn.ChainID,
+		TokenName:   sn.TokenName,
+		GenesisPath: store.GenesisPath(sn.Name),
+	}
+	return printResult(cmd, result, func(w io.Writer) {
+		fmt.Fprintf(w, "Subnet '%s' created with VM type '%s'\n", sn.Name, sn.VM)
+		fmt.Fprintf(w, "Genesis: %s\n", result.GenesisPath)
+	})
+}
+
+// subnetGenesisOptions builds genesis options from the subnet create flags
+func subnetGenesisOptions(cmd *cobra.Command) (subnet.GenesisOptions, error) {
+	opts := subnet.DefaultGenesisOptions()
+
+	chainID, _ := cmd.Flags().GetString("chain-id")
+	if chainID == "" {
+		return opts, fmt.Errorf("--chain-id is required")
+	}
+	id, err := strconv.ParseUint(chainID, 10, 64)
+	if err != nil {
+		return opts, fmt.Errorf("invalid chain ID %q: %w", chainID, err)
+	}
+	opts.ChainID = id
+
+	if cmd.Flags().Changed("gas-limit") {
+		opts.GasLimit, _ = cmd.Flags().GetUint64("gas-limit")
+	}
+	if cmd.Flags().Changed("target-gas") {
+		opts.TargetGas, _ = cmd.Flags().GetUint64("target-gas")
+	}
+	if cmd.Flags().Changed("target-block-rate") {
+		opts.TargetBlockRate, _ = cmd.Flags().GetUint64("target-block-rate")
+	}
+	if cmd.Flags().Changed("min-base-fee") {
+		opts.MinBaseFee, _ = cmd.Flags().GetUint64("min-base-fee")
+	}
+
+	allocs, _ := cmd.Flags().GetStringSlice("alloc")
+	for _, a := range allocs {
+		alloc, err := subnet.ParseAllocation(a)
+		if err != nil {
+			return opts, err
+		}
+		opts.Allocations = append(opts.Allocations, alloc)
+	}
+
+	precompiles, err := subnetPrecompiles(cmd)
+	if err != nil {
+		return opts, err
+	}
+	opts.Precompiles = *precompiles
+	opts.Warp, _ = cmd.Flags().GetBool("warp")
+
+	return opts, nil
+}
+
+// addPrecompileFlags registers the precompile settings read by subnetPrecompiles
+func addPrecompileFlags(flags *pflag.FlagSet) {
+	for _, name := range subnet.PrecompileNames {
+		title := subnet.PrecompileTitle(name)
+		flags.StringSlice(name+"-admins", nil, "Enable the "+title+" with these admin addresses")
+		flags.StringSlice(name+"-enabled", nil, "Enable the "+title+" with these enabled addresses")
+	}
+	flags.Bool("allow-fee-recipients", false, "Let validators set their own fee recipient")
+	flags.String("reward-address", "", "Send all fees to this address (requires the reward manager)")
+	flags.BoolP("interactive", "i", false, "Prompt for precompile configuration")
+}
+
+// subnetPrecompiles reads precompile settings from flags and, with
+// --interactive, prompts for any precompile not configured by flags
+func subnetPrecompiles(cmd *cobra.Command) (*subnet.Precompiles, error) {
+	p := &subnet.Precompiles{}
+	p.AllowFeeRecipients, _ = cmd.Flags().GetBool("allow-fee-recipients")
+	p.RewardAddress, _ = cmd.Flags().GetString("reward-address")
+
+	for _, name := range subnet.PrecompileNames {
+		admins, _ := cmd.Flags().GetStringSlice(name + "-admins")
+		enabled, _ := cmd.Flags().GetStringSlice(name + "-enabled")
+		if cmd.Flags().Changed(name+"-admins") || cmd.Flags().Changed(name+"-enabled") {
+			p.Set(name, &subnet.AllowList{Admins: admins, Enabled: enabled})
+		}
+	}
+
+	if interactive, _ := cmd.Flags().GetBool("interactive"); !interactive {
+		return p, nil
+	}
+
+	prompt := newPrompter(cmd.InOrStdin(), progressWriter(cmd))
+	for _, name := range subnet.PrecompileNames {
+		if p.Get(name) != nil {
+			continue
+		}
+		title := subnet.PrecompileTitle(name)
+		enable, err := prompt.confirm("Enable the " + title + "?")
+		if err != nil {
+			return nil, err
+		}
+		if !enable {
+			continue
+		}
+		admins, err := prompt.list("  Admin addresses for the " + title)
+		if err != nil {
+			return nil, err
+		}
+		enabled, err := prompt.list("  Enabled addresses for the " + title)
+		if err != nil {
+			return nil, err
+		}
+		p.Set(name, &subnet.AllowList{Admins: admins, Enabled: enabled})
+	}
+
+	if p.RewardManager != nil && p.RewardAddress == "" && !p.AllowFeeRecipients {
+		address, err := prompt.ask("Fee reward address (leave empty to burn fees)")
+		if err != nil {
+			return nil, err
+		}
+		p.RewardAddress = address
+	}
+	return p, nil
+}
+
+// subnetDeployOutput is the structured result of subnet deploy
+type subnetDeployOutput struct {
+	Name    string `json:"name"`
+	Network string `json:"network"`
+	*subnet.Deployment
+	// Record is the project's deployment record, when run inside a project
+	Record string `json:"record,omitempty"`
+}
+
+func runSubnetDeploy(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	name := args[0]
+	network := targetNetwork()
+	vmVersion, _ := cmd.Flags().GetString("vm-version")
+
+	if network != "local" {
+		return fmt.Errorf("deploying to %s is not supported yet; only local is available", network)
+	}
+
+	store, err := subnet.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Get()
+	// Deploying installs the VM plugin and chain config in the node dirs
+	if err := cfg.CheckDirs(); err != nil {
+		return err
+	}
+	manager, err := node.NewManager(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create node manager: %w", err)
+	}
+	defer manager.Close()
+
+	progress := progressWriter(cmd)
+	manager.SetOutput(progress)
+
+	d, err := subnet.DeployLocal(ctx, store, name, subnet.DeployOptions{
+		Config:    cfg,
+		Manager:   manager,
+		VMVersion: vmVersion,
+		Out:       progress,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to deploy subnet: %w", err)
+	}
+
+	// Persist the tracked subnet so later node restarts keep running it
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	result := subnetDeployOutput{Name: name, Network: network, Deployment: d}
+	if p, err := project.Current(); err == nil {
+		if result.Record, err = p.WriteDeployment(network, project.KindSubnet, name, result); err != nil {
+			return err
+		}
+	}
+	return printResult(cmd, result, func(w io.Writer) {
+		fmt.Fprintf(w, "Subnet '%s' deployed to %s\n", name, network)
+		fmt.Fprintf(w, "  Subnet ID: %s\n", d.SubnetID)
+		fmt.Fprintf(w, "  Blockchain ID: %s\n", d.BlockchainID)
+		fmt.Fprintf(w, "  RPC URL: %s\n", d.RPCURL)
+		if result.Record != "" {
+			fmt.Fprintf(w, "  Recorded in: %s\n", result.Record)
+		}
+	})
 }
 
 func init() {
+	defaultGenesis := subnet.DefaultGenesisOptions()
+
 	subnetCmd.AddCommand(subnetListCmd)
+	subnetCmd.AddCommand(subnetDescribeCmd)
+	subnetCmd.AddCommand(subnetDeleteCmd)
 	subnetCmd.AddCommand(subnetCreateCmd)
 	subnetCmd.AddCommand(subnetDeployCmd)
 
 	// Add flags
 	subnetCreateCmd.Flags().StringP("vm", "v", "subnet-evm", "VM type (subnet-evm, custom)")
-	subnetCreateCmd.Flags().StringP("chain-id", "c", "", "Chain ID for the subnet")
+	subnetCreateCmd.RegisterFlagCompletionFunc("vm", completeValues("subnet-evm", "custom"))
+	subnetCreateCmd.Flags().String("vm-name", "", "Custom VM name the VM ID is derived from (default is the subnet name)")
+	subnetCreateCmd.Flags().String("vm-binary", "", "Path to a prebuilt custom VM plugin binary")
+	subnetCreateCmd.Flags().String("vm-module", "", "Go module path (module@version) or local directory to build the custom VM from")
+	subnetCreateCmd.Flags().String("genesis", "", "Genesis file for a custom VM, used verbatim")
+	subnetCreateCmd.Flags().String("chain-id", "", "Chain ID for the subnet")
 	subnetCreateCmd.Flags().StringP("token-name", "t", "", "Token name for the subnet")
+	subnetCreateCmd.Flags().Uint64("gas-limit", defaultGenesis.GasLimit, "Block gas limit")
+	subnetCreateCmd.Flags().Uint64("target-gas", defaultGenesis.TargetGas, "Target gas consumed per target block rate window")
+	subnetCreateCmd.Flags().Uint64("target-block-rate", defaultGenesis.TargetBlockRate, "Target seconds between blocks")
+	subnetCreateCmd.Flags().Uint64("min-base-fee", defaultGenesis.MinBaseFee, "Minimum base fee in wei")
+	subnetCreateCmd.Flags().StringSlice("alloc", nil, "Initial allocation as address=amount in whole tokens (repeatable, default funds the local ewoq key)")
+	subnetCreateCmd.Flags().Bool("warp", false, "Enable Avalanche Warp Messaging and the warp API")
+	addPrecompileFlags(subnetCreateCmd.Flags())
+
+	subnetDeleteCmd.Flags().BoolP("force", "f", false, "Delete even if the subnet has deployments")
 
-	subnetDeployCmd.Flags().StringP("network", "n", "local", "Target network (local, fuji, mainnet)")
+	subnetDeployCmd.Flags().StringP("network", "n", "", "Target network: local, fuji or mainnet (default is network.name, local unless configured)")
+	bindConfigFlag(subnetDeployCmd.Flags(), "network", "network.name")
+	subnetDeployCmd.RegisterFlagCompletionFunc("network", completeNetworks)
+	subnetDeployCmd.Flags().String("vm-version", subnet.DefaultSubnetEVMVersion, "subnet-evm release to install on the node")
 }
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/subnet"
+	"github.com/spf13/cobra"
+)
+
+var subnetConfigCmd = &cobra.Command{
+	Use:   "config [name] [show|set|unset|keys] [key=value...]",
+	Short: "Manage a subnet's chain config",
+	Long: `Manage the chain config avalanchego passes to the subnet's VM, such as
+enabled APIs, pruning and log level. For subnets deployed locally the config
+is installed as configs/chains/<blockchainID>/config.json in the node's chain
+config directory and the node is restarted when it changes.
+
+subnet-evm keys are validated against the known config keys; list them with
+'keys'. Use --force to set other keys. Values of custom VM configs are read as
+JSON, falling back to plain strings.
+
+Example:
+  kinetic subnet config mysubnet
+  kinetic subnet config mysubnet set pruning-enabled=false log-level=debug
+  kinetic subnet config mysubnet set eth-apis=eth,eth-filter,net,web3,debug-tracer
+  kinetic subnet config mysubnet unset log-level`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeSubnetConfig,
+	RunE:              runSubnetConfig,
+}
+
+// subnetConfigOutput is the structured result of subnet config
+type subnetConfigOutput struct {
+	Name        string         `json:"name"`
+	Config      map[string]any `json:"config"`
+	Changed     bool           `json:"changed"`
+	InstalledAt string         `json:"installed_at,omitempty"`
+}
+
+// subnetConfigKey is a known chain config key in the keys listing
+type subnetConfigKey struct {
+	Key         string   `json:"key"`
+	Type        string   `json:"type"`
+	Values      []string `json:"values,omitempty"`
+	Description string   `json:"description"`
+}
+
+func runSubnetConfig(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	action := "show"
+	if len(args) > 1 {
+		action = args[1]
+	}
+	params := args[min(len(args), 2):]
+
+	store, err := subnet.DefaultStore()
+	if err != nil {
+		return err
+	}
+	sn, err := store.Get(name)
+	if err != nil {
+		return err
+	}
+
+	var set map[string]any
+	var unset []string
+	switch action {
+	case "show":
+		values, err := subnet.LoadChainConfig(store, name)
+		if err != nil {
+			return err
+		}
+		return printResult(cmd, subnetConfigOutput{Name: name, Config: values}, func(w io.Writer) {
+			printChainConfig(w, values)
+		})
+	case "keys":
+		if sn.VM != subnet.VMSubnetEVM {
+			return fmt.Errorf("no known config keys for VM type %s", sn.VM)
+		}
+		return printConfigKeys(cmd)
+	case "set":
+		if len(params) == 0 {
+			return fmt.Errorf("set requires at least one key=value")
+		}
+		force, _ := cmd.Flags().GetBool("force")
+		set = make(map[string]any, len(params))
+		for _, param := range params {
+			key, value, ok := strings.Cut(param, "=")
+			if !ok || key == "" {
+				return fmt.Errorf("invalid setting %q; expected key=value", param)
+			}
+			if set[key], err = subnet.ParseConfigValue(sn.VM, key, value, force); err != nil {
+				return err
+			}
+		}
+	case "unset":
+		if len(params) == 0 {
+			return fmt.Errorf("unset requires at least one key")
+		}
+		unset = params
+	default:
+		return fmt.Errorf("unknown action %q; expected show, set, unset or keys", action)
+	}
+
+	values, changed, err := subnet.UpdateChainConfig(store, name, set, unset)
+	if err != nil {
+		return err
+	}
+	result := subnetConfigOutput{Name: name, Config: values, Changed: changed}
+
+	if changed && sn.Deployment("local") != nil {
+		cfg := config.Get()
+		result.InstalledAt, err = subnet.InstallChainConfig(store, name, cfg)
+		if err != nil {
+			return err
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		if err := restartLocalNode(cmd.Context(), cfg, progressWriter(cmd)); err != nil {
+			return err
+		}
+	}
+
+	return printResult(cmd, result, func(w io.Writer) {
+		if !changed {
+			fmt.Fprintf(w, "Chain config of subnet '%s' is unchanged\n", name)
+			return
+		}
+		fmt.Fprintf(w, "Chain config of subnet '%s' updated\n", name)
+		if result.InstalledAt != "" {
+			fmt.Fprintf(w, "Installed: %s\n", result.InstalledAt)
+		}
+		printChainConfig(w, values)
+	})
+}
+
+// printChainConfig renders chain config values for humans
+func printChainConfig(w io.Writer, values map[string]any) {
+	if len(values) == 0 {
+		fmt.Fprintln(w, "No chain config set")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, key := range subnet.ConfigKeys(values) {
+		data, _ := json.Marshal(values[key])
+		fmt.Fprintf(tw, "%s\t%s\n", key, data)
+	}
+	tw.Flush()
+}
+
+// printConfigKeys lists the known subnet-evm chain config keys
+func printConfigKeys(cmd *cobra.Command) error {
+	keys := make([]subnetConfigKey, 0, len(subnet.SubnetEVMConfigKeys))
+	for key, schema := range subnet.SubnetEVMConfigKeys {
+		keys = append(keys, subnetConfigKey{Key: key, Type: schema.Type, Values: schema.Values, Description: schema.Description})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Key < keys[j].Key })
+
+	return printResult(cmd, keys, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "KEY\tTYPE\tDESCRIPTION")
+		for _, k := range keys {
+			typ := k.Type
+			if len(k.Values) > 0 {
+				typ = strings.Join(k.Values, "|")
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", k.Key, typ, k.Description)
+		}
+		tw.Flush()
+	})
+}
+
+func init() {
+	subnetCmd.AddCommand(subnetConfigCmd)
+
+	subnetConfigCmd.Flags().Bool("force", false, "Set keys that are not known subnet-evm config keys")
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/subnet"
+	"github.com/spf13/cobra"
+)
+
+var subnetRelayerCmd = &cobra.Command{
+	Use:   "relayer [source] [destination]",
+	Short: "Relay warp messages between two local subnets",
+	Long: `Run a lightweight relayer that delivers Avalanche Warp Messages sent on the
+source subnet to the destination subnet. Both subnets must be created with
+--warp and deployed locally.
+
+The relayer watches the source chain for messages from the warp precompile,
+aggregates the validators' signatures through the warp API, and delivers each
+signed message by calling receiveCrossChainMessage(uint32,address) with the
+message in the transaction's access list. Messages go to the sender's address
+on the destination chain, as Teleporter messengers share an address across
+chains, unless --destination-address is set.
+
+The relayer runs until interrupted; use --once to relay pending messages and
+exit.
+
+Example:
+  kinetic subnet relayer subnet-a subnet-b
+  kinetic subnet relayer subnet-a subnet-b --from-block 1 --once`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeSubnetArgs(2),
+	RunE:              runSubnetRelayer,
+}
+
+func runSubnetRelayer(cmd *cobra.Command, args []string) error {
+	store, err := subnet.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	opts := subnet.RelayerOptions{
+		Config:      config.Get(),
+		Source:      args[0],
+		Destination: args[1],
+		Out:         progressWriter(cmd),
+	}
+	opts.DestinationAddress, _ = cmd.Flags().GetString("destination-address")
+	opts.FromBlock, _ = cmd.Flags().GetUint64("from-block")
+	opts.PollInterval, _ = cmd.Flags().GetDuration("poll-interval")
+	if privateKey := config.Get().Network.DefaultKey; privateKey != "" {
+		if opts.Key, err = subnet.KeyFromHex(privateKey); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	relayer, err := subnet.NewRelayer(ctx, store, opts)
+	if err != nil {
+		return err
+	}
+
+	var relayed []subnet.RelayedMessage
+	if once, _ := cmd.Flags().GetBool("once"); once {
+		if relayed, err = relayer.Poll(ctx); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(opts.Out, "Relaying warp messages from %s to %s (Ctrl+C to stop)...\n", opts.Source, opts.Destination)
+		relayed = relayer.Run(ctx)
+	}
+
+	if relayed == nil {
+		relayed = []subnet.RelayedMessage{}
+	}
+	return printResult(cmd, relayed, func(w io.Writer) {
+		printRelayed(w, relayed)
+	})
+}
+
+// printRelayed renders delivered warp messages as a table
+func printRelayed(w io.Writer, relayed []subnet.RelayedMessage) {
+	if len(relayed) == 0 {
+		fmt.Fprintln(w, "No warp messages relayed")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MESSAGE ID\tFROM\tTO\tDELIVERY TX")
+	for _, m := range relayed {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", m.ID, m.SourceAddress, m.DestinationAddress, m.DeliveryTx)
+	}
+	tw.Flush()
+}
+
+func init() {
+	subnetCmd.AddCommand(subnetRelayerCmd)
+
+	subnetRelayerCmd.Flags().String("destination-address", "", "Contract receiving messages on the destination (default is the sender's address)")
+	subnetRelayerCmd.Flags().StringP("private-key", "k", "", "Private key paying for deliveries (default is network.default_key, or the local ewoq key)")
+	bindConfigFlag(subnetRelayerCmd.Flags(), "private-key", "network.default_key")
+	subnetRelayerCmd.Flags().Uint64("from-block", 0, "First source block to relay (default is the next block)")
+	subnetRelayerCmd.Flags().Duration("poll-interval", 0, "Interval between polls of the source chain (default 2s)")
+	subnetRelayerCmd.Flags().Bool("once", false, "Relay pending messages once and exit")
+}
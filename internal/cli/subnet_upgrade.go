@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/node"
+	"github.com/kinetic-dev/kinetic/internal/subnet"
+	"github.com/spf13/cobra"
+)
+
+var subnetUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [name]",
+	Short: "Schedule precompile and fee upgrades for a subnet",
+	Long: `Author subnet-evm network upgrades in the subnet's upgrade.json. Each
+upgrade enables or disables one precompile at an activation time. Fee config
+changes are made by enabling the fee manager with an initial fee config.
+
+Upgrades are validated against the genesis and earlier upgrades. For subnets
+deployed locally the upgrades are installed into the node's chain config
+directory and the node is restarted to load them.
+
+Without --enable or --disable the scheduled upgrades are listed.
+
+Example:
+  kinetic subnet upgrade mysubnet --enable native-minter \
+    --admin-addresses 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC --activate-at +5m
+  kinetic subnet upgrade mysubnet --enable fee-manager --gas-limit 15000000 \
+    --admin-addresses 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC --activate-at 2025-01-01T00:00:00Z
+  kinetic subnet upgrade mysubnet --disable tx-allowlist --activate-at +1h`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSubnetArgs(1),
+	RunE:              runSubnetUpgrade,
+}
+
+// subnetUpgradeOutput is the structured result of subnet upgrade
+type subnetUpgradeOutput struct {
+	Name        string                     `json:"name"`
+	Upgrades    []subnet.PrecompileUpgrade `json:"precompileUpgrades"`
+	Path        string                     `json:"path"`
+	InstalledAt string                     `json:"installed_at,omitempty"`
+}
+
+func runSubnetUpgrade(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	name := args[0]
+
+	store, err := subnet.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	sn, err := store.Get(name)
+	if err != nil {
+		return err
+	}
+
+	enable, _ := cmd.Flags().GetString("enable")
+	disable, _ := cmd.Flags().GetString("disable")
+	if enable != "" && disable != "" {
+		return fmt.Errorf("--enable and --disable cannot be combined")
+	}
+
+	if enable == "" && disable == "" {
+		upgrades, err := store.Upgrades(name)
+		if err != nil {
+			return err
+		}
+		result := subnetUpgradeOutput{Name: name, Upgrades: upgrades.PrecompileUpgrades, Path: store.UpgradePath(name)}
+		return printResult(cmd, result, func(w io.Writer) {
+			printUpgrades(w, upgrades)
+		})
+	}
+
+	upgrade, err := subnetUpgradeFromFlags(cmd, store, name, enable, disable)
+	if err != nil {
+		return err
+	}
+
+	upgrades, err := subnet.AddUpgrade(store, name, upgrade, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid upgrade: %w", err)
+	}
+	result := subnetUpgradeOutput{Name: name, Upgrades: upgrades.PrecompileUpgrades, Path: store.UpgradePath(name)}
+
+	if sn.Deployment("local") != nil {
+		progress := progressWriter(cmd)
+		cfg := config.Get()
+		result.InstalledAt, err = subnet.InstallUpgrades(store, name, cfg)
+		if err != nil {
+			return err
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		if err := restartLocalNode(ctx, cfg, progress); err != nil {
+			return err
+		}
+	}
+
+	return printResult(cmd, result, func(w io.Writer) {
+		fmt.Fprintf(w, "Upgrade scheduled for subnet '%s'\n", name)
+		if result.InstalledAt != "" {
+			fmt.Fprintf(w, "Installed: %s\n", result.InstalledAt)
+		}
+		printUpgrades(w, upgrades)
+	})
+}
+
+// subnetUpgradeFromFlags builds the upgrade described by the command's flags
+func subnetUpgradeFromFlags(cmd *cobra.Command, store *subnet.Store, name, enable, disable string) (subnet.PrecompileUpgrade, error) {
+	activateAt, _ := cmd.Flags().GetString("activate-at")
+	if activateAt == "" {
+		return nil, fmt.Errorf("--activate-at is required")
+	}
+	at, err := subnet.ParseActivationTime(activateAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &subnet.UpgradeEntry{BlockTimestamp: uint64(at.Unix()), Disable: disable != ""}
+	if disable != "" {
+		return subnet.NewPrecompileUpgrade(disable, entry)
+	}
+
+	entry.AdminAddresses, _ = cmd.Flags().GetStringSlice("admin-addresses")
+	entry.EnabledAddresses, _ = cmd.Flags().GetStringSlice("enabled-addresses")
+
+	if feeFlagsChanged(cmd) {
+		// Unchanged fee parameters carry over from the genesis
+		genesis, err := storedGenesis(store, name)
+		if err != nil {
+			return nil, err
+		}
+		fee := genesis.Config.FeeConfig
+		if cmd.Flags().Changed("gas-limit") {
+			fee.GasLimit, _ = cmd.Flags().GetUint64("gas-limit")
+		}
+		if cmd.Flags().Changed("target-gas") {
+			fee.TargetGas, _ = cmd.Flags().GetUint64("target-gas")
+		}
+		if cmd.Flags().Changed("target-block-rate") {
+			fee.TargetBlockRate, _ = cmd.Flags().GetUint64("target-block-rate")
+		}
+		if cmd.Flags().Changed("min-base-fee") {
+			fee.MinBaseFee, _ = cmd.Flags().GetUint64("min-base-fee")
+		}
+		entry.InitialFeeConfig = &fee
+	}
+
+	allowFeeRecipients, _ := cmd.Flags().GetBool("allow-fee-recipients")
+	rewardAddress, _ := cmd.Flags().GetString("reward-address")
+	if allowFeeRecipients || rewardAddress != "" {
+		entry.InitialRewardConfig = &subnet.RewardConfig{AllowFeeRecipients: allowFeeRecipients, RewardAddress: rewardAddress}
+	}
+
+	return subnet.NewPrecompileUpgrade(enable, entry)
+}
+
+// feeFlagsChanged reports whether any fee config flag was given
+func feeFlagsChanged(cmd *cobra.Command) bool {
+	for _, flag := range []string{"gas-limit", "target-gas", "target-block-rate", "min-base-fee"} {
+		if cmd.Flags().Changed(flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// storedGenesis loads and decodes a subnet's genesis
+func storedGenesis(store *subnet.Store, name string) (*subnet.Genesis, error) {
+	data, err := store.Genesis(name)
+	if err != nil {
+		return nil, err
+	}
+	genesis := &subnet.Genesis{}
+	if err := json.Unmarshal(data, genesis); err != nil {
+		return nil, fmt.Errorf("failed to parse genesis: %w", err)
+	}
+	return genesis, nil
+}
+
+// restartLocalNode restarts the local node, if running, so it picks up
+// changed chain configs
+func restartLocalNode(ctx context.Context, cfg *config.Config, progress io.Writer) error {
+	manager, err := node.NewManager(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create node manager: %w", err)
+	}
+	defer manager.Close()
+	manager.SetOutput(progress)
+
+	status, err := manager.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check node status: %w", err)
+	}
+	if !status.IsRunning {
+		fmt.Fprintln(progress, "Node is not running; changes apply on next start")
+		return nil
+	}
+
+	fmt.Fprintln(progress, "Restarting node to apply chain config changes...")
+	if err := manager.Restart(ctx); err != nil {
+		return fmt.Errorf("failed to restart node: %w", err)
+	}
+	if err := manager.WaitForHealthy(ctx, 2*time.Minute); err != nil {
+		return fmt.Errorf("node failed to become healthy: %w", err)
+	}
+	return nil
+}
+
+// printUpgrades renders scheduled upgrades for humans
+func printUpgrades(w io.Writer, cfg *subnet.UpgradeConfig) {
+	if len(cfg.PrecompileUpgrades) == 0 {
+		fmt.Fprintln(w, "No upgrades scheduled")
+		return
+	}
+	fmt.Fprintln(w, "Upgrades:")
+	for _, u := range cfg.PrecompileUpgrades {
+		name, entry, err := u.Precompile()
+		if err != nil {
+			continue
+		}
+		action := "enable"
+		if entry.Disable {
+			action = "disable"
+		}
+		fmt.Fprintf(w, "  %s  %s %s\n", entry.ActivationTime().Format(time.RFC3339), action, name)
+	}
+}
+
+func init() {
+	subnetCmd.AddCommand(subnetUpgradeCmd)
+
+	subnetUpgradeCmd.Flags().String("enable", "", "Precompile to enable (deployer-allowlist, tx-allowlist, native-minter, fee-manager, reward-manager)")
+	subnetUpgradeCmd.Flags().String("disable", "", "Precompile to disable")
+	subnetUpgradeCmd.Flags().String("activate-at", "", "Activation time as RFC 3339, unix seconds or +duration")
+	subnetUpgradeCmd.Flags().StringSlice("admin-addresses", nil, "Admin addresses of the enabled precompile")
+	subnetUpgradeCmd.Flags().StringSlice("enabled-addresses", nil, "Enabled addresses of the enabled precompile")
+	subnetUpgradeCmd.Flags().Uint64("gas-limit", 0, "Fee manager initial block gas limit")
+	subnetUpgradeCmd.Flags().Uint64("target-gas", 0, "Fee manager initial target gas")
+	subnetUpgradeCmd.Flags().Uint64("target-block-rate", 0, "Fee manager initial target block rate")
+	subnetUpgradeCmd.Flags().Uint64("min-base-fee", 0, "Fee manager initial minimum base fee in wei")
+	subnetUpgradeCmd.Flags().Bool("allow-fee-recipients", false, "Reward manager initially lets validators set fee recipients")
+	subnetUpgradeCmd.Flags().String("reward-address", "", "Reward manager initial reward address")
+}
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/subnet"
+	"github.com/spf13/cobra"
+)
+
+var subnetValidatorsCmd = &cobra.Command{
+	Use:   "validators",
+	Short: "Manage a subnet's validators",
+	Long: `Commands for listing and changing the validator set of a deployed subnet.
+
+Validators are added and removed with AddSubnetValidatorTx and
+RemoveSubnetValidatorTx on the P-Chain, signed by the subnet owner key. The
+nodes are given with --node-id. The local node runs without sybil protection,
+so it already validates every subnet it tracks and needs no AddSubnetValidatorTx.`,
+}
+
+var subnetValidatorsListCmd = &cobra.Command{
+	Use:               "list [subnet]",
+	Short:             "List a subnet's current validators",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSubnetArgs(1),
+	RunE:              runSubnetValidatorsList,
+}
+
+var subnetValidatorsAddCmd = &cobra.Command{
+	Use:   "add [subnet]",
+	Short: "Add validators to a subnet",
+	Long: `Add primary network validators to a subnet. The validation period defaults
+to starting shortly from now and ending with the node's primary network
+validation.
+
+Example:
+  kinetic subnet validators add mysubnet --node-id NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg --weight 20
+  kinetic subnet validators add mysubnet --node-id NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg --end-time +720h`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSubnetArgs(1),
+	RunE:              runSubnetValidatorsAdd,
+}
+
+var subnetValidatorsRemoveCmd = &cobra.Command{
+	Use:               "remove [subnet]",
+	Short:             "Remove validators from a subnet",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSubnetArgs(1),
+	RunE:              runSubnetValidatorsRemove,
+}
+
+func runSubnetValidatorsList(cmd *cobra.Command, args []string) error {
+	store, err := subnet.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	validators, err := subnet.ListValidators(cmd.Context(), store, args[0], config.Get())
+	if err != nil {
+		return err
+	}
+
+	return printResult(cmd, validators, func(w io.Writer) {
+		printValidators(w, validators)
+	})
+}
+
+func runSubnetValidatorsAdd(cmd *cobra.Command, args []string) error {
+	opts, err := validatorOptions(cmd)
+	if err != nil {
+		return err
+	}
+	opts.Weight, _ = cmd.Flags().GetUint64("weight")
+
+	now := time.Now()
+	if s, _ := cmd.Flags().GetString("start-time"); s != "" {
+		if opts.Start, err = subnet.ParseActivationTime(s, now); err != nil {
+			return err
+		}
+	}
+	if s, _ := cmd.Flags().GetString("end-time"); s != "" {
+		if opts.End, err = subnet.ParseActivationTime(s, now); err != nil {
+			return err
+		}
+	}
+
+	store, err := subnet.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	added, err := subnet.AddValidators(cmd.Context(), store, args[0], opts)
+	if err != nil {
+		return err
+	}
+
+	return printResult(cmd, added, func(w io.Writer) {
+		fmt.Fprintf(w, "Added %d validator(s) to subnet '%s'\n", len(added), args[0])
+		printValidators(w, added)
+	})
+}
+
+func runSubnetValidatorsRemove(cmd *cobra.Command, args []string) error {
+	opts, err := validatorOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	store, err := subnet.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	removed, err := subnet.RemoveValidators(cmd.Context(), store, args[0], opts)
+	if err != nil {
+		return err
+	}
+
+	return printResult(cmd, removed, func(w io.Writer) {
+		for _, nodeID := range removed {
+			fmt.Fprintf(w, "Removed %s from subnet '%s'\n", nodeID, args[0])
+		}
+	})
+}
+
+// validatorOptions reads the flags shared by validators add and remove
+func validatorOptions(cmd *cobra.Command) (subnet.ValidatorOptions, error) {
+	network := targetNetwork()
+	if network != "local" {
+		return subnet.ValidatorOptions{}, fmt.Errorf("managing validators on %s is not supported yet; only local is available", network)
+	}
+
+	nodeIDs, _ := cmd.Flags().GetStringSlice("node-id")
+	if len(nodeIDs) == 0 {
+		return subnet.ValidatorOptions{}, fmt.Errorf("%w; pass them with --node-id", subnet.ErrNoNodeIDs)
+	}
+	return subnet.ValidatorOptions{
+		Config:  config.Get(),
+		NodeIDs: nodeIDs,
+		Out:     progressWriter(cmd),
+	}, nil
+}
+
+// printValidators renders validators as a table
+func printValidators(w io.Writer, validators []subnet.Validator) {
+	if len(validators) == 0 {
+		fmt.Fprintln(w, "No validators")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NODE ID\tWEIGHT\tSTART\tEND")
+	for _, v := range validators {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", v.NodeID, v.Weight, v.StartTime.Format(time.RFC3339), v.EndTime.Format(time.RFC3339))
+	}
+	tw.Flush()
+}
+
+func init() {
+	subnetCmd.AddCommand(subnetValidatorsCmd)
+	subnetValidatorsCmd.AddCommand(subnetValidatorsListCmd)
+	subnetValidatorsCmd.AddCommand(subnetValidatorsAddCmd)
+	subnetValidatorsCmd.AddCommand(subnetValidatorsRemoveCmd)
+
+	for _, cmd := range []*cobra.Command{subnetValidatorsAddCmd, subnetValidatorsRemoveCmd} {
+		cmd.Flags().StringSlice("node-id", nil, "Node IDs to act on (required)")
+		cmd.Flags().StringP("network", "n", "", "Target network: local, fuji or mainnet (default is network.name, local unless configured)")
+		bindConfigFlag(cmd.Flags(), "network", "network.name")
+		cmd.RegisterFlagCompletionFunc("network", completeNetworks)
+	}
+	subnetValidatorsAddCmd.Flags().Uint64("weight", subnet.DefaultValidatorWeight, "Validator weight")
+	subnetValidatorsAddCmd.Flags().String("start-time", "", "Start time as RFC 3339, unix seconds or +duration (default shortly from now)")
+	subnetValidatorsAddCmd.Flags().String("end-time", "", "End time as RFC 3339, unix seconds or +duration (default the end of primary network validation)")
+}
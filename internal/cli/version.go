@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/node"
+	"github.com/kinetic-dev/kinetic/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show the Kinetic version and the running node's avalanchego version",
+	Long: `Show the Kinetic version, commit and build date. When the local node is
+running, its avalanchego version is shown too, with a warning if this Kinetic
+release has not been tested against it.`,
+	Args: cobra.NoArgs,
+	RunE: runVersion,
+}
+
+// versionOutput is the structured result of version
+type versionOutput struct {
+	Kinetic version.Info `json:"kinetic"`
+	// Node is the running node's version, if a node is running
+	Node    *node.NodeVersion `json:"node,omitempty"`
+	Warning string            `json:"warning,omitempty"`
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	result := versionOutput{Kinetic: version.Get()}
+
+	// Only a running node answers; any error means there is none to report
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Second)
+	defer cancel()
+	if v, err := node.NewAPIClient(config.Get().Node.APIPort).NodeVersion(ctx); err == nil {
+		result.Node = v
+		if err := node.CheckTested(v.Version); err != nil {
+			result.Warning = err.Error()
+		}
+	}
+
+	return printResult(cmd, result, func(w io.Writer) {
+		fmt.Fprintf(w, "Kinetic %s\n", result.Kinetic)
+		fmt.Fprintf(w, "Go %s %s\n", result.Kinetic.GoVersion, result.Kinetic.Platform)
+		if result.Node != nil {
+			fmt.Fprintf(w, "avalanchego %s (running node, database %s, RPC protocol %s)\n",
+				result.Node.Version, result.Node.DatabaseVersion, result.Node.RPCProtocolVersion)
+		}
+		if result.Warning != "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", result.Warning)
+		}
+	})
+}
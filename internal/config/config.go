@@ -1,160 +1,171 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
-
-	"github.com/kinetic-dev/kinetic/internal/system"
-	"github.com/spf13/viper"
+	"reflect"
 )
 
 var globalConfig *Config
 
 // Config holds the application configuration
 type Config struct {
+	// Version is the config file format, upgraded automatically when an
+	// older file is loaded
+	Version int `mapstructure:"version"`
+
+	// Profile names the active profile, whose profiles.<name> values
+	// override the config files
+	Profile string `mapstructure:"profile"`
+
 	// Node configuration
-	Node struct {
-		Port       int    `mapstructure:"port"`
-		APIPort    int    `mapstructure:"api_port"`
-		NetworkID  int    `mapstructure:"network_id"`
-		DBDir      string `mapstructure:"db_dir"`
-		LogDir     string `mapstructure:"log_dir"`
-		StakingDir string `mapstructure:"staking_dir"`
-	} `mapstructure:"node"`
+	Node NodeConfig `mapstructure:"node"`
 
 	// Docker configuration
-	Docker struct {
-		ImageTag      string `mapstructure:"image_tag"`
-		ContainerName string `mapstructure:"container_name"`
-	} `mapstructure:"docker"`
+	Docker DockerConfig `mapstructure:"docker"`
+
+	// Native process configuration
+	Native NativeConfig `mapstructure:"native"`
+
+	// Network configuration
+	Network NetworkConfig `mapstructure:"network"`
+
+	// Solidity compiler configuration
+	Compiler CompilerConfig `mapstructure:"compiler"`
+
+	// path is the config file saved to; origins records the source of each
+	// value; profiles lists the files defining each profile; loaded holds
+	// the values as loaded, so Save only writes changes; migrations lists
+	// the files upgraded to the current version. All are set by Load.
+	path       string
+	origins    map[string]Origin
+	profiles   map[string][]string
+	loaded     map[string]any
+	migrations []Migration
+}
+
+// NodeConfig holds the local node settings
+type NodeConfig struct {
+	Port      int `mapstructure:"port"`
+	APIPort   int `mapstructure:"api_port"`
+	NetworkID int `mapstructure:"network_id"`
+	// DBDir, LogDir and StakingDir default to db, logs and staking in the
+	// node data directory of the container. Paths may use ~ and environment
+	// variables.
+	DBDir      string `mapstructure:"db_dir"`
+	LogDir     string `mapstructure:"log_dir"`
+	StakingDir string `mapstructure:"staking_dir"`
+	Runtime    string `mapstructure:"runtime"`
+
+	// PluginDir holds VM binaries, named by VM ID, made available to the
+	// node. It defaults to plugins in the node data directory of the
+	// container.
+	PluginDir string `mapstructure:"plugin_dir"`
+	// TrackSubnets lists the subnet IDs the node validates and syncs
+	TrackSubnets []string `mapstructure:"track_subnets"`
+	// ChainConfigDir holds per-chain config and upgrade files, one directory
+	// per blockchain ID. It defaults to configs/chains in the node data
+	// directory of the container.
+	ChainConfigDir string `mapstructure:"chain_config_dir"`
+}
+
+// DockerConfig holds the container settings for the node
+type DockerConfig struct {
+	ImageTag      string `mapstructure:"image_tag"`
+	ContainerName string `mapstructure:"container_name"`
+}
+
+// NativeConfig holds the settings for running avalanchego without a container
+type NativeConfig struct {
+	BinaryPath string `mapstructure:"binary_path"`
+	Version    string `mapstructure:"version"`
+}
+
+// NetworkConfig holds the network that deploy commands target
+type NetworkConfig struct {
+	// Name is the target network: local, fuji or mainnet; empty is local
+	Name string `mapstructure:"name"`
+	// DefaultKey is the hex private key used when a command is not given
+	// --private-key; empty uses the local ewoq key
+	DefaultKey string `mapstructure:"default_key"`
+}
+
+// CompilerConfig holds the Solidity compiler settings for contracts
+type CompilerConfig struct {
+	// Version is the solc version contracts are compiled with
+	Version       string `mapstructure:"version"`
+	Optimizer     bool   `mapstructure:"optimizer"`
+	OptimizerRuns int    `mapstructure:"optimizer_runs"`
+	// EVMVersion is the EVM hard fork targeted, e.g. paris
+	EVMVersion string `mapstructure:"evm_version"`
 }
 
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
-	cfg := &Config{}
+	cfg := &Config{Version: CurrentVersion}
 
 	// Node defaults
 	cfg.Node.Port = 9650
 	cfg.Node.APIPort = 9651
 	cfg.Node.NetworkID = 12345 // Local network
+	cfg.Node.Runtime = "docker"
 
 	// Docker defaults
 	cfg.Docker.ImageTag = "avaplatform/avalanchego:latest"
 	cfg.Docker.ContainerName = "kinetic-node"
 
+	// Network defaults
+	cfg.Network.Name = "local"
+
+	// Compiler defaults; paris avoids PUSH0, which older Avalanche chains
+	// do not support
+	cfg.Compiler.Version = "0.8.20"
+	cfg.Compiler.Optimizer = true
+	cfg.Compiler.OptimizerRuns = 200
+	cfg.Compiler.EVMVersion = "paris"
+
 	return cfg
 }
 
-// Get returns the global config instance
+// Get returns the global config instance, or the defaults if no config has
+// been loaded
 func Get() *Config {
 	if globalConfig == nil {
-		// Return default config if not initialized
-		return &Config{
-			Node: struct {
-				Port       int    `mapstructure:"port"`
-				APIPort    int    `mapstructure:"api_port"`
-				NetworkID  int    `mapstructure:"network_id"`
-				DBDir      string `mapstructure:"db_dir"`
-				LogDir     string `mapstructure:"log_dir"`
-				StakingDir string `mapstructure:"staking_dir"`
-			}{
-				Port:       9650,
-				APIPort:    9651,
-				NetworkID:  12345,
-				DBDir:      "data",
-				LogDir:     "data",
-				StakingDir: "data",
-			},
-		}
+		globalConfig = DefaultConfig()
 	}
 	return globalConfig
 }
 
-// Load reads the config from the specified file
-func Load(configPath string) (*Config, error) {
-	if configPath == "" {
-		configPath = "config.json"
-	}
-
-	// Create default config if file doesn't exist
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		cfg := &Config{
-			Node: struct {
-				Port       int    `mapstructure:"port"`
-				APIPort    int    `mapstructure:"api_port"`
-				NetworkID  int    `mapstructure:"network_id"`
-				DBDir      string `mapstructure:"db_dir"`
-				LogDir     string `mapstructure:"log_dir"`
-				StakingDir string `mapstructure:"staking_dir"`
-			}{
-				Port:       9650,
-				APIPort:    9651,
-				NetworkID:  12345,
-				DBDir:      "data",
-				LogDir:     "data",
-				StakingDir: "data",
-			},
-		}
-		globalConfig = cfg
-		return cfg, nil
-	}
-
-	data, err := os.ReadFile(configPath)
+// Save writes the values changed since the config was loaded to the file it
+// was loaded from, or the user config file. With an active profile, changes
+// are saved to that profile. A config that was not loaded is written in full.
+func (c *Config) Save() error {
+	path, err := c.Path()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return err
 	}
 
-	cfg := &Config{}
-	if err := json.Unmarshal(data, cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	prefix := ""
+	if c.Profile != "" && c.loaded != nil {
+		prefix = "profiles." + c.Profile + "."
 	}
-
-	// Ensure data directory is absolute
-	if !filepath.IsAbs(cfg.Node.DBDir) {
-		absPath, err := filepath.Abs(cfg.Node.DBDir)
-		if err != nil {
-			return nil, fmt.Errorf("failed to resolve absolute path for data directory: %w", err)
+	values := c.values()
+	set := map[string]any{versionKey: CurrentVersion}
+	for key, value := range values {
+		if key == versionKey || c.loaded != nil && reflect.DeepEqual(value, c.loaded[key]) {
+			continue
 		}
-		cfg.Node.DBDir = absPath
-	}
-
-	globalConfig = cfg
-	return cfg, nil
-}
-
-// Save saves the current configuration to file
-func (c *Config) Save() error {
-	configDir, err := system.GetUserConfigDir()
-	if err != nil {
-		return fmt.Errorf("failed to get config directory: %w", err)
-	}
-
-	v := viper.New()
-	v.SetConfigFile(filepath.Join(configDir, "config.json"))
-
-	// Convert config struct to map
-	if err := v.MergeConfigMap(map[string]interface{}{
-		"node": map[string]interface{}{
-			"port":        c.Node.Port,
-			"api_port":    c.Node.APIPort,
-			"network_id":  c.Node.NetworkID,
-			"db_dir":      c.Node.DBDir,
-			"log_dir":     c.Node.LogDir,
-			"staking_dir": c.Node.StakingDir,
-		},
-		"docker": map[string]interface{}{
-			"image_tag":      c.Docker.ImageTag,
-			"container_name": c.Docker.ContainerName,
-		},
-	}); err != nil {
-		return fmt.Errorf("failed to merge config: %w", err)
+		if key == "profile" && prefix != "" {
+			// Profiles cannot select profiles; the active profile is saved
+			// at the top level
+			set[key] = value
+			continue
+		}
+		set[prefix+key] = value
 	}
-
-	if err := v.WriteConfig(); err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
+	if err := editFile(path, set, nil); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
 	}
 
+	c.loaded = values
 	return nil
 }
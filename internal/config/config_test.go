@@ -1,9 +1,17 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/spf13/pflag"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -27,7 +35,19 @@ func TestDefaultConfig(t *testing.T) {
 	}
 }
 
+// isolateConfigDir points the user config directory at a temporary directory
+func isolateConfigDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(dir, "share"))
+	return dir
+}
+
 func TestLoadConfig(t *testing.T) {
+	isolateConfigDir(t)
+
 	// Create a temporary directory for test files
 	tmpDir, err := os.MkdirTemp("", "config-test")
 	if err != nil {
@@ -89,6 +109,8 @@ func TestLoadConfig(t *testing.T) {
 }
 
 func TestSaveConfig(t *testing.T) {
+	isolateConfigDir(t)
+
 	// Create a temporary directory for test files
 	tmpDir, err := os.MkdirTemp("", "config-test")
 	if err != nil {
@@ -98,25 +120,16 @@ func TestSaveConfig(t *testing.T) {
 
 	// Create a test config
 	cfg := &Config{
-		Node: struct {
-			Port       int    `mapstructure:"port"`
-			APIPort    int    `mapstructure:"api_port"`
-			NetworkID  int    `mapstructure:"network_id"`
-			DBDir      string `mapstructure:"db_dir"`
-			LogDir     string `mapstructure:"log_dir"`
-			StakingDir string `mapstructure:"staking_dir"`
-		}{
+		Node: NodeConfig{
 			Port:       9670,
 			APIPort:    9671,
 			NetworkID:  98765,
 			DBDir:      "test/data",
 			LogDir:     "test/logs",
 			StakingDir: "test/staking",
+			Runtime:    "podman",
 		},
-		Docker: struct {
-			ImageTag      string `mapstructure:"image_tag"`
-			ContainerName string `mapstructure:"container_name"`
-		}{
+		Docker: DockerConfig{
 			ImageTag:      "test/avalanchego:latest",
 			ContainerName: "test-node",
 		},
@@ -144,3 +157,797 @@ func TestSaveConfig(t *testing.T) {
 		t.Errorf("expected saved image tag %s, got %s", cfg.Docker.ImageTag, savedCfg.Docker.ImageTag)
 	}
 }
+
+func TestLoadDirs(t *testing.T) {
+	home := isolateConfigDir(t)
+	nodeDir := filepath.Join(home, "share", "kinetic", "node")
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want [5]string
+	}{
+		{
+			name: "defaults",
+			want: [5]string{
+				filepath.Join(nodeDir, "kinetic-node", "db"),
+				filepath.Join(nodeDir, "kinetic-node", "logs"),
+				filepath.Join(nodeDir, "kinetic-node", "staking"),
+				filepath.Join(nodeDir, "kinetic-node", "plugins"),
+				filepath.Join(nodeDir, "kinetic-node", "configs", "chains"),
+			},
+		},
+		{
+			name: "per container",
+			env:  map[string]string{"KINETIC_DOCKER_CONTAINER_NAME": "devnet-node"},
+			want: [5]string{
+				filepath.Join(nodeDir, "devnet-node", "db"),
+				filepath.Join(nodeDir, "devnet-node", "logs"),
+				filepath.Join(nodeDir, "devnet-node", "staking"),
+				filepath.Join(nodeDir, "devnet-node", "plugins"),
+				filepath.Join(nodeDir, "devnet-node", "configs", "chains"),
+			},
+		},
+		{
+			name: "expanded",
+			env: map[string]string{
+				"KINETIC_NODE_DB_DIR":      "~/avalanche/db",
+				"KINETIC_NODE_LOG_DIR":     "$TEST_LOGS/node",
+				"KINETIC_NODE_STAKING_DIR": "~",
+				"TEST_LOGS":                filepath.Join(home, "logs"),
+			},
+			want: [5]string{
+				filepath.Join(home, "avalanche", "db"),
+				filepath.Join(home, "logs", "node"),
+				home,
+				filepath.Join(nodeDir, "kinetic-node", "plugins"),
+				filepath.Join(nodeDir, "kinetic-node", "configs", "chains"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := LoadWithOptions(LoadOptions{ProjectDir: t.TempDir()})
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			got := [5]string{cfg.Node.DBDir, cfg.Node.LogDir, cfg.Node.StakingDir, cfg.Node.PluginDir, cfg.Node.ChainConfigDir}
+			if got != tt.want {
+				t.Errorf("dirs = %v, want %v", got, tt.want)
+			}
+			// Loading only resolves the directories
+			if _, err := os.Stat(nodeDir); !os.IsNotExist(err) {
+				t.Errorf("loading created %s", nodeDir)
+			}
+		})
+	}
+}
+
+func TestLoadRelativeDirs(t *testing.T) {
+	isolateConfigDir(t)
+	projectDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(projectDir, "kinetic.yaml"), []byte("node:\n  db_dir: state/db\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KINETIC_NODE_LOG_DIR", "logs")
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadWithOptions(LoadOptions{ProjectDir: projectDir})
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	// Files are relative to themselves, the environment to the working
+	// directory
+	if want := filepath.Join(projectDir, "state", "db"); cfg.Node.DBDir != want {
+		t.Errorf("db_dir = %s, want %s", cfg.Node.DBDir, want)
+	}
+	if want := filepath.Join(cwd, "logs"); cfg.Node.LogDir != want {
+		t.Errorf("log_dir = %s, want %s", cfg.Node.LogDir, want)
+	}
+}
+
+func TestLoadLayers(t *testing.T) {
+	home := isolateConfigDir(t)
+	userDir := filepath.Join(home, "kinetic")
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(userDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	userFile := filepath.Join(userDir, "config.yaml")
+	os.WriteFile(userFile, []byte("node:\n  port: 9700\n  api_port: 9701\ndocker:\n  container_name: user-node\n"), 0644)
+	projectFile := filepath.Join(projectDir, "kinetic.toml")
+	os.WriteFile(projectFile, []byte("[node]\napi_port = 9801\nnetwork_id = 1337\n"), 0644)
+	t.Setenv("KINETIC_NODE_NETWORK_ID", "4242")
+	t.Setenv("KINETIC_NODE_TRACK_SUBNETS", "a,b")
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("runtime", "", "")
+	flags.String("image", "", "")
+	flags.Parse([]string{"--runtime", "native"})
+
+	cfg, err := LoadWithOptions(LoadOptions{
+		ProjectDir: projectDir,
+		Flags:      map[string]*pflag.Flag{"node.runtime": flags.Lookup("runtime"), "docker.image_tag": flags.Lookup("image")},
+	})
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		value  any
+		origin Origin
+	}{
+		{key: "node.port", value: 9700, origin: Origin{Source: SourceUser, Location: userFile}},
+		{key: "docker.container_name", value: "user-node", origin: Origin{Source: SourceUser, Location: userFile}},
+		{key: "node.api_port", value: 9801, origin: Origin{Source: SourceProject, Location: projectFile}},
+		{key: "node.network_id", value: 4242, origin: Origin{Source: SourceEnv, Location: "KINETIC_NODE_NETWORK_ID"}},
+		{key: "node.track_subnets", value: []string{"a", "b"}, origin: Origin{Source: SourceEnv, Location: "KINETIC_NODE_TRACK_SUBNETS"}},
+		{key: "node.runtime", value: "native", origin: Origin{Source: SourceFlag, Location: "--runtime"}},
+		{key: "docker.image_tag", value: "avaplatform/avalanchego:latest", origin: Origin{Source: SourceDefault}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			value, ok := cfg.Value(tt.key)
+			if !ok || !reflect.DeepEqual(value, tt.value) {
+				t.Errorf("Value(%s) = %v, want %v", tt.key, value, tt.value)
+			}
+			if origin := cfg.Origin(tt.key); origin != tt.origin {
+				t.Errorf("Origin(%s) = %+v, want %+v", tt.key, origin, tt.origin)
+			}
+		})
+	}
+
+	if path, _ := cfg.Path(); path != userFile {
+		t.Errorf("expected config to save to %s, got %s", userFile, path)
+	}
+
+	os.WriteFile(projectFile, []byte("[node]\napi_prot = 9801\n"), 0644)
+	if _, err := LoadWithOptions(LoadOptions{ProjectDir: projectDir}); err == nil || !strings.Contains(err.Error(), "node.api_prot") {
+		t.Errorf("expected unknown key error, got %v", err)
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		want    any
+		wantErr bool
+	}{
+		{key: "node.api_port", value: "9700", want: 9700},
+		{key: "node.api_port", value: "high", wantErr: true},
+		{key: "node.track_subnets", value: "a, b,,c", want: []string{"a", "b", "c"}},
+		{key: "node.track_subnets", value: "", want: []string{}},
+		{key: "docker.container_name", value: "team-node", want: "team-node"},
+		{key: "node.api_prot", value: "9700", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			got, err := ParseValue(tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFileValue(t *testing.T) {
+	for _, ext := range []string{"yaml", "json", "toml"} {
+		t.Run(ext, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config."+ext)
+			if err := SetFileValue(path, "node.api_port", 9700); err != nil {
+				t.Fatalf("SetFileValue() error = %v", err)
+			}
+			if err := SetFileValue(path, "node.track_subnets", []string{"a", "b"}); err != nil {
+				t.Fatalf("SetFileValue() error = %v", err)
+			}
+			if err := SetFileValue(path, "docker.container_name", "team-node"); err != nil {
+				t.Fatalf("SetFileValue() error = %v", err)
+			}
+			if err := ValidateFile(path); err != nil {
+				t.Fatalf("ValidateFile() error = %v", err)
+			}
+
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Node.APIPort != 9700 || cfg.Docker.ContainerName != "team-node" || !reflect.DeepEqual(cfg.Node.TrackSubnets, []string{"a", "b"}) {
+				t.Errorf("unexpected config after set: %+v", cfg)
+			}
+
+			removed, err := UnsetFileValue(path, "docker.container_name")
+			if err != nil || !removed {
+				t.Fatalf("UnsetFileValue() = %v, %v", removed, err)
+			}
+			if removed, _ := UnsetFileValue(path, "docker.container_name"); removed {
+				t.Error("expected second unset to report nothing removed")
+			}
+			values, err := FileValues(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := values["docker.container_name"]; ok {
+				t.Error("expected docker.container_name to be removed")
+			}
+			if len(values) != 2 {
+				t.Errorf("expected 2 values left, got %v", values)
+			}
+		})
+	}
+
+	if err := SetFileValue(filepath.Join(t.TempDir(), "config.yaml"), "node.api_prot", 1); err == nil {
+		t.Error("expected error setting unknown key")
+	}
+}
+
+func TestSetFileValueKeepsComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kinetic.yaml")
+	if err := WriteDefaultFile(path); err != nil {
+		t.Fatalf("WriteDefaultFile() error = %v", err)
+	}
+	if err := SetFileValue(path, "node.api_port", 9700); err != nil {
+		t.Fatalf("SetFileValue() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# Kinetic configuration", "# HTTP API port", "api_port: 9700"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in edited file:\n%s", want, data)
+		}
+	}
+}
+
+func TestDefaultFileContents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := WriteDefaultFile(path); err != nil {
+		t.Fatalf("WriteDefaultFile() error = %v", err)
+	}
+	if err := ValidateFile(path); err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+
+	values, err := FileValues(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range Keys() {
+		if _, ok := values[key]; !ok && key != versionKey {
+			t.Errorf("default file is missing %s", key)
+		}
+		if keyComments[key] == "" {
+			t.Errorf("no comment for %s", key)
+		}
+	}
+
+	settings, err := readConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, _ := fileVersion(settings, path); version != CurrentVersion {
+		t.Errorf("default file version = %d, want %d", version, CurrentVersion)
+	}
+
+	if err := WriteDefaultFile(filepath.Join(t.TempDir(), "config.json")); err == nil {
+		t.Error("expected error writing defaults to a JSON file")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	os.WriteFile(file, nil, 0644)
+
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		want   []string
+	}{
+		{name: "defaults", modify: func(cfg *Config) {}},
+		{
+			name:   "port out of range",
+			modify: func(cfg *Config) { cfg.Node.Port = 70000 },
+			want:   []string{"node.port: port 70000 is out of range"},
+		},
+		{
+			name:   "port conflict",
+			modify: func(cfg *Config) { cfg.Node.APIPort = cfg.Node.Port },
+			want:   []string{"node.api_port: port 9650 is also used by node.port"},
+		},
+		{
+			name:   "mainnet",
+			modify: func(cfg *Config) { cfg.Node.NetworkID = 1 },
+			want:   []string{"node.network_id: network ID 1 is mainnet"},
+		},
+		{
+			name:   "network ID out of range",
+			modify: func(cfg *Config) { cfg.Node.NetworkID = 0 },
+			want:   []string{"node.network_id: network ID 0 is out of range"},
+		},
+		{
+			name:   "runtime",
+			modify: func(cfg *Config) { cfg.Node.Runtime = "rkt" },
+			want:   []string{`node.runtime: unsupported runtime "rkt"`},
+		},
+		{
+			name:   "writable dirs",
+			modify: func(cfg *Config) { cfg.Node.DBDir = filepath.Join(dir, "db"); cfg.Node.LogDir = dir },
+		},
+		{
+			name:   "relative dir",
+			modify: func(cfg *Config) { cfg.Node.LogDir = "logs" },
+			want:   []string{"node.log_dir: directory logs is not an absolute path"},
+		},
+		{
+			name:   "shared dir",
+			modify: func(cfg *Config) { cfg.Node.DBDir = dir; cfg.Node.LogDir = dir + "/"; cfg.Node.StakingDir = dir },
+			want: []string{
+				"node.log_dir: directory " + dir + "/ is also node.db_dir",
+				"node.staking_dir: directory " + dir + " is also node.db_dir",
+			},
+		},
+		{
+			name:   "empty docker settings",
+			modify: func(cfg *Config) { cfg.Docker.ImageTag = ""; cfg.Docker.ContainerName = "" },
+			want:   []string{"docker.image_tag: the node image must not be empty", "docker.container_name: the container name must not be empty"},
+		},
+		{
+			// Writability is left to CheckDirs
+			name:   "dir under a file",
+			modify: func(cfg *Config) { cfg.Node.StakingDir = filepath.Join(file, "staking") },
+		},
+		{
+			name: "all problems",
+			modify: func(cfg *Config) {
+				cfg.Node.Port = 0
+				cfg.Node.NetworkID = 5
+				cfg.Node.PluginDir = "plugins"
+			},
+			want: []string{"node.port:", "node.network_id: network ID 5 is fuji", "node.plugin_dir:"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var errs ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("expected ValidationErrors, got %v", err)
+			}
+			if len(errs) != len(tt.want) {
+				t.Errorf("expected %d problems, got %v", len(tt.want), err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected %q in %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadValidates(t *testing.T) {
+	isolateConfigDir(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("node:\n  port: 9651\n"), 0644)
+	t.Setenv("KINETIC_NODE_NETWORK_ID", "1")
+
+	cfg, err := Load(path)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	if cfg == nil || Get() != cfg {
+		t.Error("expected an invalid config to still be loaded")
+	}
+	want := []string{
+		"node.api_port: port 9651 is also used by node.port",
+		"node.network_id: network ID 1 is mainnet; use a local network ID such as 12345 (set by KINETIC_NODE_NETWORK_ID)",
+	}
+	for _, w := range want {
+		if !strings.Contains(err.Error(), w) {
+			t.Errorf("expected %q in %v", w, err)
+		}
+	}
+}
+
+func TestCheckDirs(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	os.WriteFile(file, nil, 0644)
+
+	cfg := DefaultConfig()
+	cfg.Node.DBDir = filepath.Join(dir, "db")
+	cfg.Node.LogDir = dir
+	if err := cfg.CheckDirs(); err != nil {
+		t.Errorf("CheckDirs() error = %v", err)
+	}
+	if _, err := os.Stat(cfg.Node.DBDir); !os.IsNotExist(err) {
+		t.Errorf("CheckDirs() created %s", cfg.Node.DBDir)
+	}
+
+	cfg.Node.StakingDir = filepath.Join(file, "staking")
+	cfg.Node.PluginDir = file
+	var errs ValidationErrors
+	if err := cfg.CheckDirs(); !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("expected two ValidationErrors, got %v", err)
+	}
+	if !strings.Contains(errs.Error(), "node.staking_dir: "+file+" is not a directory") || errs[1].Key != "node.plugin_dir" {
+		t.Errorf("unexpected problems: %v", errs)
+	}
+}
+
+func TestCheckPorts(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	cfg := DefaultConfig()
+	cfg.Node.APIPort = l.Addr().(*net.TCPAddr).Port
+	err = cfg.CheckPorts()
+	if err == nil || !strings.Contains(err.Error(), "node.api_port: port "+strconv.Itoa(cfg.Node.APIPort)+" is already in use") {
+		t.Errorf("expected port in use error, got %v", err)
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	home := isolateConfigDir(t)
+	projectDir := t.TempDir()
+	userFile := filepath.Join(home, "kinetic", "config.yaml")
+	os.MkdirAll(filepath.Dir(userFile), 0755)
+	os.WriteFile(userFile, []byte(`node:
+  api_port: 9700
+profile: devnet
+profiles:
+  devnet:
+    node:
+      api_port: 9800
+      network_id: 1337
+    docker:
+      container_name: devnet-node
+  fuji:
+    network:
+      name: fuji
+`), 0644)
+	projectFile := filepath.Join(projectDir, "kinetic.yaml")
+	os.WriteFile(projectFile, []byte("docker:\n  container_name: project-node\nprofiles:\n  devnet:\n    node:\n      network_id: 4242\n"), 0644)
+
+	load := func(flags ...string) *Config {
+		t.Helper()
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		fs.String("profile", "", "")
+		fs.Parse(flags)
+		cfg, err := LoadWithOptions(LoadOptions{ProjectDir: projectDir, Flags: map[string]*pflag.Flag{"profile": fs.Lookup("profile")}})
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		return cfg
+	}
+
+	cfg := load()
+	if cfg.Profile != "devnet" {
+		t.Errorf("expected saved profile devnet, got %q", cfg.Profile)
+	}
+	tests := []struct {
+		key    string
+		value  any
+		origin Origin
+	}{
+		{key: "node.api_port", value: 9800, origin: Origin{Source: SourceProfile, Location: userFile, Profile: "devnet"}},
+		{key: "node.network_id", value: 4242, origin: Origin{Source: SourceProfile, Location: projectFile, Profile: "devnet"}},
+		{key: "docker.container_name", value: "devnet-node", origin: Origin{Source: SourceProfile, Location: userFile, Profile: "devnet"}},
+		{key: "network.name", value: "local", origin: Origin{Source: SourceDefault}},
+	}
+	for _, tt := range tests {
+		if value, _ := cfg.Value(tt.key); !reflect.DeepEqual(value, tt.value) {
+			t.Errorf("Value(%s) = %v, want %v", tt.key, value, tt.value)
+		}
+		if origin := cfg.Origin(tt.key); origin != tt.origin {
+			t.Errorf("Origin(%s) = %+v, want %+v", tt.key, origin, tt.origin)
+		}
+	}
+	want := []Profile{{Name: "devnet", Files: []string{userFile, projectFile}}, {Name: "fuji", Files: []string{userFile}}}
+	if !reflect.DeepEqual(cfg.Profiles(), want) {
+		t.Errorf("Profiles() = %+v, want %+v", cfg.Profiles(), want)
+	}
+
+	// Environment variables override the profile and select another one
+	t.Setenv("KINETIC_NODE_API_PORT", "9900")
+	t.Setenv("KINETIC_PROFILE", "fuji")
+	cfg = load()
+	if cfg.Profile != "fuji" || cfg.Network.Name != "fuji" || cfg.Node.APIPort != 9900 || cfg.Docker.ContainerName != "project-node" {
+		t.Errorf("unexpected config with KINETIC_PROFILE=fuji: %+v", cfg)
+	}
+
+	// The flag overrides the environment
+	if cfg = load("--profile", "devnet"); cfg.Profile != "devnet" || cfg.Origin("profile").Source != SourceFlag {
+		t.Errorf("expected --profile to select devnet, got %q from %+v", cfg.Profile, cfg.Origin("profile"))
+	}
+
+	t.Setenv("KINETIC_PROFILE", "staging")
+	_, err := LoadWithOptions(LoadOptions{ProjectDir: projectDir})
+	if err == nil || !strings.Contains(err.Error(), "profile: profile staging is not defined (available: devnet, fuji) (set by KINETIC_PROFILE)") {
+		t.Errorf("expected undefined profile error, got %v", err)
+	}
+}
+
+func TestSaveProfile(t *testing.T) {
+	home := isolateConfigDir(t)
+	userFile := filepath.Join(home, "kinetic", "config.yaml")
+	if err := CreateProfile(userFile, "devnet", map[string]any{"node.api_port": 9800}); err != nil {
+		t.Fatalf("CreateProfile() error = %v", err)
+	}
+	if err := CreateProfile(userFile, "devnet", nil); err == nil {
+		t.Error("expected error creating an existing profile")
+	}
+	if err := CreateProfile(userFile, "Dev Net", nil); err == nil {
+		t.Error("expected error for an invalid profile name")
+	}
+	if err := CreateProfile(userFile, "empty", nil); err != nil {
+		t.Fatalf("CreateProfile() error = %v", err)
+	}
+	if err := SetFileValue(userFile, "profile", "devnet"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadWithOptions(LoadOptions{ProjectDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Node.APIPort != 9800 || len(cfg.Profiles()) != 2 {
+		t.Fatalf("unexpected profile config: %+v, %+v", cfg.Node, cfg.Profiles())
+	}
+
+	// Changes are saved to the active profile, leaving other values alone
+	cfg.Node.TrackSubnets = []string{"subnetA"}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	values, err := FileValues(userFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"profile":                            "devnet",
+		"profiles.devnet.node.api_port":      9800,
+		"profiles.devnet.node.track_subnets": []any{"subnetA"},
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("saved values = %v, want %v", values, want)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	isolateConfigDir(t)
+	dir := t.TempDir()
+
+	// Version 0 files used the Go field names as keys
+	legacy := `# Team node
+node:
+  # API port
+  APIPort: 9701
+  networkid: 4321
+docker:
+  ContainerName: team-node
+profiles:
+  devnet:
+    node:
+      apiport: 9801
+`
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Node.APIPort != 9701 || cfg.Node.NetworkID != 4321 || cfg.Docker.ContainerName != "team-node" {
+		t.Errorf("legacy values not loaded: %+v, %+v", cfg.Node, cfg.Docker)
+	}
+	migrations := cfg.Migrations()
+	if len(migrations) != 1 || migrations[0].From != 0 || migrations[0].To != CurrentVersion {
+		t.Fatalf("Migrations() = %+v", migrations)
+	}
+
+	backup, err := os.ReadFile(migrations[0].Backup)
+	if err != nil || string(backup) != legacy {
+		t.Errorf("backup = %q, %v; want the original file", backup, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# Team node", "version: 1", "api_port: 9701", "container_name: team-node"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in upgraded file:\n%s", want, data)
+		}
+	}
+	values, err := FileValues(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["profiles.devnet.node.api_port"] != 9801 {
+		t.Errorf("profile key not renamed: %v", values)
+	}
+
+	// Current files are left alone
+	cfg, err = Load(path)
+	if err != nil || len(cfg.Migrations()) != 0 {
+		t.Errorf("reload: migrations = %v, err = %v", cfg.Migrations(), err)
+	}
+
+	newer := filepath.Join(dir, "newer.json")
+	if err := os.WriteFile(newer, []byte(`{"version": 99}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(newer); err == nil || !strings.Contains(err.Error(), "upgrade Kinetic") {
+		t.Errorf("expected error loading a newer config version, got %v", err)
+	}
+	if err := SetFileValue(path, versionKey, 2); err == nil {
+		t.Error("expected error setting the version")
+	}
+}
+
+func TestMigrateBaselineSave(t *testing.T) {
+	home := isolateConfigDir(t)
+
+	// The file Config.Save wrote before config versions, for the config
+	// Get returned when none was loaded
+	baseline := `{
+  "docker": {
+    "container_name": "",
+    "image_tag": ""
+  },
+  "node": {
+    "api_port": 9651,
+    "db_dir": "data",
+    "log_dir": "data",
+    "network_id": 12345,
+    "port": 9650,
+    "staking_dir": "data"
+  }
+}`
+	path := filepath.Join(home, "kinetic", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(baseline), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if len(cfg.Migrations()) != 1 {
+		t.Fatalf("Migrations() = %+v, want one", cfg.Migrations())
+	}
+	defaults := DefaultConfig()
+	if cfg.Docker.ImageTag != defaults.Docker.ImageTag || cfg.Docker.ContainerName != defaults.Docker.ContainerName {
+		t.Errorf("docker = %+v, want the defaults", cfg.Docker)
+	}
+	nodeDir := filepath.Join(home, "share", "kinetic", "node", defaults.Docker.ContainerName)
+	for dir, want := range map[string]string{
+		cfg.Node.DBDir:      filepath.Join(nodeDir, "db"),
+		cfg.Node.LogDir:     filepath.Join(nodeDir, "logs"),
+		cfg.Node.StakingDir: filepath.Join(nodeDir, "staking"),
+	} {
+		if dir != want {
+			t.Errorf("node dir = %s, want %s", dir, want)
+		}
+	}
+
+	values, err := FileValues(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"docker.image_tag", "docker.container_name", "node.db_dir", "node.log_dir", "node.staking_dir"} {
+		if _, ok := values[key]; ok {
+			t.Errorf("upgraded file still sets %s: %v", key, values)
+		}
+	}
+	if fmt.Sprint(values["node.api_port"]) != "9651" {
+		t.Errorf("upgraded file lost node.api_port: %v", values)
+	}
+	if data, err := os.ReadFile(path); err != nil || !strings.Contains(string(data), `"version": 1`) {
+		t.Errorf("upgraded file = %s, %v; want version 1", data, err)
+	}
+
+	// Saving and loading the upgraded config keeps its values
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	loaded, err := Load("")
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if len(loaded.Migrations()) != 0 || !reflect.DeepEqual(loaded.values(), cfg.values()) {
+		t.Errorf("reloaded values = %v, want %v", loaded.values(), cfg.values())
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	for _, ext := range []string{"yaml", "json", "toml"} {
+		t.Run(ext, func(t *testing.T) {
+			isolateConfigDir(t)
+			dir := t.TempDir()
+			path := filepath.Join(dir, "config."+ext)
+
+			// Give every field a value that differs from its default, so a
+			// field Save or Load forgets is caught
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			v := reflect.ValueOf(cfg).Elem()
+			i := 0
+			walkFields(v, "", func(key string, field reflect.Value) {
+				i++
+				switch {
+				case key == versionKey || key == "profile":
+					// Managed by Kinetic and covered by TestSaveProfile
+				case field.Kind() == reflect.String:
+					field.SetString(filepath.Join(dir, key))
+				case field.Kind() == reflect.Int:
+					field.SetInt(field.Int() + 7 + int64(i))
+				case field.Kind() == reflect.Bool:
+					field.SetBool(!field.Bool())
+				case field.Kind() == reflect.Slice:
+					field.Set(reflect.ValueOf([]string{"a", "b"}))
+				default:
+					t.Fatalf("no test value for %s of kind %s", key, field.Kind())
+				}
+			})
+			if err := cfg.Save(); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+
+			values, err := FileValues(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range Keys() {
+				if _, ok := values[key]; !ok && key != versionKey && key != "profile" {
+					t.Errorf("Save did not write %s", key)
+				}
+			}
+
+			// The values are not valid config, only distinct
+			loaded, err := Load(path)
+			var verrs ValidationErrors
+			if err != nil && !errors.As(err, &verrs) {
+				t.Fatalf("Failed to load saved config: %v", err)
+			}
+			if !reflect.DeepEqual(loaded.values(), cfg.values()) {
+				t.Errorf("loaded values = %v, want %v", loaded.values(), cfg.values())
+			}
+		})
+	}
+}
@@ -0,0 +1,476 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kinetic-dev/kinetic/internal/system"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigFile returns the project config file in dir: an existing
+// kinetic.yaml, kinetic.yml, kinetic.json or kinetic.toml, or kinetic.yaml
+func ProjectConfigFile(dir string) string {
+	if path := findConfigFile(dir, ProjectConfigName); path != "" {
+		return path
+	}
+	return filepath.Join(dir, ProjectConfigName+".yaml")
+}
+
+// ParseValue converts a command line value to the type of a config key.
+// Lists are comma separated.
+func ParseValue(key, s string) (any, error) {
+	field, ok := fieldByKey(key)
+	if !ok {
+		return nil, fmt.Errorf("unknown config key %s", key)
+	}
+	switch field.Kind() {
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an integer", key)
+		}
+		return n, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", key)
+		}
+		return b, nil
+	case reflect.Slice:
+		items := []string{}
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	default:
+		return s, nil
+	}
+}
+
+// fieldByKey returns the default config field of a dotted key. Keys of a
+// profile, profiles.<name>.<key>, resolve to the field of <key>.
+func fieldByKey(key string) (reflect.Value, bool) {
+	if _, rest, ok := profileKey(key); ok {
+		if rest == "profile" || rest == versionKey {
+			return reflect.Value{}, false
+		}
+		key = rest
+	}
+	var found reflect.Value
+	walkFields(reflect.ValueOf(DefaultConfig()).Elem(), "", func(k string, v reflect.Value) {
+		if k == key {
+			found = v
+		}
+	})
+	return found, found.IsValid()
+}
+
+// profileKey splits a key of the form profiles.<name>.<key>
+func profileKey(key string) (profile, rest string, ok bool) {
+	parts := strings.SplitN(key, ".", 3)
+	if len(parts) != 3 || parts[0] != profilesKey {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// FileValues returns the values set in a config file by dotted key; a missing
+// file has none
+func FileValues(path string) (map[string]any, error) {
+	settings, err := readConfigFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]any{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]any)
+	flattenValues(settings, "", values)
+	delete(values, versionKey)
+	return values, nil
+}
+
+// flattenValues collects the leaf values of nested settings by dotted key
+func flattenValues(settings map[string]any, prefix string, values map[string]any) {
+	for k, v := range settings {
+		if nested, ok := v.(map[string]any); ok {
+			flattenValues(nested, prefix+k+".", values)
+			continue
+		}
+		values[prefix+k] = v
+	}
+}
+
+// ValidateFile checks that a config file parses and sets only known keys
+// with values of the right type
+func ValidateFile(path string) error {
+	settings, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := fileVersion(settings, path); err != nil {
+		return err
+	}
+	for _, key := range flattenKeys(settings, "") {
+		if _, ok := fieldByKey(key); !ok {
+			return fmt.Errorf("unknown config key %s in %s", key, path)
+		}
+	}
+	v := viper.New()
+	v.MergeConfigMap(settings)
+	if err := v.Unmarshal(&Config{}); err != nil {
+		return fmt.Errorf("invalid config in %s: %w", path, err)
+	}
+	return nil
+}
+
+// SetFileValue sets a key in a config file, creating the file if needed.
+// YAML files are edited in place so their comments are kept.
+func SetFileValue(path, key string, value any) error {
+	if key == versionKey {
+		return fmt.Errorf("%s is managed by Kinetic and cannot be set", versionKey)
+	}
+	if _, ok := fieldByKey(key); !ok {
+		return fmt.Errorf("unknown config key %s", key)
+	}
+	return editFile(path, map[string]any{key: value}, nil)
+}
+
+// UnsetFileValue removes a key from a config file, reporting whether it was set
+func UnsetFileValue(path, key string) (bool, error) {
+	if key == versionKey {
+		return false, fmt.Errorf("%s is managed by Kinetic and cannot be unset", versionKey)
+	}
+	values, err := FileValues(path)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := values[key]; !ok {
+		return false, nil
+	}
+	return true, editFile(path, nil, []string{key})
+}
+
+// profileNamePattern matches valid profile names
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// CreateProfile adds a profile with the given values, by dotted key, to a
+// config file, creating the file if needed
+func CreateProfile(path, name string, values map[string]any) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	settings, err := readConfigFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if profiles, ok := settings[profilesKey].(map[string]any); ok {
+		if _, ok := profiles[name]; ok {
+			return fmt.Errorf("profile %s already exists in %s", name, path)
+		}
+	}
+
+	set := map[string]any{profilesKey + "." + name: map[string]any{}}
+	for key, value := range values {
+		if _, ok := fieldByKey(key); !ok || key == "profile" || key == versionKey {
+			return fmt.Errorf("unknown config key %s", key)
+		}
+		set[profilesKey+"."+name+"."+key] = value
+	}
+	return editFile(path, set, nil)
+}
+
+// editFile sets and removes dotted keys in a config file, creating it at the
+// current version if needed
+func editFile(path string, set map[string]any, unset []string) error {
+	if err := system.EnsureDir(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if _, ok := set[versionKey]; !ok {
+			set = copyWith(set, versionKey, CurrentVersion)
+		}
+	}
+
+	switch ext := strings.TrimPrefix(filepath.Ext(path), "."); ext {
+	case "yaml", "yml":
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+		if data, err = editYAML(data, set, unset); err != nil {
+			return fmt.Errorf("failed to edit %s: %w", path, err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write config file: %w", err)
+		}
+		return nil
+	case "json", "toml":
+		settings, err := readConfigFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			settings, err = map[string]any{}, nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, key := range sortedKeys(set) {
+			editMap(settings, strings.Split(key, "."), set[key], false)
+		}
+		for _, key := range unset {
+			editMap(settings, strings.Split(key, "."), nil, true)
+		}
+
+		v := viper.New()
+		v.SetConfigFile(path)
+		if err := v.MergeConfigMap(settings); err != nil {
+			return fmt.Errorf("failed to merge config: %w", err)
+		}
+		if err := v.WriteConfigAs(path); err != nil {
+			return fmt.Errorf("failed to write config file: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported config file type %q (supported: yaml, json, toml)", ext)
+	}
+}
+
+// editMap sets or removes a dotted key in nested settings, dropping maps left
+// empty by a removal
+func editMap(settings map[string]any, segments []string, value any, remove bool) {
+	if len(segments) == 1 {
+		if remove {
+			delete(settings, segments[0])
+		} else {
+			settings[segments[0]] = value
+		}
+		return
+	}
+	nested, ok := settings[segments[0]].(map[string]any)
+	if !ok {
+		if remove {
+			return
+		}
+		nested = make(map[string]any)
+		settings[segments[0]] = nested
+	}
+	editMap(nested, segments[1:], value, remove)
+	if remove && len(nested) == 0 {
+		delete(settings, segments[0])
+	}
+}
+
+// copyWith returns a copy of m with key set to value
+func copyWith(m map[string]any, key string, value any) map[string]any {
+	c := make(map[string]any, len(m)+1)
+	for k, v := range m {
+		c[k] = v
+	}
+	c[key] = value
+	return c
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// editYAML sets and removes dotted keys in a YAML document, keeping comments
+// and the order of existing keys
+func editYAML(data []byte, set map[string]any, unset []string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("top level is not a mapping")
+	}
+
+	for _, key := range sortedKeys(set) {
+		var node yaml.Node
+		if err := node.Encode(set[key]); err != nil {
+			return nil, err
+		}
+		if err := setYAMLKey(root, strings.Split(key, "."), &node); err != nil {
+			return nil, err
+		}
+	}
+	for _, key := range unset {
+		removeYAMLKey(root, strings.Split(key, "."))
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// yamlValue returns the index of a key's value node in a mapping, or -1
+func yamlValue(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if strings.EqualFold(mapping.Content[i].Value, key) {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// setYAMLKey sets a nested key in a mapping, creating intermediate mappings
+func setYAMLKey(mapping *yaml.Node, segments []string, value *yaml.Node) error {
+	idx := yamlValue(mapping, segments[0])
+	if len(segments) == 1 {
+		if idx < 0 {
+			mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: segments[0]}, value)
+			return nil
+		}
+		// Keep comments attached to the old value
+		old := mapping.Content[idx]
+		value.HeadComment, value.LineComment, value.FootComment = old.HeadComment, old.LineComment, old.FootComment
+		mapping.Content[idx] = value
+		return nil
+	}
+
+	if idx < 0 {
+		nested := &yaml.Node{Kind: yaml.MappingNode}
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: segments[0]}, nested)
+		return setYAMLKey(nested, segments[1:], value)
+	}
+	nested := mapping.Content[idx]
+	if nested.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s is not a mapping", segments[0])
+	}
+	// An empty profile is written as {}; keys added to it use block style
+	nested.Style &^= yaml.FlowStyle
+	return setYAMLKey(nested, segments[1:], value)
+}
+
+// removeYAMLKey removes a nested key from a mapping, dropping mappings left
+// empty
+func removeYAMLKey(mapping *yaml.Node, segments []string) {
+	idx := yamlValue(mapping, segments[0])
+	if idx < 0 {
+		return
+	}
+	if len(segments) > 1 {
+		nested := mapping.Content[idx]
+		if nested.Kind != yaml.MappingNode {
+			return
+		}
+		removeYAMLKey(nested, segments[1:])
+		if len(nested.Content) > 0 {
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content[:idx-1], mapping.Content[idx+1:]...)
+}
+
+// WriteDefaultFile writes a commented config file with every key set to its
+// default value
+func WriteDefaultFile(path string) error {
+	if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+		return fmt.Errorf("config init writes YAML; use a .yaml path instead of %s", filepath.Base(path))
+	}
+	if err := system.EnsureDir(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(DefaultFileContents()), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// keyComments describe config keys in generated config files
+var keyComments = map[string]string{
+	"version":                 "Config file format version; upgraded automatically",
+	"profile":                 "Active profile; empty uses no profile",
+	"node":                    "Local Avalanche node",
+	"node.port":               "Staking (peer-to-peer) port",
+	"node.api_port":           "HTTP API port",
+	"node.network_id":         "Network ID; 12345 is the local network",
+	"node.db_dir":             "Database directory; empty is <data dir>/node/<container_name>/db",
+	"node.log_dir":            "Log directory; empty is <data dir>/node/<container_name>/logs",
+	"node.staking_dir":        "Staking certificate directory; empty is <data dir>/node/<container_name>/staking",
+	"node.runtime":            "How the node runs: docker, podman or native",
+	"node.plugin_dir":         "VM plugin binaries, named by VM ID; empty is <data dir>/node/<container_name>/plugins",
+	"node.track_subnets":      "Subnet IDs the node tracks",
+	"node.chain_config_dir":   "Per-chain config and upgrade files; empty is <data dir>/node/<container_name>/configs/chains",
+	"docker":                  "Container runtimes (docker and podman)",
+	"docker.image_tag":        "avalanchego image",
+	"docker.container_name":   "Node container name",
+	"native":                  "Native runtime",
+	"native.binary_path":      "avalanchego binary; empty downloads the pinned version",
+	"native.version":          "avalanchego version to download",
+	"network":                 "Network targeted by deploy commands",
+	"network.name":            "local, fuji or mainnet",
+	"network.default_key":     "Hex private key used without --private-key; empty uses the local ewoq key",
+	"compiler":                "Solidity compiler",
+	"compiler.version":        "solc version",
+	"compiler.optimizer":      "Enable the optimizer",
+	"compiler.optimizer_runs": "Expected runs of each function, for the optimizer",
+	"compiler.evm_version":    "Target EVM version; paris avoids PUSH0 for older Avalanche chains",
+}
+
+// DefaultFileContents returns a commented YAML config with the defaults
+func DefaultFileContents() string {
+	var b strings.Builder
+	b.WriteString("# Kinetic configuration\n")
+	b.WriteString("#\n")
+	b.WriteString("# Values here override the built-in defaults and are overridden by\n")
+	b.WriteString("# KINETIC_* environment variables and command line flags.\n")
+
+	values := DefaultConfig().values()
+	section := ""
+	// Keys are written in the order of the Config struct
+	var keys []string
+	walkFields(reflect.ValueOf(DefaultConfig()).Elem(), "", func(key string, _ reflect.Value) {
+		keys = append(keys, key)
+	})
+	for _, key := range keys {
+		value, _ := yaml.Marshal(values[key])
+		parent, name, nested := strings.Cut(key, ".")
+		if !nested {
+			fmt.Fprintf(&b, "\n# %s\n%s: %s", keyComments[key], key, value)
+			section = ""
+			continue
+		}
+		if parent != section {
+			section = parent
+			fmt.Fprintf(&b, "\n# %s\n%s:\n", keyComments[parent], parent)
+		}
+		fmt.Fprintf(&b, "  # %s\n  %s: %s", keyComments[key], name, value)
+	}
+
+	b.WriteString("\n# Profiles override the values above when selected with --profile,\n")
+	b.WriteString("# KINETIC_PROFILE or 'kinetic profile use', e.g.\n")
+	b.WriteString("#\n")
+	b.WriteString("# profiles:\n")
+	b.WriteString("#   devnet:\n")
+	b.WriteString("#     node:\n")
+	b.WriteString("#       api_port: 9700\n")
+	b.WriteString("#     docker:\n")
+	b.WriteString("#       container_name: devnet-node\n")
+	return b.String()
+}
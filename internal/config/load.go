@@ -0,0 +1,483 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kinetic-dev/kinetic/internal/system"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix prefixes environment variables that override config keys, e.g.
+// KINETIC_NODE_API_PORT for node.api_port
+const EnvPrefix = "KINETIC"
+
+// ProjectConfigName is the base name of the project config file,
+// kinetic.yaml, kinetic.json or kinetic.toml, which also marks the project
+// root
+const ProjectConfigName = "kinetic"
+
+// userConfigName is the base name of the config file in the user config
+// directory
+const userConfigName = "config"
+
+// profilesKey holds named profiles in config files, e.g.
+// profiles.devnet.node.api_port
+const profilesKey = "profiles"
+
+// configExts are the supported config file formats, in lookup order
+var configExts = []string{"yaml", "yml", "json", "toml"}
+
+// Config sources, from lowest to highest precedence
+const (
+	SourceDefault = "default"
+	SourceUser    = "user"
+	SourceProject = "project"
+	SourceProfile = "profile"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// Origin records where a config value came from
+type Origin struct {
+	Source string `json:"source"`
+	// Location is the file, environment variable or flag that set the value
+	Location string `json:"location,omitempty"`
+	// Profile is the profile that set the value, for the profile source
+	Profile string `json:"profile,omitempty"`
+}
+
+// LoadOptions holds the inputs of layered config loading
+type LoadOptions struct {
+	// ConfigFile replaces the user config file
+	ConfigFile string
+	// ProjectDir is searched for the project config; empty is the project
+	// root containing the working directory
+	ProjectDir string
+	// Flags maps config keys to command line flags; only flags set on the
+	// command line override the config
+	Flags map[string]*pflag.Flag
+}
+
+// Load reads the config from the specified file, or the user config file when
+// empty, layered with the project config, environment and defaults
+func Load(configPath string) (*Config, error) {
+	return LoadWithOptions(LoadOptions{ConfigFile: configPath})
+}
+
+// LoadWithOptions reads the config in layers: built-in defaults, the user
+// config file, the project config file, the active profile, KINETIC_*
+// environment variables and command line flags, each overriding the ones
+// before. If the result fails
+// Validate, the config is still returned and set globally along with the
+// ValidationErrors.
+func LoadWithOptions(opts LoadOptions) (*Config, error) {
+	v := viper.New()
+	origins := make(map[string]Origin)
+	for key, value := range DefaultConfig().values() {
+		v.SetDefault(key, value)
+		origins[key] = Origin{Source: SourceDefault}
+	}
+
+	userFile := opts.ConfigFile
+	if userFile == "" {
+		var err error
+		if userFile, err = UserConfigFile(); err != nil {
+			return nil, err
+		}
+	}
+	projectDir := opts.ProjectDir
+	if projectDir == "" {
+		cwd, _ := os.Getwd()
+		if projectDir = FindProjectRoot(cwd); projectDir == "" {
+			projectDir = cwd
+		}
+	}
+
+	layers := []Origin{{Source: SourceUser, Location: userFile}}
+	if projectFile := findConfigFile(projectDir, ProjectConfigName); projectFile != "" {
+		layers = append(layers, Origin{Source: SourceProject, Location: projectFile})
+	}
+	// Profiles are applied after every file is read, so each file's
+	// profiles override both files
+	type profileLayer struct {
+		origin   Origin
+		profiles map[string]any
+	}
+	var profileLayers []profileLayer
+	profiles := make(map[string][]string)
+	var migrations []Migration
+	for _, layer := range layers {
+		settings, err := readConfigFile(layer.Location)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		migrated, err := migrateFile(layer.Location, settings)
+		if err != nil {
+			return nil, err
+		}
+		if migrated != nil {
+			migrations = append(migrations, *migrated)
+			if settings, err = readConfigFile(layer.Location); err != nil {
+				return nil, err
+			}
+		}
+		// Files are current after migration; the version is not a setting
+		delete(settings, versionKey)
+
+		if p, ok := settings[profilesKey]; ok {
+			delete(settings, profilesKey)
+			defined, err := readProfiles(p, layer.Location)
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range sortedKeys(defined) {
+				profiles[name] = append(profiles[name], layer.Location)
+			}
+			profileLayers = append(profileLayers, profileLayer{origin: layer, profiles: defined})
+		}
+		for _, key := range flattenKeys(settings, "") {
+			if _, ok := origins[key]; !ok {
+				return nil, fmt.Errorf("unknown config key %s in %s", key, layer.Location)
+			}
+			origins[key] = layer
+		}
+		if err := v.MergeConfigMap(settings); err != nil {
+			return nil, fmt.Errorf("failed to merge %s: %w", layer.Location, err)
+		}
+	}
+
+	for _, key := range Keys() {
+		if key == versionKey {
+			continue
+		}
+		name := EnvVar(key)
+		v.BindEnv(key, name)
+		if _, ok := os.LookupEnv(name); ok {
+			origins[key] = Origin{Source: SourceEnv, Location: name}
+		}
+	}
+	for key, flag := range opts.Flags {
+		if flag == nil || !flag.Changed {
+			continue
+		}
+		if _, ok := origins[key]; !ok {
+			return nil, fmt.Errorf("flag --%s is bound to unknown config key %s", flag.Name, key)
+		}
+		if err := v.BindPFlag(key, flag); err != nil {
+			return nil, fmt.Errorf("failed to bind flag --%s: %w", flag.Name, err)
+		}
+		origins[key] = Origin{Source: SourceFlag, Location: "--" + flag.Name}
+	}
+
+	// Profile values sit below environment variables and flags, which viper
+	// gives precedence over merged config
+	profile := strings.ToLower(v.GetString("profile"))
+	v.Set("profile", profile)
+	for _, layer := range profileLayers {
+		settings, ok := layer.profiles[profile].(map[string]any)
+		if profile == "" || !ok {
+			continue
+		}
+		for _, key := range flattenKeys(settings, "") {
+			if source := origins[key].Source; source != SourceEnv && source != SourceFlag {
+				origins[key] = Origin{Source: SourceProfile, Location: layer.origin.Location, Profile: profile}
+			}
+		}
+		if err := v.MergeConfigMap(settings); err != nil {
+			return nil, fmt.Errorf("failed to merge profile %s from %s: %w", profile, layer.origin.Location, err)
+		}
+	}
+
+	cfg := &Config{}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	// Paths may use ~ and environment variables. Relative directories are
+	// resolved against the directory of the config file that set them, or
+	// the working directory for the environment and flags, so a file means
+	// the same wherever Kinetic runs.
+	var err error
+	if cfg.Native.BinaryPath, err = system.ExpandPath(cfg.Native.BinaryPath); err != nil {
+		return nil, err
+	}
+	for _, d := range []struct {
+		key string
+		dir *string
+	}{
+		{"node.db_dir", &cfg.Node.DBDir},
+		{"node.log_dir", &cfg.Node.LogDir},
+		{"node.staking_dir", &cfg.Node.StakingDir},
+		{"node.plugin_dir", &cfg.Node.PluginDir},
+		{"node.chain_config_dir", &cfg.Node.ChainConfigDir},
+	} {
+		if *d.dir == "" {
+			continue
+		}
+		if *d.dir, err = system.ExpandPath(*d.dir); err != nil {
+			return nil, err
+		}
+		if filepath.IsAbs(*d.dir) {
+			continue
+		}
+		if origin := origins[d.key]; origin.Source != SourceEnv && origin.Source != SourceFlag {
+			*d.dir = filepath.Join(filepath.Dir(origin.Location), *d.dir)
+		}
+		abs, err := filepath.Abs(*d.dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve absolute path for %s: %w", *d.dir, err)
+		}
+		*d.dir = abs
+	}
+	if err := cfg.SetDefaultDirs(); err != nil {
+		return nil, err
+	}
+
+	cfg.path = userFile
+	cfg.origins = origins
+	cfg.profiles = profiles
+	cfg.migrations = migrations
+	cfg.loaded = cfg.values()
+	globalConfig = cfg
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// SetDefaultDirs fills in unset node directories with db, logs, staking,
+// plugins and configs/chains under a node directory named after the
+// container, so profiles running their own node keep separate state, VMs and
+// chain configs. Load calls it; configs built otherwise call it before use.
+// Nothing is created; the node creates its directories when it starts.
+func (c *Config) SetDefaultDirs() error {
+	dirs := []struct {
+		dir  *string
+		name string
+	}{
+		{&c.Node.DBDir, "db"},
+		{&c.Node.LogDir, "logs"},
+		{&c.Node.StakingDir, "staking"},
+		{&c.Node.PluginDir, "plugins"},
+		{&c.Node.ChainConfigDir, filepath.Join("configs", "chains")},
+	}
+	unset := false
+	for _, d := range dirs {
+		unset = unset || *d.dir == ""
+	}
+	if !unset {
+		return nil
+	}
+	nodeDir, err := system.NodeDataDirPath()
+	if err != nil {
+		return fmt.Errorf("failed to get node data directory: %w", err)
+	}
+	instanceDir := filepath.Join(nodeDir, c.Docker.ContainerName)
+	for _, d := range dirs {
+		if *d.dir == "" {
+			*d.dir = filepath.Join(instanceDir, d.name)
+		}
+	}
+	return nil
+}
+
+// FindProjectRoot returns the project root containing dir: the nearest of
+// dir and its parents holding a kinetic.yaml, kinetic.yml, kinetic.json or
+// kinetic.toml, or an empty string
+func FindProjectRoot(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if findConfigFile(dir, ProjectConfigName) != "" {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// readProfiles checks the profiles section of a config file, returning the
+// settings of each profile by name
+func readProfiles(section any, path string) (map[string]any, error) {
+	defined, ok := section.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s in %s must map profile names to settings", profilesKey, path)
+	}
+	for name, settings := range defined {
+		if settings == nil {
+			// An empty profile written as "devnet:" has no settings
+			defined[name] = map[string]any{}
+			continue
+		}
+		nested, ok := settings.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("profile %s in %s must be a map of settings", name, path)
+		}
+		for _, key := range flattenKeys(nested, "") {
+			if _, ok := fieldByKey(key); !ok || key == "profile" || key == versionKey {
+				return nil, fmt.Errorf("unknown config key %s.%s.%s in %s", profilesKey, name, key, path)
+			}
+		}
+	}
+	return defined, nil
+}
+
+// UserConfigFile returns the config file in the user config directory: the
+// first existing config.yaml, config.yml, config.json or config.toml, or
+// config.yaml if none exists yet
+func UserConfigFile() (string, error) {
+	dir, err := system.GetUserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	if path := findConfigFile(dir, userConfigName); path != "" {
+		return path, nil
+	}
+	return filepath.Join(dir, userConfigName+".yaml"), nil
+}
+
+// findConfigFile returns the first existing config file with the given base
+// name in dir, or an empty string
+func findConfigFile(dir, name string) string {
+	for _, ext := range configExts {
+		path := filepath.Join(dir, name+"."+ext)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// readConfigFile reads a YAML, JSON or TOML config file into nested settings
+func readConfigFile(path string) (map[string]any, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	settings := v.AllSettings()
+	// AllSettings drops empty maps, which are valid empty profiles
+	if profiles := v.Get(profilesKey); profiles != nil {
+		settings[profilesKey] = profiles
+	}
+	return settings, nil
+}
+
+// flattenKeys returns the dotted leaf keys of nested settings
+func flattenKeys(settings map[string]any, prefix string) []string {
+	var keys []string
+	for k, val := range settings {
+		key := prefix + k
+		if nested, ok := val.(map[string]any); ok {
+			keys = append(keys, flattenKeys(nested, key+".")...)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// EnvVar returns the environment variable overriding a config key
+func EnvVar(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Keys returns the dotted keys of all config values, e.g. node.api_port
+func Keys() []string {
+	var keys []string
+	walkFields(reflect.ValueOf(DefaultConfig()).Elem(), "", func(key string, _ reflect.Value) {
+		keys = append(keys, key)
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+// values returns the config's values by dotted key
+func (c *Config) values() map[string]any {
+	values := make(map[string]any)
+	walkFields(reflect.ValueOf(c).Elem(), "", func(key string, v reflect.Value) {
+		values[key] = v.Interface()
+	})
+	return values
+}
+
+// Value returns the value of a dotted config key
+func (c *Config) Value(key string) (any, bool) {
+	v, ok := c.values()[key]
+	return v, ok
+}
+
+// Origin returns where a config value came from
+func (c *Config) Origin(key string) Origin {
+	if origin, ok := c.origins[key]; ok {
+		return origin
+	}
+	return Origin{Source: SourceDefault}
+}
+
+// Profile is a named set of config values
+type Profile struct {
+	Name string `json:"name"`
+	// Files are the config files defining the profile
+	Files []string `json:"files"`
+}
+
+// Profiles returns the profiles defined in the loaded config files, by name
+func (c *Config) Profiles() []Profile {
+	profiles := make([]Profile, 0, len(c.profiles))
+	for name, files := range c.profiles {
+		profiles = append(profiles, Profile{Name: name, Files: files})
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles
+}
+
+// Migrations returns the config files upgraded to the current version when
+// the config was loaded
+func (c *Config) Migrations() []Migration {
+	return c.migrations
+}
+
+// Path returns the config file the config is saved to
+func (c *Config) Path() (string, error) {
+	if c.path != "" {
+		return c.path, nil
+	}
+	return UserConfigFile()
+}
+
+// walkFields calls fn for every leaf field of a config struct, keyed by its
+// dotted mapstructure tags
+func walkFields(v reflect.Value, prefix string, fn func(key string, v reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("mapstructure")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			walkFields(field, prefix+tag+".", fn)
+			continue
+		}
+		fn(prefix+tag, field)
+	}
+}
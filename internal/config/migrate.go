@@ -0,0 +1,172 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// CurrentVersion is the config file format written by this version of
+// Kinetic. Files without a version key are version 0.
+const CurrentVersion = 1
+
+// versionKey holds the format version of a config file
+const versionKey = "version"
+
+// migration upgrades config file values, by dotted key, from one version to
+// the next
+type migration struct {
+	description string
+	migrate     func(values map[string]any)
+}
+
+// migrations upgrade version i to version i+1
+var migrations = []migration{
+	{
+		description: "rename Go-style keys such as node.APIPort to node.api_port, and drop the empty docker settings and shared \"data\" node directories older versions saved, so their defaults apply",
+		migrate: func(values map[string]any) {
+			renameKeys(map[string]string{
+				"node.apiport":         "node.api_port",
+				"node.networkid":       "node.network_id",
+				"node.dbdir":           "node.db_dir",
+				"node.logdir":          "node.log_dir",
+				"node.stakingdir":      "node.staking_dir",
+				"docker.imagetag":      "docker.image_tag",
+				"docker.containername": "docker.container_name",
+			})(values)
+			// Config.Save wrote every field, including the empty image and
+			// container name of an unloaded config and its node directories,
+			// all "data" relative to the working directory
+			dropValues(map[string][]any{
+				"docker.image_tag":      {""},
+				"docker.container_name": {""},
+				"node.db_dir":           {"", "data"},
+				"node.log_dir":          {"", "data"},
+				"node.staking_dir":      {"", "data"},
+			})(values)
+		},
+	},
+}
+
+// renameKeys returns a migration renaming keys, including within profiles
+func renameKeys(renames map[string]string) func(values map[string]any) {
+	return func(values map[string]any) {
+		for key, value := range values {
+			prefix, rest := "", key
+			if name, k, ok := profileKey(key); ok {
+				prefix, rest = profilesKey+"."+name+".", k
+			}
+			if renamed, ok := renames[rest]; ok {
+				delete(values, key)
+				values[prefix+renamed] = value
+			}
+		}
+	}
+}
+
+// dropValues returns a migration removing keys, including within profiles,
+// that hold one of the given stale values
+func dropValues(stale map[string][]any) func(values map[string]any) {
+	return func(values map[string]any) {
+		for key, value := range values {
+			rest := key
+			if _, k, ok := profileKey(key); ok {
+				rest = k
+			}
+			for _, v := range stale[rest] {
+				if value == v {
+					delete(values, key)
+					break
+				}
+			}
+		}
+	}
+}
+
+// Migration records the upgrade of a config file to the current version
+type Migration struct {
+	Path string `json:"path"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+	// Backup is a copy of the file before the upgrade
+	Backup string `json:"backup"`
+}
+
+// fileVersion returns the format version of config file settings
+func fileVersion(settings map[string]any, path string) (int, error) {
+	raw, ok := settings[versionKey]
+	if !ok {
+		return 0, nil
+	}
+	var version int
+	switch v := raw.(type) {
+	case int:
+		version = v
+	case int64:
+		version = int(v)
+	case float64:
+		version = int(v)
+	default:
+		return 0, fmt.Errorf("invalid version %v in %s", raw, path)
+	}
+	if version > CurrentVersion {
+		return 0, fmt.Errorf("%s is config version %d, newer than the version %d this Kinetic supports; upgrade Kinetic", path, version, CurrentVersion)
+	}
+	return version, nil
+}
+
+// migrateFile upgrades a config file to the current version in place, after
+// copying it to a backup. It returns nil if the file is already current.
+func migrateFile(path string, settings map[string]any) (*Migration, error) {
+	version, err := fileVersion(settings, path)
+	if err != nil || version == CurrentVersion {
+		return nil, err
+	}
+
+	before := make(map[string]any)
+	flattenValues(settings, "", before)
+	after := make(map[string]any, len(before))
+	for k, v := range before {
+		after[k] = v
+	}
+	for _, m := range migrations[version:] {
+		m.migrate(after)
+	}
+	after[versionKey] = CurrentVersion
+
+	backup := fmt.Sprintf("%s.v%d.bak", path, version)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := os.WriteFile(backup, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to back up %s before upgrading it: %w", path, err)
+	}
+
+	set := make(map[string]any)
+	var unset []string
+	for key, value := range after {
+		if old, ok := before[key]; !ok || !reflect.DeepEqual(old, value) {
+			set[key] = value
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			unset = append(unset, key)
+		}
+	}
+	if err := editFile(path, set, unset); err != nil {
+		return nil, fmt.Errorf("failed to upgrade %s from config version %d: %w", path, version, err)
+	}
+	return &Migration{Path: path, From: version, To: CurrentVersion, Backup: backup}, nil
+}
+
+// Description returns what the upgrade changed
+func (m Migration) Description() string {
+	var changes []string
+	for _, mig := range migrations[m.From:m.To] {
+		changes = append(changes, mig.description)
+	}
+	return strings.Join(changes, "; ")
+}
@@ -0,0 +1,273 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// reservedNetworkIDs are the public and historic Avalanche networks, which a
+// local node must not join
+var reservedNetworkIDs = map[int]string{
+	1: "mainnet",
+	2: "cascade",
+	3: "denali",
+	4: "everest",
+	5: "fuji",
+}
+
+// Runtimes are the supported node runtimes
+var Runtimes = []string{"docker", "podman", "native"}
+
+// networks are the networks deploy commands can target
+var networks = []string{"local", "fuji", "mainnet"}
+
+// ValidationError is a problem with a single config value
+type ValidationError struct {
+	Key     string
+	Origin  Origin
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	msg := e.Key + ": " + e.Message
+	switch e.Origin.Source {
+	case SourceDefault:
+	case SourceUser, SourceProject:
+		msg += fmt.Sprintf(" (set in %s)", e.Origin.Location)
+	case SourceProfile:
+		msg += fmt.Sprintf(" (set in profile %s in %s)", e.Origin.Profile, e.Origin.Location)
+	default:
+		msg += fmt.Sprintf(" (set by %s)", e.Origin.Location)
+	}
+	return msg
+}
+
+// ValidationErrors lists every problem found in a config
+type ValidationErrors []ValidationError
+
+func (errs ValidationErrors) Error() string {
+	lines := make([]string, len(errs))
+	for i, err := range errs {
+		lines[i] = "  " + err.Error()
+	}
+	problems := "problems"
+	if len(errs) == 1 {
+		problems = "problem"
+	}
+	return fmt.Sprintf("invalid config (%d %s; fix with 'kinetic config set' or 'kinetic config edit'):\n%s",
+		len(errs), problems, strings.Join(lines, "\n"))
+}
+
+// validator collects validation errors for a config
+type validator struct {
+	cfg  *Config
+	errs ValidationErrors
+}
+
+func (v *validator) add(key, format string, args ...any) {
+	v.errs = append(v.errs, ValidationError{Key: key, Origin: v.cfg.Origin(key), Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// Validate checks the active profile, port ranges and conflicts, the network
+// ID, the runtime, the target network and default key, the node image and
+// container name, and that node directories are absolute and distinct,
+// returning ValidationErrors listing every problem. It does not touch the host; see CheckPorts and CheckDirs.
+func (c *Config) Validate() error {
+	v := &validator{cfg: c}
+
+	if c.Profile != "" && len(c.profiles[c.Profile]) == 0 {
+		var names []string
+		for _, p := range c.Profiles() {
+			names = append(names, p.Name)
+		}
+		available := "none are defined"
+		if len(names) > 0 {
+			available = "available: " + strings.Join(names, ", ")
+		}
+		v.add("profile", "profile %s is not defined (%s)", c.Profile, available)
+	}
+
+	ports := []struct {
+		key  string
+		port int
+	}{
+		{"node.port", c.Node.Port},
+		{"node.api_port", c.Node.APIPort},
+	}
+	for _, p := range ports {
+		if p.port < 1 || p.port > 65535 {
+			v.add(p.key, "port %d is out of range (1-65535)", p.port)
+		}
+	}
+	if c.Node.Port == c.Node.APIPort {
+		v.add("node.api_port", "port %d is also used by node.port; the staking and API ports must differ", c.Node.APIPort)
+	}
+
+	switch id := c.Node.NetworkID; {
+	case id < 1 || id > 1<<32-1:
+		v.add("node.network_id", "network ID %d is out of range (1-4294967295)", id)
+	case reservedNetworkIDs[id] != "":
+		v.add("node.network_id", "network ID %d is %s; use a local network ID such as 12345", id, reservedNetworkIDs[id])
+	}
+
+	if !contains(Runtimes, c.Node.Runtime) {
+		v.add("node.runtime", "unsupported runtime %q (supported: %s)", c.Node.Runtime, strings.Join(Runtimes, ", "))
+	}
+
+	if c.Network.Name != "" && !contains(networks, c.Network.Name) {
+		v.add("network.name", "unsupported network %q (supported: %s)", c.Network.Name, strings.Join(networks, ", "))
+	}
+	if key := strings.TrimPrefix(c.Network.DefaultKey, "0x"); key != "" {
+		// The key itself is never echoed
+		if b, err := hex.DecodeString(key); err != nil || len(b) != 32 {
+			v.add("network.default_key", "not a 32-byte hex private key")
+		}
+	}
+
+	if c.Compiler.OptimizerRuns < 0 {
+		v.add("compiler.optimizer_runs", "optimizer runs must not be negative")
+	}
+
+	if c.Docker.ImageTag == "" {
+		v.add("docker.image_tag", "the node image must not be empty; unset it to use %s", DefaultConfig().Docker.ImageTag)
+	}
+	if c.Docker.ContainerName == "" {
+		v.add("docker.container_name", "the container name must not be empty; unset it to use %s", DefaultConfig().Docker.ContainerName)
+	}
+
+	for _, d := range c.nodeDirs() {
+		if d.dir != "" && !filepath.IsAbs(d.dir) {
+			v.add(d.key, "directory %s is not an absolute path", d.dir)
+		}
+	}
+	// The node keeps its database, logs and staking keys apart
+	seen := make(map[string]string)
+	for _, d := range c.nodeDirs()[:3] {
+		if d.dir == "" {
+			continue
+		}
+		dir := filepath.Clean(d.dir)
+		if other, ok := seen[dir]; ok {
+			v.add(d.key, "directory %s is also %s; the database, log and staking directories must differ", d.dir, other)
+			continue
+		}
+		seen[dir] = d.key
+	}
+
+	return v.err()
+}
+
+// nodeDir is a node directory setting
+type nodeDir struct {
+	key string
+	dir string
+}
+
+// nodeDirs returns the node directory settings
+func (c *Config) nodeDirs() []nodeDir {
+	return []nodeDir{
+		{"node.db_dir", c.Node.DBDir},
+		{"node.log_dir", c.Node.LogDir},
+		{"node.staking_dir", c.Node.StakingDir},
+		{"node.plugin_dir", c.Node.PluginDir},
+		{"node.chain_config_dir", c.Node.ChainConfigDir},
+	}
+}
+
+// CheckDirs checks that the node directories are, or can be created as,
+// writable directories, returning ValidationErrors for those that are not.
+// It writes a probe file in each, so only commands that are about to write
+// them run it.
+func (c *Config) CheckDirs() error {
+	v := &validator{cfg: c}
+	for _, d := range c.nodeDirs() {
+		// Validate reports relative paths
+		if d.dir == "" || !filepath.IsAbs(d.dir) {
+			continue
+		}
+		if err := checkWritable(d.dir); err != nil {
+			v.add(d.key, "%v", err)
+		}
+	}
+	return v.err()
+}
+
+// checkWritable checks that dir is, or can be created as, a writable
+// directory
+func checkWritable(dir string) error {
+	// Walk up to the closest existing ancestor, which must be a directory
+	existing := dir
+	for {
+		info, err := os.Stat(existing)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", existing)
+			}
+			break
+		}
+		if !errors.Is(err, os.ErrNotExist) && !errors.Is(err, syscall.ENOTDIR) {
+			return fmt.Errorf("cannot access %s: %w", existing, err)
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return fmt.Errorf("cannot access %s: %w", dir, err)
+		}
+		existing = parent
+	}
+
+	f, err := os.CreateTemp(existing, ".kinetic-write-check-*")
+	if err != nil {
+		if existing == dir {
+			return fmt.Errorf("directory %s is not writable", dir)
+		}
+		return fmt.Errorf("cannot create %s: %s is not writable", dir, existing)
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return nil
+}
+
+// CheckPorts checks that the node's staking and API ports are free on the
+// host, returning ValidationErrors for ports already in use. It only makes
+// sense while the node is stopped.
+func (c *Config) CheckPorts() error {
+	v := &validator{cfg: c}
+	for _, p := range []struct {
+		key  string
+		port int
+	}{
+		{"node.port", c.Node.Port},
+		{"node.api_port", c.Node.APIPort},
+	} {
+		l, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(p.port)))
+		if err != nil {
+			v.add(p.key, "port %d is already in use on this host", p.port)
+			continue
+		}
+		l.Close()
+	}
+	return v.err()
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
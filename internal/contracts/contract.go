@@ -3,12 +3,28 @@ package contracts
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
+	"unicode"
+
+	"github.com/kinetic-dev/kinetic/templates"
 )
 
+// templateFS holds the contract templates and config.json, replaced in tests
+var templateFS fs.FS = mustSub(templates.Contracts, "contracts")
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
 // TemplateConfig represents the structure of the template configuration file
 type TemplateConfig struct {
 	Templates map[string]struct {
@@ -25,44 +41,54 @@ type TemplateConfig struct {
 
 // CreateOptions holds the options for contract creation
 type CreateOptions struct {
-	TemplateName  string
-	ContractName  string
-	OutputDir     string
+	TemplateName string
+	ContractName string
+	OutputDir    string
+	// BaseDir is the directory a relative or empty OutputDir resolves
+	// against; empty is the working directory
+	BaseDir       string
 	TemplateFlags map[string]interface{}
 }
 
-// Create generates a new contract from a template
-func Create(opts CreateOptions) error {
-	// Get the user's current working directory
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current working directory: %w", err)
+// OutputPath returns the absolute path of the contract file Create writes
+func OutputPath(opts CreateOptions) (string, error) {
+	baseDir := opts.BaseDir
+	if baseDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current working directory: %w", err)
+		}
+		baseDir = cwd
 	}
 
 	// Handle output directory
 	outputDir := opts.OutputDir
 	if outputDir == "" {
-		outputDir = cwd
+		outputDir = baseDir
 	} else if !filepath.IsAbs(outputDir) {
-		outputDir = filepath.Join(cwd, outputDir)
+		outputDir = filepath.Join(baseDir, outputDir)
 	}
 
-	// Read template configuration
-	configPath := filepath.Join("templates", "contracts", "config.json")
-	configData, err := os.ReadFile(configPath)
+	return filepath.Join(outputDir, fmt.Sprintf("%s.sol", opts.ContractName)), nil
+}
+
+// Create generates a new contract from a template
+func Create(opts CreateOptions) error {
+	outputPath, err := OutputPath(opts)
 	if err != nil {
-		return fmt.Errorf("failed to read template config: %w", err)
+		return err
 	}
+	outputDir := filepath.Dir(outputPath)
 
-	var config TemplateConfig
-	if err := json.Unmarshal(configData, &config); err != nil {
-		return fmt.Errorf("failed to parse template config: %w", err)
+	config, err := LoadTemplateConfig()
+	if err != nil {
+		return err
 	}
 
 	// Validate template name
 	templateConfig, ok := config.Templates[opts.TemplateName]
 	if !ok {
-		return fmt.Errorf("invalid template name. Available templates: %s", strings.Join(getTemplateNames(config), ", "))
+		return fmt.Errorf("invalid template name. Available templates: %s", strings.Join(config.TemplateNames(), ", "))
 	}
 
 	// Create template data with defaults
@@ -83,8 +109,7 @@ func Create(opts CreateOptions) error {
 	}
 
 	// Read template file
-	templatePath := filepath.Join("templates", "contracts", fmt.Sprintf("%s.sol.tmpl", opts.TemplateName))
-	tmplContent, err := os.ReadFile(templatePath)
+	tmplContent, err := fs.ReadFile(templateFS, opts.TemplateName+".sol.tmpl")
 	if err != nil {
 		return fmt.Errorf("failed to read template file: %w", err)
 	}
@@ -95,7 +120,6 @@ func Create(opts CreateOptions) error {
 	}
 
 	// Create output file in the specified directory
-	outputPath := filepath.Join(outputDir, fmt.Sprintf("%s.sol", opts.ContractName))
 	outputFile, err := os.Create(outputPath)
 	if err != nil {
 		return fmt.Errorf("failed to create output file: %w", err)
@@ -115,11 +139,46 @@ func Create(opts CreateOptions) error {
 	return nil
 }
 
-// GetTemplateNames returns a list of available template names
-func getTemplateNames(config TemplateConfig) []string {
-	names := make([]string, 0, len(config.Templates))
-	for name := range config.Templates {
+// LoadTemplateConfig reads the template configuration file
+func LoadTemplateConfig() (*TemplateConfig, error) {
+	configData, err := fs.ReadFile(templateFS, "config.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template config: %w", err)
+	}
+
+	var config TemplateConfig
+	if err := json.Unmarshal(configData, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse template config: %w", err)
+	}
+	return &config, nil
+}
+
+// TemplateNames returns the available template names, sorted
+func (c *TemplateConfig) TemplateNames() []string {
+	names := make([]string, 0, len(c.Templates))
+	for name := range c.Templates {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 	return names
 }
+
+// OptionFlag returns the command line flag of a template option, e.g.
+// has-base-uri for HasBaseURI
+func OptionFlag(option string) string {
+	runes := []rune(option)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			// A word starts at an upper case letter after a lower case one,
+			// or at the last letter of an acronym followed by lower case
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || unicode.IsUpper(runes[i-1]) && nextLower {
+				b.WriteByte('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
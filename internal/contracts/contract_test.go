@@ -72,15 +72,10 @@ contract {{.ContractName}} {
 		t.Fatalf("Failed to create output dir: %v", err)
 	}
 
-	// Set up test environment
-	if err := os.Chdir(tmpDir); err != nil {
-		t.Fatalf("Failed to change working directory: %v", err)
-	}
-	defer func() {
-		if err := os.Chdir(filepath.Dir(tmpDir)); err != nil {
-			t.Errorf("Failed to restore working directory: %v", err)
-		}
-	}()
+	// Use the test templates instead of the embedded ones
+	embedded := templateFS
+	templateFS = os.DirFS(templatesDir)
+	defer func() { templateFS = embedded }()
 
 	// Create contract with options
 	opts := CreateOptions{
@@ -121,3 +116,74 @@ contract MyContract {
 		t.Errorf("Output content does not match expected.\nGot:\n%s\nWant:\n%s", content, expectedContent)
 	}
 }
+
+func TestOutputPath(t *testing.T) {
+	cwd, _ := os.Getwd()
+	base := t.TempDir()
+
+	tests := []struct {
+		name string
+		opts CreateOptions
+		want string
+	}{
+		{name: "working directory", opts: CreateOptions{ContractName: "Token"}, want: filepath.Join(cwd, "Token.sol")},
+		{name: "relative to working directory", opts: CreateOptions{ContractName: "Token", OutputDir: "out"}, want: filepath.Join(cwd, "out", "Token.sol")},
+		{name: "base directory", opts: CreateOptions{ContractName: "Token", BaseDir: base}, want: filepath.Join(base, "Token.sol")},
+		{name: "relative to base directory", opts: CreateOptions{ContractName: "Token", OutputDir: "contracts", BaseDir: base}, want: filepath.Join(base, "contracts", "Token.sol")},
+		{name: "absolute", opts: CreateOptions{ContractName: "Token", OutputDir: "/abs", BaseDir: base}, want: filepath.Join("/abs", "Token.sol")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OutputPath(tt.opts)
+			if err != nil {
+				t.Fatalf("OutputPath() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("OutputPath() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptionFlag(t *testing.T) {
+	tests := map[string]string{
+		"HasCap":           "has-cap",
+		"HasBaseURI":       "has-base-uri",
+		"IsMintable":       "is-mintable",
+		"OnlyOwnerCanMint": "only-owner-can-mint",
+		"HasURIStorage":    "has-uri-storage",
+	}
+	for option, want := range tests {
+		if got := OptionFlag(option); got != want {
+			t.Errorf("OptionFlag(%q) = %q, want %q", option, got, want)
+		}
+	}
+}
+
+func TestEmbeddedTemplates(t *testing.T) {
+	config, err := LoadTemplateConfig()
+	if err != nil {
+		t.Fatalf("LoadTemplateConfig() error = %v", err)
+	}
+	if len(config.Templates) == 0 {
+		t.Fatal("no templates embedded")
+	}
+
+	// Every template renders from any working directory
+	outputDir := t.TempDir()
+	for _, name := range config.TemplateNames() {
+		t.Run(name, func(t *testing.T) {
+			opts := CreateOptions{TemplateName: name, ContractName: "My" + name, OutputDir: outputDir}
+			if err := Create(opts); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			content, err := os.ReadFile(filepath.Join(outputDir, "My"+name+".sol"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(content), "contract My"+name+" ") {
+				t.Errorf("unexpected contract:\n%s", content)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/node"
+	"github.com/kinetic-dev/kinetic/internal/system"
+)
+
+// Host requirements, as listed in the README
+const (
+	MinDiskFree       = 20 << 30
+	MinMemory         = 4 << 30
+	RecommendedMemory = 8 << 30
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Result is the outcome of a single check
+type Result struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	// Hint suggests how to fix a warning or failure
+	Hint string `json:"hint,omitempty"`
+}
+
+func pass(name, format string, args ...any) Result {
+	return Result{Name: name, Status: StatusPass, Message: fmt.Sprintf(format, args...)}
+}
+
+func warn(name, hint, format string, args ...any) Result {
+	return Result{Name: name, Status: StatusWarn, Message: fmt.Sprintf(format, args...), Hint: hint}
+}
+
+func fail(name, hint, format string, args ...any) Result {
+	return Result{Name: name, Status: StatusFail, Message: fmt.Sprintf(format, args...), Hint: hint}
+}
+
+// Doctor checks that the host can run Kinetic and its local node
+type Doctor struct {
+	cfg *config.Config
+
+	// Host access, replaced in tests
+	newRuntime  func(name string) (system.ContainerRuntime, error)
+	diskFree    func(path string) (uint64, error)
+	totalMemory func() (uint64, error)
+	lookPath    func(file string) (string, error)
+	solcVersion func(ctx context.Context, path string) (string, error)
+	nodeRunning func(ctx context.Context) bool
+}
+
+// New creates a doctor for the given config
+func New(cfg *config.Config) *Doctor {
+	return &Doctor{
+		cfg:         cfg,
+		newRuntime:  system.NewContainerRuntime,
+		diskFree:    system.DiskFree,
+		totalMemory: system.TotalMemory,
+		lookPath:    exec.LookPath,
+		solcVersion: solcVersion,
+		nodeRunning: func(ctx context.Context) bool {
+			ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+			defer cancel()
+			_, err := node.NewAPIClient(cfg.Node.APIPort).NodeVersion(ctx)
+			return err == nil
+		},
+	}
+}
+
+// Run performs every check, returning their results in order
+func (d *Doctor) Run(ctx context.Context) []Result {
+	var results []Result
+	results = append(results, d.checkConfig())
+	results = append(results, d.checkRuntime(ctx)...)
+	results = append(results, d.checkPorts(ctx)...)
+	results = append(results, d.checkDisk(), d.checkMemory(), d.checkDirs(), d.checkSolc(ctx))
+	return results
+}
+
+// checkConfig validates the config
+func (d *Doctor) checkConfig() Result {
+	var problems []string
+	var invalid config.ValidationErrors
+	if err := d.cfg.Validate(); errors.As(err, &invalid) {
+		for _, e := range invalid {
+			problems = append(problems, e.Error())
+		}
+	} else if err != nil {
+		problems = append(problems, err.Error())
+	}
+	if len(problems) > 0 {
+		return fail("config", "fix with 'kinetic config set' or 'kinetic config edit'", "%s", strings.Join(problems, "; "))
+	}
+	path, _ := d.cfg.Path()
+	return pass("config", "valid (%s)", path)
+}
+
+// runtimeHints tell how to make each container daemon reachable
+var runtimeHints = map[string]string{
+	"docker": "start Docker (Docker Desktop, or 'sudo systemctl start docker' on Linux) and check DOCKER_HOST",
+	"podman": "start the Podman API socket with 'systemctl --user start podman.socket' or 'podman system service', or set CONTAINER_HOST",
+}
+
+// checkRuntime checks that the node runtime is usable: the container daemon
+// is reachable and has the node image, or the native binary is present
+func (d *Doctor) checkRuntime(ctx context.Context) []Result {
+	name := d.cfg.Node.Runtime
+	if name == "native" {
+		return []Result{d.checkNativeBinary()}
+	}
+
+	runtime, err := d.newRuntime(name)
+	if err != nil {
+		return []Result{fail(name, runtimeHints[name], "%v", err)}
+	}
+	defer runtime.Close()
+
+	version, err := runtime.ServerVersion(ctx)
+	if err != nil {
+		return []Result{fail(name, runtimeHints[name], "%v", err)}
+	}
+	results := []Result{pass(name, "daemon reachable, version %s", version)}
+
+	image := d.cfg.Docker.ImageTag
+	switch exists, err := runtime.ImageExists(ctx, image); {
+	case err != nil:
+		results = append(results, warn("image", "", "could not check for %s: %v", image, err))
+	case !exists:
+		results = append(results, warn("image", fmt.Sprintf("pull it now with '%s pull %s'", name, image),
+			"%s is not present; 'kinetic node start' pulls it", image))
+	default:
+		results = append(results, pass("image", "%s is present", image))
+	}
+	return results
+}
+
+// checkNativeBinary checks the avalanchego binary of the native runtime
+func (d *Doctor) checkNativeBinary() Result {
+	if path := d.cfg.Native.BinaryPath; path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return fail("avalanchego", "set native.binary_path to an avalanchego binary, or unset it to download a release",
+				"binary %s not found", path)
+		}
+		return pass("avalanchego", "using %s", path)
+	}
+
+	version := d.cfg.Native.Version
+	if version == "" {
+		version = node.DefaultAvalanchegoVersion
+	}
+	binary, err := node.DownloadedBinary(version)
+	if err != nil {
+		return warn("avalanchego", "", "%v", err)
+	}
+	if _, err := os.Stat(binary); err != nil {
+		return warn("avalanchego", "set native.binary_path to use a local build instead",
+			"avalanchego %s is not downloaded yet; 'kinetic node start' downloads it (linux only)", version)
+	}
+	return pass("avalanchego", "%s at %s", version, binary)
+}
+
+// checkPorts checks that the node ports are free, unless the node is
+// running and holds them itself
+func (d *Doctor) checkPorts(ctx context.Context) []Result {
+	ports := []struct {
+		key  string
+		port int
+	}{
+		{"node.port", d.cfg.Node.Port},
+		{"node.api_port", d.cfg.Node.APIPort},
+	}
+	if d.nodeRunning(ctx) {
+		var results []Result
+		for _, p := range ports {
+			results = append(results, pass(p.key, "port %d is used by the running node", p.port))
+		}
+		return results
+	}
+
+	inUse := make(map[string]bool)
+	var invalid config.ValidationErrors
+	if err := d.cfg.CheckPorts(); errors.As(err, &invalid) {
+		for _, e := range invalid {
+			inUse[e.Key] = true
+		}
+	}
+	var results []Result
+	for _, p := range ports {
+		if inUse[p.key] {
+			results = append(results, fail(p.key,
+				fmt.Sprintf("stop the process using it, or choose another port with 'kinetic config set %s <port>'", p.key),
+				"port %d is already in use", p.port))
+			continue
+		}
+		results = append(results, pass(p.key, "port %d is free", p.port))
+	}
+	return results
+}
+
+// checkDisk checks the free space where the node database is stored
+func (d *Doctor) checkDisk() Result {
+	path := existingAncestor(d.cfg.Node.DBDir)
+	free, err := d.diskFree(path)
+	if err != nil {
+		return warn("disk", "", "could not determine free space: %v", err)
+	}
+	if free < MinDiskFree {
+		return fail("disk", "free up space, or move node.db_dir to a larger disk with 'kinetic config set node.db_dir <dir>'",
+			"%s free at %s; %s required", formatBytes(free), path, formatBytes(MinDiskFree))
+	}
+	return pass("disk", "%s free at %s", formatBytes(free), path)
+}
+
+// checkMemory checks the host's physical memory
+func (d *Doctor) checkMemory() Result {
+	total, err := d.totalMemory()
+	if err != nil {
+		return warn("memory", "", "could not determine memory size: %v", err)
+	}
+	switch {
+	case total < MinMemory:
+		return fail("memory", "run the node on a host with more memory",
+			"%s of memory; %s required", formatBytes(total), formatBytes(MinMemory))
+	case total < RecommendedMemory:
+		return warn("memory", "close other applications while the node runs",
+			"%s of memory; %s recommended", formatBytes(total), formatBytes(RecommendedMemory))
+	}
+	return pass("memory", "%s of memory", formatBytes(total))
+}
+
+// checkDirs checks that the data directory and node directories are
+// writable
+func (d *Doctor) checkDirs() Result {
+	var problems []string
+	if _, err := system.GetDataDir(); err != nil {
+		problems = append(problems, fmt.Sprintf("data directory: %v", err))
+	}
+	var invalid config.ValidationErrors
+	if err := d.cfg.CheckDirs(); errors.As(err, &invalid) {
+		for _, e := range invalid {
+			problems = append(problems, e.Key+": "+e.Message)
+		}
+	}
+	if len(problems) > 0 {
+		return fail("directories", "fix the permissions, or choose another directory with 'kinetic config set <key> <dir>'",
+			"%s", strings.Join(problems, "; "))
+	}
+	return pass("directories", "data and node directories are writable")
+}
+
+// checkSolc checks that solc is installed in the configured version
+func (d *Doctor) checkSolc(ctx context.Context) Result {
+	want := d.cfg.Compiler.Version
+	install := fmt.Sprintf("install it, e.g. with 'pip install solc-select && solc-select install %s && solc-select use %s'", want, want)
+	path, err := d.lookPath("solc")
+	if err != nil {
+		return warn("solc", install, "solc not found; it is needed to compile contracts")
+	}
+	version, err := d.solcVersion(ctx, path)
+	if err != nil {
+		return warn("solc", install, "%v", err)
+	}
+	if version != want {
+		return warn("solc", fmt.Sprintf("use solc %s, or set 'kinetic config set compiler.version %s'", want, version),
+			"solc %s found, but compiler.version is %s", version, want)
+	}
+	return pass("solc", "solc %s at %s", version, path)
+}
+
+// solcVersionPattern matches the release in solc --version output, e.g.
+// Version: 0.8.20+commit.a1b79de6.Linux.g++
+var solcVersionPattern = regexp.MustCompile(`Version: (\d+\.\d+\.\d+)`)
+
+// solcVersion returns the release of a solc binary
+func solcVersion(ctx context.Context, path string) (string, error) {
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s --version: %w", path, err)
+	}
+	m := solcVersionPattern.FindSubmatch(out)
+	if m == nil {
+		return "", fmt.Errorf("unrecognized solc --version output")
+	}
+	return string(m[1]), nil
+}
+
+// existingAncestor returns the closest of path and its parents that exists
+func existingAncestor(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// formatBytes formats a size in GB
+func formatBytes(n uint64) string {
+	return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+}
@@ -0,0 +1,207 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/system"
+)
+
+// freePort returns a port that nothing listens on
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// testDoctor returns a doctor for a valid config on a healthy host
+func testDoctor(t *testing.T, runtime *system.FakeRuntime) (*Doctor, *config.Config) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(dir, "share"))
+
+	cfg := config.DefaultConfig()
+	cfg.Node.Port = freePort(t)
+	cfg.Node.APIPort = freePort(t)
+	cfg.Node.DBDir = filepath.Join(dir, "db")
+	cfg.Node.LogDir = filepath.Join(dir, "logs")
+	cfg.Node.StakingDir = filepath.Join(dir, "staking")
+
+	d := New(cfg)
+	d.newRuntime = func(name string) (system.ContainerRuntime, error) { return runtime, nil }
+	d.diskFree = func(path string) (uint64, error) { return 100 << 30, nil }
+	d.totalMemory = func() (uint64, error) { return 16 << 30, nil }
+	d.lookPath = func(file string) (string, error) { return "/usr/bin/solc", nil }
+	d.solcVersion = func(ctx context.Context, path string) (string, error) { return cfg.Compiler.Version, nil }
+	d.nodeRunning = func(ctx context.Context) bool { return false }
+	return d, cfg
+}
+
+// statuses maps check names to their status
+func statuses(results []Result) map[string]Status {
+	m := make(map[string]Status)
+	for _, r := range results {
+		m[r.Name] = r.Status
+	}
+	return m
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, d *Doctor, cfg *config.Config, runtime *system.FakeRuntime)
+		want  map[string]Status
+	}{
+		{
+			name: "healthy",
+			setup: func(t *testing.T, d *Doctor, cfg *config.Config, runtime *system.FakeRuntime) {
+				runtime.Images[cfg.Docker.ImageTag] = "sha256:test"
+			},
+			want: map[string]Status{
+				"config": StatusPass, "docker": StatusPass, "image": StatusPass,
+				"node.port": StatusPass, "node.api_port": StatusPass, "disk": StatusPass,
+				"memory": StatusPass, "directories": StatusPass, "solc": StatusPass,
+			},
+		},
+		{
+			name: "daemon unreachable",
+			setup: func(t *testing.T, d *Doctor, cfg *config.Config, runtime *system.FakeRuntime) {
+				runtime.DaemonErr = errors.New("cannot connect to the Docker daemon")
+			},
+			want: map[string]Status{"docker": StatusFail},
+		},
+		{
+			name:  "image missing",
+			setup: func(t *testing.T, d *Doctor, cfg *config.Config, runtime *system.FakeRuntime) {},
+			want:  map[string]Status{"docker": StatusPass, "image": StatusWarn},
+		},
+		{
+			name: "port in use",
+			setup: func(t *testing.T, d *Doctor, cfg *config.Config, runtime *system.FakeRuntime) {
+				l, err := net.Listen("tcp", ":"+strconv.Itoa(cfg.Node.Port))
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { l.Close() })
+			},
+			want: map[string]Status{"node.port": StatusFail, "node.api_port": StatusPass},
+		},
+		{
+			name: "ports held by the running node",
+			setup: func(t *testing.T, d *Doctor, cfg *config.Config, runtime *system.FakeRuntime) {
+				l, err := net.Listen("tcp", ":"+strconv.Itoa(cfg.Node.Port))
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { l.Close() })
+				d.nodeRunning = func(ctx context.Context) bool { return true }
+			},
+			want: map[string]Status{"node.port": StatusPass},
+		},
+		{
+			name: "low disk space",
+			setup: func(t *testing.T, d *Doctor, cfg *config.Config, runtime *system.FakeRuntime) {
+				d.diskFree = func(path string) (uint64, error) { return 10 << 30, nil }
+			},
+			want: map[string]Status{"disk": StatusFail},
+		},
+		{
+			name: "below recommended memory",
+			setup: func(t *testing.T, d *Doctor, cfg *config.Config, runtime *system.FakeRuntime) {
+				d.totalMemory = func() (uint64, error) { return 6 << 30, nil }
+			},
+			want: map[string]Status{"memory": StatusWarn},
+		},
+		{
+			name: "below minimum memory",
+			setup: func(t *testing.T, d *Doctor, cfg *config.Config, runtime *system.FakeRuntime) {
+				d.totalMemory = func() (uint64, error) { return 2 << 30, nil }
+			},
+			want: map[string]Status{"memory": StatusFail},
+		},
+		{
+			name: "solc missing",
+			setup: func(t *testing.T, d *Doctor, cfg *config.Config, runtime *system.FakeRuntime) {
+				d.lookPath = func(file string) (string, error) { return "", errors.New("not found") }
+			},
+			want: map[string]Status{"solc": StatusWarn},
+		},
+		{
+			name: "solc version mismatch",
+			setup: func(t *testing.T, d *Doctor, cfg *config.Config, runtime *system.FakeRuntime) {
+				d.solcVersion = func(ctx context.Context, path string) (string, error) { return "0.7.6", nil }
+			},
+			want: map[string]Status{"solc": StatusWarn},
+		},
+		{
+			name: "invalid config",
+			setup: func(t *testing.T, d *Doctor, cfg *config.Config, runtime *system.FakeRuntime) {
+				cfg.Node.NetworkID = 1
+			},
+			want: map[string]Status{"config": StatusFail},
+		},
+		{
+			name: "unwritable directory",
+			setup: func(t *testing.T, d *Doctor, cfg *config.Config, runtime *system.FakeRuntime) {
+				file := filepath.Join(t.TempDir(), "file")
+				if err := os.WriteFile(file, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+				cfg.Node.LogDir = filepath.Join(file, "logs")
+			},
+			// The directory problem is reported once, by the directories check
+			want: map[string]Status{"config": StatusPass, "directories": StatusFail},
+		},
+		{
+			name: "native runtime without a binary",
+			setup: func(t *testing.T, d *Doctor, cfg *config.Config, runtime *system.FakeRuntime) {
+				cfg.Node.Runtime = "native"
+				cfg.Native.BinaryPath = filepath.Join(t.TempDir(), "avalanchego")
+			},
+			want: map[string]Status{"avalanchego": StatusFail},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime := system.NewFakeRuntime()
+			d, cfg := testDoctor(t, runtime)
+			tt.setup(t, d, cfg, runtime)
+
+			results := d.Run(context.Background())
+			got := statuses(results)
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("%s = %q, want %q (results: %+v)", name, got[name], want, results)
+				}
+			}
+			for _, r := range results {
+				if r.Status != StatusPass && r.Message == "" {
+					t.Errorf("%s: %s without a message", r.Name, r.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestExistingAncestor(t *testing.T) {
+	dir := t.TempDir()
+	if got := existingAncestor(filepath.Join(dir, "a", "b")); got != dir {
+		t.Errorf("existingAncestor = %q, want %q", got, dir)
+	}
+	if got := existingAncestor(dir); got != dir {
+		t.Errorf("existingAncestor = %q, want %q", got, dir)
+	}
+}
@@ -2,116 +2,151 @@ package node
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
 )
 
+// PrimaryChains are the primary network chains checked for bootstrapping
+var PrimaryChains = []string{"X", "P", "C"}
+
 // HealthStatus represents the node's health status
 type HealthStatus struct {
-	IsRunning      bool      `json:"is_running"`
-	IsHealthy      bool      `json:"is_healthy"`
-	IsBootstrapped bool      `json:"is_bootstrapped"`
-	Version        string    `json:"version"`
-	NetworkID      int       `json:"network_id"`
-	LastChecked    time.Time `json:"last_checked"`
-	Error          string    `json:"error,omitempty"`
+	IsRunning      bool                   `json:"is_running"`
+	IsHealthy      bool                   `json:"is_healthy"`
+	IsBootstrapped bool                   `json:"is_bootstrapped"`
+	Version        string                 `json:"version"`
+	NetworkID      int                    `json:"network_id"`
+	APIEndpoint    string                 `json:"api_endpoint"`
+	Checks         map[string]CheckResult `json:"checks,omitempty"`
+	Chains         map[string]bool        `json:"chains,omitempty"`
+	LastChecked    time.Time              `json:"last_checked"`
+	Error          string                 `json:"error,omitempty"`
+}
+
+// CheckResult is the outcome of a single avalanchego health check
+type CheckResult struct {
+	Healthy            bool       `json:"healthy"`
+	Error              string     `json:"error,omitempty"`
+	ContiguousFailures int64      `json:"contiguous_failures,omitempty"`
+	TimeOfFirstFailure *time.Time `json:"time_of_first_failure,omitempty"`
+}
+
+// FailingChecks returns the names of the unhealthy checks in sorted order
+func (s *HealthStatus) FailingChecks() []string {
+	var failing []string
+	for name, check := range s.Checks {
+		if !check.Healthy {
+			failing = append(failing, name)
+		}
+	}
+	sort.Strings(failing)
+	return failing
 }
 
 // CheckHealth performs a health check on the node
 func (m *NodeManager) CheckHealth(ctx context.Context) (*HealthStatus, error) {
+	return checkHealth(ctx, m, m.cfg)
+}
+
+// WaitForHealthy waits for the node to become healthy with a timeout
+func (m *NodeManager) WaitForHealthy(ctx context.Context, timeout time.Duration) error {
+	return waitForHealthy(ctx, m, timeout)
+}
+
+// checkHealth queries the health and info APIs of a running node
+func checkHealth(ctx context.Context, m Manager, cfg *config.Config) (*HealthStatus, error) {
 	status := &HealthStatus{
 		LastChecked: time.Now(),
 	}
 
-	// First check if the container is running
+	// First check if the node is running
 	nodeStatus, err := m.Status(ctx)
 	if err != nil {
-		return status, fmt.Errorf("failed to check container status: %w", err)
+		return status, fmt.Errorf("failed to determine whether the node is running: %w", err)
 	}
 	status.IsRunning = nodeStatus.IsRunning
 	status.NetworkID = nodeStatus.NetworkID
 	status.Version = nodeStatus.Version
+	status.APIEndpoint = nodeStatus.APIEndpoint
 
 	if !status.IsRunning {
 		status.Error = "node is not running"
 		return status, nil
 	}
 
-	// Check node health endpoint
-	healthURL := fmt.Sprintf("http://localhost:%d/ext/health", m.cfg.Node.APIPort)
-	client := &http.Client{Timeout: 5 * time.Second}
-
-	req, err := http.NewRequestWithContext(ctx, "GET", healthURL, nil)
-	if err != nil {
-		status.Error = fmt.Sprintf("failed to create request: %v", err)
-		return status, nil
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		status.Error = fmt.Sprintf("failed to connect to node: %v", err)
-		return status, nil
-	}
-	defer resp.Body.Close()
+	api := NewAPIClient(cfg.Node.APIPort)
 
 	var healthResp struct {
-		Healthy   bool   `json:"healthy"`
-		Error     string `json:"error,omitempty"`
-		Timestamp string `json:"timestamp"`
+		Healthy bool `json:"healthy"`
+		Checks  map[string]struct {
+			Error              *string    `json:"error,omitempty"`
+			ContiguousFailures int64      `json:"contiguousFailures,omitempty"`
+			TimeOfFirstFailure *time.Time `json:"timeOfFirstFailure,omitempty"`
+		} `json:"checks"`
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&healthResp); err != nil {
-		status.Error = fmt.Sprintf("failed to decode response: %v", err)
+	if err := api.Call(ctx, "/ext/health", "health.health", nil, &healthResp); err != nil {
+		status.Error = err.Error()
 		return status, nil
 	}
 
 	status.IsHealthy = healthResp.Healthy
-
-	// Check if chains are bootstrapped
-	infoURL := fmt.Sprintf("http://localhost:%d/ext/info", m.cfg.Node.APIPort)
-	req, err = http.NewRequestWithContext(ctx, "GET", infoURL, nil)
-	if err != nil {
-		status.Error = fmt.Sprintf("failed to create info request: %v", err)
-		return status, nil
-	}
-
-	resp, err = client.Do(req)
-	if err != nil {
-		status.Error = fmt.Sprintf("failed to get node info: %v", err)
-		return status, nil
+	status.Checks = make(map[string]CheckResult, len(healthResp.Checks))
+	for name, check := range healthResp.Checks {
+		result := CheckResult{
+			Healthy:            check.Error == nil,
+			ContiguousFailures: check.ContiguousFailures,
+			TimeOfFirstFailure: check.TimeOfFirstFailure,
+		}
+		if check.Error != nil {
+			result.Error = *check.Error
+		}
+		status.Checks[name] = result
 	}
-	defer resp.Body.Close()
 
-	var infoResp struct {
-		NetworkID      int    `json:"networkID"`
-		NodeVersion    string `json:"nodeVersion"`
-		IsBootstrapped bool   `json:"isBootstrapped"`
+	// Check if each primary chain is bootstrapped
+	status.Chains = make(map[string]bool, len(PrimaryChains))
+	status.IsBootstrapped = true
+	for _, chain := range PrimaryChains {
+		var infoResp struct {
+			IsBootstrapped bool `json:"isBootstrapped"`
+		}
+		params := map[string]string{"chain": chain}
+		if err := api.Call(ctx, "/ext/info", "info.isBootstrapped", params, &infoResp); err != nil {
+			status.Error = err.Error()
+			status.IsBootstrapped = false
+			return status, nil
+		}
+		status.Chains[chain] = infoResp.IsBootstrapped
+		status.IsBootstrapped = status.IsBootstrapped && infoResp.IsBootstrapped
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&infoResp); err != nil {
-		status.Error = fmt.Sprintf("failed to decode info response: %v", err)
-		return status, nil
+	if failing := status.FailingChecks(); len(failing) > 0 {
+		status.Error = "failing health checks: " + strings.Join(failing, ", ")
 	}
 
-	status.IsBootstrapped = infoResp.IsBootstrapped
-
 	return status, nil
 }
 
-// WaitForHealthy waits for the node to become healthy with a timeout
-func (m *NodeManager) WaitForHealthy(ctx context.Context, timeout time.Duration) error {
+// waitForHealthy polls the node's health until it is healthy and bootstrapped
+func waitForHealthy(ctx context.Context, m Manager, timeout time.Duration) error {
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
 
 	timeoutCh := time.After(timeout)
 
+	var last *HealthStatus
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-timeoutCh:
+			if last != nil && last.Error != "" {
+				return fmt.Errorf("timeout waiting for node to become healthy: %s", last.Error)
+			}
 			return fmt.Errorf("timeout waiting for node to become healthy")
 		case <-ticker.C:
 			status, err := m.CheckHealth(ctx)
@@ -121,6 +156,7 @@ func (m *NodeManager) WaitForHealthy(ctx context.Context, timeout time.Duration)
 			if status.IsHealthy && status.IsBootstrapped {
 				return nil
 			}
+			last = status
 		}
 	}
 }
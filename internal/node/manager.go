@@ -3,6 +3,8 @@ package node
 import (
 	"context"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types/container"
@@ -16,9 +18,14 @@ import (
 type Manager interface {
 	Start(ctx context.Context, cfg *config.Config) error
 	Stop(ctx context.Context) error
+	// Restart stops the node if running and starts it with the current config
+	Restart(ctx context.Context) error
 	Status(ctx context.Context) (*Status, error)
 	CheckHealth(ctx context.Context) (*HealthStatus, error)
 	WaitForHealthy(ctx context.Context, timeout time.Duration) error
+	// Upgrade installs the given avalanchego version and pins it in the config
+	Upgrade(ctx context.Context, version string) (string, error)
+	SetOutput(w io.Writer)
 	Close() error
 }
 
@@ -33,34 +40,73 @@ type Status struct {
 	LastChecked    time.Time `json:"lastChecked"`
 }
 
+const (
+	// containerPluginDir is where the avalanchego image looks for VM plugins
+	containerPluginDir = "/avalanchego/build/plugins"
+	// containerChainConfigDir is where the chain config directory is mounted
+	containerChainConfigDir = "/root/.avalanchego/configs/chains"
+)
+
+// localNodeFlags returns the avalanchego flags shared by all runtimes for a
+// single-node development network
+func localNodeFlags(cfg *config.Config) []string {
+	// Without sybil protection the lone node validates every chain it tracks
+	flags := []string{"--sybil-protection-enabled=false"}
+	if len(cfg.Node.TrackSubnets) > 0 {
+		flags = append(flags, "--track-subnets="+strings.Join(cfg.Node.TrackSubnets, ","))
+	}
+	return flags
+}
+
 // NodeManager handles Avalanche node operations
 type NodeManager struct {
-	cfg    *config.Config
-	docker *system.DockerClient
+	cfg     *config.Config
+	runtime system.ContainerRuntime
+	out     io.Writer
 }
 
-// NewManager creates a new node manager
+// NewManager creates a new node manager for the configured runtime
 func NewManager(cfg *config.Config) (Manager, error) {
-	docker, err := system.NewDockerClient()
+	if cfg.Node.Runtime == "native" {
+		return NewProcessManager(cfg), nil
+	}
+
+	runtime, err := system.NewContainerRuntime(cfg.Node.Runtime)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create docker client: %w", err)
+		return nil, fmt.Errorf("failed to create container runtime: %w", err)
 	}
+	return NewManagerWithRuntime(cfg, runtime), nil
+}
+
+// NewManagerWithRuntime creates a node manager backed by the given container runtime
+func NewManagerWithRuntime(cfg *config.Config, runtime system.ContainerRuntime) *NodeManager {
 	return &NodeManager{
-		cfg:    cfg,
-		docker: docker,
-	}, nil
+		cfg:     cfg,
+		runtime: runtime,
+		out:     io.Discard,
+	}
+}
+
+// SetOutput sets the writer used for progress output such as image pulls
+func (m *NodeManager) SetOutput(w io.Writer) {
+	m.out = w
 }
 
 // Start starts the Avalanche node
 func (m *NodeManager) Start(ctx context.Context, cfg *config.Config) error {
 	// Check if node is already running
-	running, err := m.docker.IsRunning(ctx, m.cfg.Docker.ContainerName)
+	running, err := m.runtime.IsRunning(ctx, m.cfg.Docker.ContainerName)
 	if err != nil {
-		return fmt.Errorf("failed to check node status: %w", err)
+		return fmt.Errorf("failed to look up node container %s: %w", m.cfg.Docker.ContainerName, err)
 	}
 	if running {
 		return fmt.Errorf("node is already running")
 	}
+	// A stopped container left from an earlier run is recreated, so the
+	// current config applies
+	if err := m.runtime.RemoveContainer(ctx, m.cfg.Docker.ContainerName); err != nil {
+		return err
+	}
 
 	// Ensure directories exist
 	if err := system.EnsureDir(m.cfg.Node.DBDir); err != nil {
@@ -73,22 +119,23 @@ func (m *NodeManager) Start(ctx context.Context, cfg *config.Config) error {
 		return fmt.Errorf("failed to create staking directory: %w", err)
 	}
 
-	// Pull the latest image
-	if err := m.docker.PullImage(ctx, m.cfg.Docker.ImageTag); err != nil {
-		return fmt.Errorf("failed to pull image: %w", err)
+	image := m.image()
+	if err := m.ensureImage(ctx, image); err != nil {
+		return err
 	}
 
 	// Create container configuration
 	containerConfig := &container.Config{
-		Image: m.cfg.Docker.ImageTag,
-		Cmd: []string{
+		Image: image,
+		Cmd: append([]string{
 			"--network-id=" + fmt.Sprint(m.cfg.Node.NetworkID),
 			"--http-host=0.0.0.0",
+			"--http-port=" + fmt.Sprint(m.cfg.Node.APIPort),
+			"--staking-port=" + fmt.Sprint(m.cfg.Node.Port),
 			"--public-ip=127.0.0.1",
 			"--db-dir=/root/.avalanchego/db",
 			"--log-dir=/root/.avalanchego/logs",
-			"--staking-enabled=false",
-		},
+		}, localNodeFlags(m.cfg)...),
 		ExposedPorts: nat.PortSet{
 			nat.Port(fmt.Sprintf("%d/tcp", m.cfg.Node.Port)):    struct{}{},
 			nat.Port(fmt.Sprintf("%d/tcp", m.cfg.Node.APIPort)): struct{}{},
@@ -124,12 +171,34 @@ func (m *NodeManager) Start(ctx context.Context, cfg *config.Config) error {
 		},
 	}
 
+	if m.cfg.Node.PluginDir != "" {
+		if err := system.EnsureDir(m.cfg.Node.PluginDir); err != nil {
+			return fmt.Errorf("failed to create plugin directory: %w", err)
+		}
+		hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+			Type:   mount.TypeBind,
+			Source: m.cfg.Node.PluginDir,
+			Target: containerPluginDir,
+		})
+	}
+	if m.cfg.Node.ChainConfigDir != "" {
+		if err := system.EnsureDir(m.cfg.Node.ChainConfigDir); err != nil {
+			return fmt.Errorf("failed to create chain config directory: %w", err)
+		}
+		hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+			Type:   mount.TypeBind,
+			Source: m.cfg.Node.ChainConfigDir,
+			Target: containerChainConfigDir,
+		})
+		containerConfig.Cmd = append(containerConfig.Cmd, "--chain-config-dir="+containerChainConfigDir)
+	}
+
 	// Create and start the container
-	if err := m.docker.CreateContainer(ctx, containerConfig, hostConfig, m.cfg.Docker.ContainerName); err != nil {
+	if err := m.runtime.CreateContainer(ctx, containerConfig, hostConfig, m.cfg.Docker.ContainerName); err != nil {
 		return fmt.Errorf("failed to create container: %w", err)
 	}
 
-	if err := m.docker.StartContainer(ctx, m.cfg.Docker.ContainerName); err != nil {
+	if err := m.runtime.StartContainer(ctx, m.cfg.Docker.ContainerName); err != nil {
 		return fmt.Errorf("failed to start container: %w", err)
 	}
 
@@ -138,26 +207,106 @@ func (m *NodeManager) Start(ctx context.Context, cfg *config.Config) error {
 
 // Stop stops the Avalanche node
 func (m *NodeManager) Stop(ctx context.Context) error {
-	running, err := m.docker.IsRunning(ctx, m.cfg.Docker.ContainerName)
+	running, err := m.runtime.IsRunning(ctx, m.cfg.Docker.ContainerName)
 	if err != nil {
-		return fmt.Errorf("failed to check node status: %w", err)
+		return fmt.Errorf("failed to look up node container %s: %w", m.cfg.Docker.ContainerName, err)
 	}
 	if !running {
 		return fmt.Errorf("node is not running")
 	}
 
-	if err := m.docker.StopContainer(ctx, m.cfg.Docker.ContainerName); err != nil {
+	if err := m.runtime.StopContainer(ctx, m.cfg.Docker.ContainerName); err != nil {
 		return fmt.Errorf("failed to stop container: %w", err)
 	}
 
 	return nil
 }
 
+// Restart recreates the node container so config changes take effect
+func (m *NodeManager) Restart(ctx context.Context) error {
+	running, err := m.runtime.IsRunning(ctx, m.cfg.Docker.ContainerName)
+	if err != nil {
+		return fmt.Errorf("failed to look up node container %s: %w", m.cfg.Docker.ContainerName, err)
+	}
+	if running {
+		if err := m.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	return m.Start(ctx, m.cfg)
+}
+
+// Upgrade pulls the given avalanchego version and pins the node image to its
+// digest, recreating the container if the node was running. It returns the
+// pinned image reference.
+func (m *NodeManager) Upgrade(ctx context.Context, version string) (string, error) {
+	image := system.ImageRepository(m.image()) + ":" + version
+
+	if err := m.ensureImage(ctx, image); err != nil {
+		return "", err
+	}
+
+	digest, err := m.runtime.ImageDigest(ctx, image)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve image digest: %w", err)
+	}
+
+	running, err := m.runtime.IsRunning(ctx, m.cfg.Docker.ContainerName)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up node container %s: %w", m.cfg.Docker.ContainerName, err)
+	}
+	if running {
+		if err := m.Stop(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	// The container is bound to its image, so it must be recreated
+	if err := m.runtime.RemoveContainer(ctx, m.cfg.Docker.ContainerName); err != nil {
+		return "", err
+	}
+
+	m.cfg.Docker.ImageTag = digest
+	if running {
+		if err := m.Start(ctx, m.cfg); err != nil {
+			return "", err
+		}
+	}
+
+	return digest, nil
+}
+
+// image returns the node image: docker.image_tag, or the default image when
+// it is unset
+func (m *NodeManager) image() string {
+	if m.cfg.Docker.ImageTag != "" {
+		return m.cfg.Docker.ImageTag
+	}
+	return config.DefaultConfig().Docker.ImageTag
+}
+
+// ensureImage pulls the image unless it is already present locally
+func (m *NodeManager) ensureImage(ctx context.Context, image string) error {
+	exists, err := m.runtime.ImageExists(ctx, image)
+	if err != nil {
+		return fmt.Errorf("failed to check image: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	fmt.Fprintf(m.out, "Pulling image %s...\n", image)
+	if err := m.runtime.PullImage(ctx, image, m.out); err != nil {
+		return fmt.Errorf("failed to pull image: %w", err)
+	}
+	return nil
+}
+
 // Status returns the current node status
 func (m *NodeManager) Status(ctx context.Context) (*Status, error) {
-	running, err := m.docker.IsRunning(ctx, m.cfg.Docker.ContainerName)
+	running, err := m.runtime.IsRunning(ctx, m.cfg.Docker.ContainerName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check node status: %w", err)
+		return nil, fmt.Errorf("failed to look up node container %s: %w", m.cfg.Docker.ContainerName, err)
 	}
 
 	status := &Status{
@@ -175,5 +324,5 @@ func (m *NodeManager) Status(ctx context.Context) (*Status, error) {
 
 // Close cleans up resources
 func (m *NodeManager) Close() error {
-	return m.docker.Close()
+	return m.runtime.Close()
 }
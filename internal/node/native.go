@@ -0,0 +1,562 @@
+package node
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/system"
+)
+
+// DefaultAvalanchegoVersion is the avalanchego release downloaded when no
+// binary path or version is configured for native mode
+const DefaultAvalanchegoVersion = "v1.11.3"
+
+const (
+	pidFileName = "avalanchego.pid"
+	logFileName = "avalanchego.log"
+)
+
+// ProcessManager runs avalanchego as a local child process instead of a container
+type ProcessManager struct {
+	cfg         *config.Config
+	out         io.Writer
+	stopTimeout time.Duration
+
+	// proc and exited are only set when this manager started the process,
+	// so it can be reaped instead of lingering as a zombie
+	proc    *os.Process
+	exited  chan struct{}
+	exitErr error
+}
+
+// NewProcessManager creates a node manager for native process mode
+func NewProcessManager(cfg *config.Config) *ProcessManager {
+	return &ProcessManager{
+		cfg:         cfg,
+		out:         io.Discard,
+		stopTimeout: 30 * time.Second,
+	}
+}
+
+// SetOutput sets the writer used for progress output such as binary downloads
+func (m *ProcessManager) SetOutput(w io.Writer) {
+	m.out = w
+}
+
+// Start starts avalanchego as a detached child process
+func (m *ProcessManager) Start(ctx context.Context, cfg *config.Config) error {
+	if pid, running := m.runningPID(); running {
+		return fmt.Errorf("node is already running (pid %d)", pid)
+	}
+
+	// Ensure directories exist
+	if err := system.EnsureDir(m.cfg.Node.DBDir); err != nil {
+		return fmt.Errorf("failed to create DB directory: %w", err)
+	}
+	if err := system.EnsureDir(m.cfg.Node.LogDir); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if err := system.EnsureDir(m.cfg.Node.StakingDir); err != nil {
+		return fmt.Errorf("failed to create staking directory: %w", err)
+	}
+
+	if err := ensureStakingCert(m.cfg.Node.StakingDir); err != nil {
+		return fmt.Errorf("failed to create staking certificate: %w", err)
+	}
+
+	binary, err := m.ensureBinary(ctx, m.cfg.Native.Version)
+	if err != nil {
+		return err
+	}
+
+	logFile, err := os.OpenFile(filepath.Join(m.cfg.Node.LogDir, logFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer logFile.Close()
+
+	args := []string{
+		"--network-id=" + fmt.Sprint(m.cfg.Node.NetworkID),
+		"--http-host=127.0.0.1",
+		"--http-port=" + fmt.Sprint(m.cfg.Node.APIPort),
+		"--staking-port=" + fmt.Sprint(m.cfg.Node.Port),
+		"--public-ip=127.0.0.1",
+		"--db-dir=" + m.cfg.Node.DBDir,
+		"--log-dir=" + m.cfg.Node.LogDir,
+		"--staking-tls-cert-file=" + filepath.Join(m.cfg.Node.StakingDir, "staker.crt"),
+		"--staking-tls-key-file=" + filepath.Join(m.cfg.Node.StakingDir, "staker.key"),
+	}
+	if m.cfg.Node.PluginDir != "" {
+		args = append(args, "--plugin-dir="+m.cfg.Node.PluginDir)
+	}
+	if m.cfg.Node.ChainConfigDir != "" {
+		args = append(args, "--chain-config-dir="+m.cfg.Node.ChainConfigDir)
+	}
+	args = append(args, localNodeFlags(m.cfg)...)
+
+	cmd := exec.Command(binary, args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = detachedProcAttr()
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start avalanchego: %w", err)
+	}
+
+	// The PID file records the binary too, so a later run can tell the node
+	// from an unrelated process that reused its PID
+	if abs, err := filepath.Abs(binary); err == nil {
+		binary = abs
+	}
+	pidData := strconv.Itoa(cmd.Process.Pid) + "\n" + binary + "\n"
+	if err := os.WriteFile(m.pidFile(), []byte(pidData), 0644); err != nil {
+		cmd.Process.Kill()
+		return fmt.Errorf("failed to write PID file: %w", err)
+	}
+
+	m.proc = cmd.Process
+	m.exited = make(chan struct{})
+	go func(exited chan struct{}) {
+		m.exitErr = cmd.Wait()
+		close(exited)
+	}(m.exited)
+
+	return nil
+}
+
+// Stop sends SIGTERM to the node, escalating to SIGKILL if it does not exit in time
+func (m *ProcessManager) Stop(ctx context.Context) error {
+	pid, running := m.runningPID()
+	if !running {
+		return fmt.Errorf("node is not running")
+	}
+
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return fmt.Errorf("failed to find node process: %w", err)
+	}
+
+	if err := terminateProcess(proc); err != nil {
+		return fmt.Errorf("failed to signal node process: %w", err)
+	}
+
+	if !m.waitForExit(ctx, pid, m.stopTimeout) {
+		if err := proc.Kill(); err != nil {
+			return fmt.Errorf("failed to kill node process: %w", err)
+		}
+		if !m.waitForExit(ctx, pid, 5*time.Second) {
+			return fmt.Errorf("node process %d did not exit", pid)
+		}
+	}
+
+	if err := os.Remove(m.pidFile()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove PID file: %w", err)
+	}
+	return nil
+}
+
+// Restart stops the node if running and starts it with the current config
+func (m *ProcessManager) Restart(ctx context.Context) error {
+	if _, running := m.runningPID(); running {
+		if err := m.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	return m.Start(ctx, m.cfg)
+}
+
+// Status returns the current node status
+func (m *ProcessManager) Status(ctx context.Context) (*Status, error) {
+	_, running := m.runningPID()
+
+	version := m.version()
+	if m.cfg.Native.BinaryPath != "" {
+		version = m.cfg.Native.BinaryPath
+	}
+
+	return &Status{
+		IsRunning:      running,
+		IsHealthy:      running,
+		IsBootstrapped: running,
+		Version:        version,
+		NetworkID:      m.cfg.Node.NetworkID,
+		APIEndpoint:    fmt.Sprintf("http://localhost:%d", m.cfg.Node.APIPort),
+		LastChecked:    time.Now(),
+	}, nil
+}
+
+// CheckHealth performs a health check on the node
+func (m *ProcessManager) CheckHealth(ctx context.Context) (*HealthStatus, error) {
+	return checkHealth(ctx, m, m.cfg)
+}
+
+// WaitForHealthy waits for the node to become healthy with a timeout. If this
+// manager started the node, it fails as soon as the process exits.
+func (m *ProcessManager) WaitForHealthy(ctx context.Context, timeout time.Duration) error {
+	if m.exited == nil {
+		return waitForHealthy(ctx, m, timeout)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-m.exited:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := waitForHealthy(ctx, m, timeout)
+	select {
+	case <-m.exited:
+		if err != nil {
+			return m.exitError()
+		}
+	default:
+	}
+	return err
+}
+
+// Upgrade installs the given avalanchego version and restarts the node if it
+// was running. It returns the path of the installed binary.
+func (m *ProcessManager) Upgrade(ctx context.Context, version string) (string, error) {
+	if m.cfg.Native.BinaryPath != "" {
+		return "", fmt.Errorf("native.binary_path is set; upgrade the binary at %s manually", m.cfg.Native.BinaryPath)
+	}
+
+	binary, err := m.ensureBinary(ctx, version)
+	if err != nil {
+		return "", err
+	}
+
+	_, running := m.runningPID()
+	if running {
+		if err := m.Stop(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	m.cfg.Native.Version = version
+	if running {
+		if err := m.Start(ctx, m.cfg); err != nil {
+			return "", err
+		}
+	}
+
+	return binary, nil
+}
+
+// Close cleans up resources
+func (m *ProcessManager) Close() error {
+	return nil
+}
+
+// pidFile returns the path of the file recording the node's PID
+func (m *ProcessManager) pidFile() string {
+	return filepath.Join(m.cfg.Node.LogDir, pidFileName)
+}
+
+// runningPID returns the PID from the PID file and whether that process is
+// the running node. A PID file left by a node that is gone, or whose PID now
+// belongs to another program, is removed.
+func (m *ProcessManager) runningPID() (int, bool) {
+	data, err := os.ReadFile(m.pidFile())
+	if err != nil {
+		return 0, false
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	pid, err := strconv.Atoi(strings.TrimSpace(lines[0]))
+	if err != nil {
+		return 0, false
+	}
+
+	// PID files written by older versions hold only the PID
+	var binary string
+	if len(lines) > 1 {
+		binary = strings.TrimSpace(lines[1])
+	} else if binary, err = m.configuredBinary(); err != nil {
+		return pid, m.alive(pid)
+	}
+
+	if m.alive(pid) && (m.started(pid) || processRuns(pid, binary)) {
+		return pid, true
+	}
+	os.Remove(m.pidFile())
+	return pid, false
+}
+
+// started reports whether this manager started the process with the given PID
+func (m *ProcessManager) started(pid int) bool {
+	return m.proc != nil && m.proc.Pid == pid
+}
+
+// configuredBinary returns the binary the config runs, without downloading it
+func (m *ProcessManager) configuredBinary() (string, error) {
+	if m.cfg.Native.BinaryPath != "" {
+		return filepath.Abs(m.cfg.Native.BinaryPath)
+	}
+	return DownloadedBinary(m.version())
+}
+
+// alive reports whether the process is still running
+func (m *ProcessManager) alive(pid int) bool {
+	if m.started(pid) {
+		select {
+		case <-m.exited:
+			return false
+		default:
+			return true
+		}
+	}
+	return processAlive(pid)
+}
+
+// waitForExit polls until the process exits or the timeout elapses
+func (m *ProcessManager) waitForExit(ctx context.Context, pid int, timeout time.Duration) bool {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	timeoutCh := time.After(timeout)
+
+	for {
+		if !m.alive(pid) {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timeoutCh:
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// exitError describes a node that exited before becoming healthy, with the
+// end of its log
+func (m *ProcessManager) exitError() error {
+	msg := "node exited before becoming healthy"
+	if m.exitErr != nil {
+		msg += " (" + m.exitErr.Error() + ")"
+	}
+
+	logPath := filepath.Join(m.cfg.Node.LogDir, logFileName)
+	tail := logTail(logPath, 20)
+	if tail == "" {
+		return fmt.Errorf("%s; see %s", msg, logPath)
+	}
+	return fmt.Errorf("%s; last lines of %s:\n%s", msg, logPath, tail)
+}
+
+// logTail returns the last n lines of a log file, or "" if it cannot be read.
+// Only the end of the file is read, as the log is appended to across runs.
+func logTail(path string, n int) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	const maxTail = 16 * 1024
+	if info, err := f.Stat(); err == nil && info.Size() > maxTail {
+		f.Seek(-maxTail, io.SeekEnd)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// version returns the configured avalanchego version
+func (m *ProcessManager) version() string {
+	if m.cfg.Native.Version != "" {
+		return m.cfg.Native.Version
+	}
+	return DefaultAvalanchegoVersion
+}
+
+// ensureBinary returns the avalanchego binary to run, downloading the
+// requested release if no binary path is configured
+func (m *ProcessManager) ensureBinary(ctx context.Context, version string) (string, error) {
+	if m.cfg.Native.BinaryPath != "" {
+		if _, err := os.Stat(m.cfg.Native.BinaryPath); err != nil {
+			return "", fmt.Errorf("avalanchego binary not found: %w", err)
+		}
+		return m.cfg.Native.BinaryPath, nil
+	}
+
+	if version == "" {
+		version = m.version()
+	}
+
+	binary, err := DownloadedBinary(version)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(binary); err == nil {
+		return binary, nil
+	}
+
+	fmt.Fprintf(m.out, "Downloading avalanchego %s...\n", version)
+	if err := downloadAvalanchego(ctx, version, filepath.Dir(binary)); err != nil {
+		return "", err
+	}
+	return binary, nil
+}
+
+// DownloadedBinary returns where native mode keeps the downloaded binary of
+// an avalanchego release
+func DownloadedBinary(version string) (string, error) {
+	dataDir, err := system.GetDataDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get data directory: %w", err)
+	}
+	return filepath.Join(dataDir, "bin", "avalanchego-"+version, "avalanchego"), nil
+}
+
+// downloadAvalanchego fetches a release tarball from GitHub and extracts the
+// binary and plugins into destDir
+func downloadAvalanchego(ctx context.Context, version, destDir string) error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("automatic avalanchego download is only supported on linux; set native.binary_path")
+	}
+
+	url := fmt.Sprintf(
+		"https://github.com/ava-labs/avalanchego/releases/download/%s/avalanchego-linux-%s-%s.tar.gz",
+		version, runtime.GOARCH, version,
+	)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create download request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download avalanchego: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download avalanchego %s: %s", version, resp.Status)
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read avalanchego archive: %w", err)
+	}
+	defer gz.Close()
+
+	if err := system.EnsureDir(destDir); err != nil {
+		return fmt.Errorf("failed to create binary directory: %w", err)
+	}
+
+	// Archives contain a single top-level avalanchego-<version>/ directory
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read avalanchego archive: %w", err)
+		}
+
+		parts := strings.SplitN(filepath.ToSlash(hdr.Name), "/", 2)
+		if len(parts) != 2 || parts[1] == "" || strings.Contains(parts[1], "..") {
+			continue
+		}
+		target := filepath.Join(destDir, filepath.FromSlash(parts[1]))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := system.EnsureDir(target); err != nil {
+				return fmt.Errorf("failed to extract %s: %w", hdr.Name, err)
+			}
+		case tar.TypeReg:
+			if err := extractFile(tr, target, os.FileMode(hdr.Mode)); err != nil {
+				return fmt.Errorf("failed to extract %s: %w", hdr.Name, err)
+			}
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(destDir, "avalanchego")); err != nil {
+		return fmt.Errorf("avalanchego binary missing from release archive")
+	}
+	return nil
+}
+
+// extractFile writes a single archive entry to disk
+func extractFile(r io.Reader, target string, mode os.FileMode) error {
+	if err := system.EnsureDir(filepath.Dir(target)); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
+
+// ensureStakingCert generates a self-signed staking certificate if none exists.
+// avalanchego only generates one itself when using its default paths.
+func ensureStakingCert(stakingDir string) error {
+	certPath := filepath.Join(stakingDir, "staker.crt")
+	keyPath := filepath.Join(stakingDir, "staker.key")
+	if _, err := os.Stat(certPath); err == nil {
+		return nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(0),
+		NotBefore:    time.Date(2000, time.January, 0, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Now().AddDate(100, 0, 0),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageDataEncipherment,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes}), 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), 0644)
+}
@@ -2,12 +2,85 @@ package node
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/system"
 )
 
+// testConfig returns a default config with node directories in a temp dir
+func testConfig(t *testing.T) *config.Config {
+	t.Helper()
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Node.DBDir = filepath.Join(tmpDir, "db")
+	cfg.Node.LogDir = filepath.Join(tmpDir, "logs")
+	cfg.Node.StakingDir = filepath.Join(tmpDir, "staking")
+	return cfg
+}
+
+// fakeNodeAPI serves the node JSON-RPC endpoints from a test server and
+// points the config's API port at it. failing lists health checks to report
+// as unhealthy.
+func fakeNodeAPI(t *testing.T, cfg *config.Config, failing ...string) {
+	t.Helper()
+	respond := func(w http.ResponseWriter, result any) {
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": 1, "result": result})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ext/health", func(w http.ResponseWriter, r *http.Request) {
+		checks := map[string]any{}
+		for _, name := range []string{"network", "bootstrapped", "database", "router"} {
+			checks[name] = map[string]any{"message": map[string]any{}}
+		}
+		for _, name := range failing {
+			checks[name] = map[string]any{"error": name + " check failed", "contiguousFailures": 3}
+		}
+		respond(w, map[string]any{"checks": checks, "healthy": len(failing) == 0})
+	})
+	mux.HandleFunc("/ext/info", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		switch req.Method {
+		case "info.isBootstrapped":
+			respond(w, map[string]any{"isBootstrapped": true})
+		case "info.getNodeVersion":
+			respond(w, map[string]any{
+				"version":            "avalanchego/1.11.3",
+				"databaseVersion":    "v1.4.5",
+				"rpcProtocolVersion": "35",
+				"gitCommit":          "abc123",
+				"vmVersions":         map[string]string{"platform": "v1.11.3"},
+			})
+		default:
+			http.Error(w, "unexpected method", http.StatusBadRequest)
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to parse test server address: %v", err)
+	}
+	cfg.Node.APIPort, _ = strconv.Atoi(port)
+}
+
 func TestNewManager(t *testing.T) {
 	cfg := config.DefaultConfig()
 	manager, err := NewManager(cfg)
@@ -21,16 +94,18 @@ func TestNewManager(t *testing.T) {
 	}
 }
 
-func TestNodeLifecycle(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping node lifecycle test in short mode")
-	}
-
+func TestNewManagerUnsupportedRuntime(t *testing.T) {
 	cfg := config.DefaultConfig()
-	manager, err := NewManager(cfg)
-	if err != nil {
-		t.Fatalf("Failed to create node manager: %v", err)
+	cfg.Node.Runtime = "rkt"
+	if _, err := NewManager(cfg); err == nil {
+		t.Error("Expected error for unsupported runtime")
 	}
+}
+
+func TestNodeLifecycle(t *testing.T) {
+	cfg := testConfig(t)
+	runtime := system.NewFakeRuntime()
+	manager := NewManagerWithRuntime(cfg, runtime)
 	defer manager.Close()
 
 	ctx := context.Background()
@@ -48,9 +123,14 @@ func TestNodeLifecycle(t *testing.T) {
 	if err := manager.Start(ctx, cfg); err != nil {
 		t.Fatalf("Failed to start node: %v", err)
 	}
+	if len(runtime.Pulls) != 1 || runtime.Pulls[0] != cfg.Docker.ImageTag {
+		t.Errorf("expected a single pull of %s, got %v", cfg.Docker.ImageTag, runtime.Pulls)
+	}
 
-	// Wait for node to start
-	time.Sleep(5 * time.Second)
+	// Test starting an already running node
+	if err := manager.Start(ctx, cfg); err == nil {
+		t.Error("Starting a running node should fail")
+	}
 
 	// Test status after starting
 	status, err = manager.Status(ctx)
@@ -61,23 +141,11 @@ func TestNodeLifecycle(t *testing.T) {
 		t.Error("Node should be running after start")
 	}
 
-	// Test health check
-	health, err := manager.CheckHealth(ctx)
-	if err != nil {
-		t.Fatalf("Failed to check health: %v", err)
-	}
-	if !health.IsRunning {
-		t.Error("Health check should show node as running")
-	}
-
 	// Test stopping node
 	if err := manager.Stop(ctx); err != nil {
 		t.Fatalf("Failed to stop node: %v", err)
 	}
 
-	// Wait for node to stop
-	time.Sleep(2 * time.Second)
-
 	// Test status after stopping
 	status, err = manager.Status(ctx)
 	if err != nil {
@@ -86,18 +154,92 @@ func TestNodeLifecycle(t *testing.T) {
 	if status.IsRunning {
 		t.Error("Node should not be running after stop")
 	}
+
+	// Test stopping a stopped node
+	if err := manager.Stop(ctx); err == nil {
+		t.Error("Stopping a stopped node should fail")
+	}
+
+	// Test starting a stopped node, whose container is recreated with the
+	// current config
+	cfg.Node.TrackSubnets = []string{"subnet"}
+	if err := manager.Start(ctx, cfg); err != nil {
+		t.Fatalf("Failed to start a stopped node: %v", err)
+	}
+	c := runtime.Containers[cfg.Docker.ContainerName]
+	if c == nil || !c.Running || !strings.Contains(strings.Join(c.Config.Cmd, " "), "--track-subnets=subnet") {
+		t.Errorf("expected a running container with the current config, got %+v", c)
+	}
 }
 
-func TestWaitForHealthy(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping wait for healthy test in short mode")
+func TestStartDefaultImage(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Docker.ImageTag = ""
+	runtime := system.NewFakeRuntime()
+	manager := NewManagerWithRuntime(cfg, runtime)
+
+	if err := manager.Start(context.Background(), cfg); err != nil {
+		t.Fatalf("Failed to start node: %v", err)
+	}
+	want := config.DefaultConfig().Docker.ImageTag
+	if c := runtime.Containers[cfg.Docker.ContainerName]; c == nil || c.Config.Image != want {
+		t.Errorf("expected container image %s, got %+v", want, c)
 	}
+}
 
-	cfg := config.DefaultConfig()
-	manager, err := NewManager(cfg)
+func TestStartSkipsPresentImage(t *testing.T) {
+	cfg := testConfig(t)
+	runtime := system.NewFakeRuntime()
+	runtime.Images[cfg.Docker.ImageTag] = "avaplatform/avalanchego@sha256:local"
+	manager := NewManagerWithRuntime(cfg, runtime)
+
+	if err := manager.Start(context.Background(), cfg); err != nil {
+		t.Fatalf("Failed to start node: %v", err)
+	}
+	if len(runtime.Pulls) != 0 {
+		t.Errorf("expected no pulls for a local image, got %v", runtime.Pulls)
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	cfg := testConfig(t)
+	runtime := system.NewFakeRuntime()
+	manager := NewManagerWithRuntime(cfg, runtime)
+	ctx := context.Background()
+
+	if err := manager.Start(ctx, cfg); err != nil {
+		t.Fatalf("Failed to start node: %v", err)
+	}
+
+	image, err := manager.Upgrade(ctx, "v1.11.3")
 	if err != nil {
-		t.Fatalf("Failed to create node manager: %v", err)
+		t.Fatalf("Failed to upgrade node: %v", err)
+	}
+
+	want := runtime.Images["avaplatform/avalanchego:v1.11.3"]
+	if image != want {
+		t.Errorf("expected pinned image %s, got %s", want, image)
+	}
+	if cfg.Docker.ImageTag != image {
+		t.Errorf("expected config image tag %s, got %s", image, cfg.Docker.ImageTag)
 	}
+
+	c, ok := runtime.Containers[cfg.Docker.ContainerName]
+	if !ok {
+		t.Fatal("expected node container to be recreated")
+	}
+	if !c.Running {
+		t.Error("expected upgraded node to be running")
+	}
+	if c.Config.Image != image {
+		t.Errorf("expected container image %s, got %s", image, c.Config.Image)
+	}
+}
+
+func TestWaitForHealthy(t *testing.T) {
+	cfg := testConfig(t)
+	fakeNodeAPI(t, cfg)
+	manager := NewManagerWithRuntime(cfg, system.NewFakeRuntime())
 	defer manager.Close()
 
 	ctx := context.Background()
@@ -109,7 +251,7 @@ func TestWaitForHealthy(t *testing.T) {
 	defer manager.Stop(ctx)
 
 	// Test waiting for healthy with timeout
-	timeout := 2 * time.Minute
+	timeout := 10 * time.Second
 	if err := manager.WaitForHealthy(ctx, timeout); err != nil {
 		t.Fatalf("Failed to wait for healthy: %v", err)
 	}
@@ -124,12 +266,47 @@ func TestWaitForHealthy(t *testing.T) {
 	}
 }
 
-func TestHealthStatus(t *testing.T) {
-	cfg := config.DefaultConfig()
-	manager, err := NewManager(cfg)
+func TestHealthStatusFailingChecks(t *testing.T) {
+	cfg := testConfig(t)
+	fakeNodeAPI(t, cfg, "bootstrapped", "network")
+	manager := NewManagerWithRuntime(cfg, system.NewFakeRuntime())
+	ctx := context.Background()
+
+	if err := manager.Start(ctx, cfg); err != nil {
+		t.Fatalf("Failed to start node: %v", err)
+	}
+
+	health, err := manager.CheckHealth(ctx)
 	if err != nil {
-		t.Fatalf("Failed to create node manager: %v", err)
+		t.Fatalf("Failed to check health: %v", err)
+	}
+	if health.IsHealthy {
+		t.Error("Node should be unhealthy with failing checks")
+	}
+	if !health.IsBootstrapped {
+		t.Error("Node should report all primary chains bootstrapped")
+	}
+	for _, chain := range PrimaryChains {
+		if !health.Chains[chain] {
+			t.Errorf("expected chain %s to be bootstrapped", chain)
+		}
+	}
+
+	failing := health.FailingChecks()
+	if strings.Join(failing, ",") != "bootstrapped,network" {
+		t.Errorf("expected failing checks [bootstrapped network], got %v", failing)
 	}
+	if check := health.Checks["network"]; check.Error != "network check failed" || check.ContiguousFailures != 3 {
+		t.Errorf("unexpected network check result: %+v", check)
+	}
+	if !health.Checks["database"].Healthy {
+		t.Error("expected database check to be healthy")
+	}
+}
+
+func TestHealthStatus(t *testing.T) {
+	cfg := testConfig(t)
+	manager := NewManagerWithRuntime(cfg, system.NewFakeRuntime())
 	defer manager.Close()
 
 	ctx := context.Background()
@@ -146,3 +323,226 @@ func TestHealthStatus(t *testing.T) {
 		t.Error("Health status should contain error message when node is not running")
 	}
 }
+
+// fakeBinary writes a shell script standing in for avalanchego
+func fakeBinary(t *testing.T, script string) string {
+	t.Helper()
+	if goruntime.GOOS == "windows" {
+		t.Skip("native mode tests require a POSIX shell")
+	}
+	path := filepath.Join(t.TempDir(), "avalanchego")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatalf("Failed to write fake binary: %v", err)
+	}
+	return path
+}
+
+func TestHealthStatusDaemonUnreachable(t *testing.T) {
+	cfg := testConfig(t)
+	runtime := system.NewFakeRuntime()
+	runtime.DaemonErr = errors.New("cannot connect to the daemon")
+	manager := NewManagerWithRuntime(cfg, runtime)
+
+	_, err := manager.CheckHealth(context.Background())
+	if !errors.Is(err, runtime.DaemonErr) {
+		t.Fatalf("CheckHealth() error = %v, want the daemon error", err)
+	}
+	// Each layer adds its own context
+	want := "failed to determine whether the node is running: failed to look up node container " +
+		cfg.Docker.ContainerName + ": cannot connect to the daemon"
+	if err.Error() != want {
+		t.Errorf("CheckHealth() error = %q, want %q", err, want)
+	}
+}
+
+func TestProcessManagerLifecycle(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Node.Runtime = "native"
+	cfg.Native.BinaryPath = fakeBinary(t, `echo "started $@"; exec sleep 60`)
+
+	manager, err := NewManager(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create node manager: %v", err)
+	}
+	defer manager.Close()
+
+	ctx := context.Background()
+
+	if err := manager.Start(ctx, cfg); err != nil {
+		t.Fatalf("Failed to start node: %v", err)
+	}
+
+	status, err := manager.Status(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get status after start: %v", err)
+	}
+	if !status.IsRunning {
+		t.Error("Node should be running after start")
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Node.LogDir, pidFileName)); err != nil {
+		t.Errorf("expected PID file: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Node.StakingDir, "staker.crt")); err != nil {
+		t.Errorf("expected staking certificate: %v", err)
+	}
+
+	if err := manager.Start(ctx, cfg); err == nil {
+		t.Error("Starting a running node should fail")
+	}
+
+	// Give the process time to write its output
+	time.Sleep(200 * time.Millisecond)
+
+	if err := manager.Stop(ctx); err != nil {
+		t.Fatalf("Failed to stop node: %v", err)
+	}
+
+	status, err = manager.Status(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get status after stop: %v", err)
+	}
+	if status.IsRunning {
+		t.Error("Node should not be running after stop")
+	}
+
+	logs, err := os.ReadFile(filepath.Join(cfg.Node.LogDir, logFileName))
+	if err != nil {
+		t.Fatalf("Failed to read node log: %v", err)
+	}
+	if !strings.Contains(string(logs), "--http-port="+strconv.Itoa(cfg.Node.APIPort)) {
+		t.Errorf("expected node output in log file, got %q", logs)
+	}
+}
+
+func TestProcessManagerStopEscalates(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Native.BinaryPath = fakeBinary(t, `trap '' TERM; while true; do sleep 1; done`)
+
+	manager := NewProcessManager(cfg)
+	manager.stopTimeout = 500 * time.Millisecond
+	ctx := context.Background()
+
+	if err := manager.Start(ctx, cfg); err != nil {
+		t.Fatalf("Failed to start node: %v", err)
+	}
+	// Give the shell time to install its trap
+	time.Sleep(200 * time.Millisecond)
+
+	if err := manager.Stop(ctx); err != nil {
+		t.Fatalf("Failed to stop node: %v", err)
+	}
+	if status, _ := manager.Status(ctx); status.IsRunning {
+		t.Error("Node should be killed after ignoring SIGTERM")
+	}
+}
+
+func TestProcessManagerStalePIDFile(t *testing.T) {
+	binary := fakeBinary(t, `exec sleep 60`)
+
+	// An unrelated process that now holds the PID recorded for the node
+	other := exec.Command("sleep", "60")
+	if err := other.Start(); err != nil {
+		t.Fatalf("Failed to start process: %v", err)
+	}
+	t.Cleanup(func() {
+		other.Process.Kill()
+		other.Wait()
+	})
+	pid := strconv.Itoa(other.Process.Pid)
+
+	tests := []struct {
+		name    string
+		pidFile string
+	}{
+		{"with binary", pid + "\n" + binary + "\n"},
+		{"pid only", pid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.Native.BinaryPath = binary
+			pidFile := filepath.Join(cfg.Node.LogDir, pidFileName)
+			if err := system.EnsureDir(cfg.Node.LogDir); err != nil {
+				t.Fatalf("Failed to create log dir: %v", err)
+			}
+			if err := os.WriteFile(pidFile, []byte(tt.pidFile), 0644); err != nil {
+				t.Fatalf("Failed to write PID file: %v", err)
+			}
+
+			manager := NewProcessManager(cfg)
+			ctx := context.Background()
+
+			if err := manager.Stop(ctx); err == nil || err.Error() != "node is not running" {
+				t.Errorf("Stop() error = %v, want node is not running", err)
+			}
+			if !processAlive(other.Process.Pid) {
+				t.Fatal("Stop() signalled a process that is not the node")
+			}
+			if _, err := os.Stat(pidFile); !os.IsNotExist(err) {
+				t.Errorf("expected stale PID file to be removed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProcessManagerWaitForHealthyExited(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Native.BinaryPath = fakeBinary(t, `echo "fatal: unknown flag"; exit 1`)
+
+	manager := NewProcessManager(cfg)
+	ctx := context.Background()
+
+	if err := manager.Start(ctx, cfg); err != nil {
+		t.Fatalf("Failed to start node: %v", err)
+	}
+
+	start := time.Now()
+	err := manager.WaitForHealthy(ctx, time.Minute)
+	if err == nil {
+		t.Fatal("WaitForHealthy() should fail when the node exits")
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("WaitForHealthy() took %v, want it to return once the node exits", time.Since(start))
+	}
+	for _, want := range []string{"node exited before becoming healthy", "exit status 1", "fatal: unknown flag"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("WaitForHealthy() error = %q, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestNodeVersion(t *testing.T) {
+	cfg := testConfig(t)
+	fakeNodeAPI(t, cfg)
+
+	v, err := NewAPIClient(cfg.Node.APIPort).NodeVersion(context.Background())
+	if err != nil {
+		t.Fatalf("NodeVersion() error = %v", err)
+	}
+	if v.Version != "v1.11.3" || v.RPCProtocolVersion != "35" || v.VMVersions["platform"] != "v1.11.3" {
+		t.Errorf("unexpected node version: %+v", v)
+	}
+}
+
+func TestCheckTested(t *testing.T) {
+	tests := []struct {
+		release string
+		wantErr bool
+	}{
+		{release: MinTestedAvalanchego},
+		{release: DefaultAvalanchegoVersion},
+		{release: "v1.11.14"},
+		{release: "v1.9.16", wantErr: true},
+		{release: MaxTestedAvalanchego, wantErr: true},
+		{release: "v2.0.0", wantErr: true},
+		{release: "latest", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.release, func(t *testing.T) {
+			if err := CheckTested(tt.release); (err != nil) != tt.wantErr {
+				t.Errorf("CheckTested(%s) error = %v, wantErr %v", tt.release, err, tt.wantErr)
+			}
+		})
+	}
+}
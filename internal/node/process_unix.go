@@ -0,0 +1,61 @@
+//go:build !windows
+
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// detachedProcAttr starts the node in its own session so it outlives the CLI
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+// processAlive reports whether a process with the given PID exists
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
+
+// terminateProcess asks the node to shut down gracefully
+func terminateProcess(proc *os.Process) error {
+	return proc.Signal(syscall.SIGTERM)
+}
+
+// processRuns reports whether the process with the given PID runs binary.
+// Scripts run through an interpreter name the script as an argument. Without
+// /proc the process cannot be inspected and is taken to match.
+func processRuns(pid int, binary string) bool {
+	if _, err := os.Stat("/proc/self/exe"); err != nil {
+		return true
+	}
+
+	want := []string{binary}
+	if resolved, err := filepath.EvalSymlinks(binary); err == nil {
+		want = append(want, resolved)
+	}
+
+	proc := filepath.Join("/proc", strconv.Itoa(pid))
+	if exe, err := os.Readlink(filepath.Join(proc, "exe")); err == nil && slices.Contains(want, exe) {
+		return true
+	}
+	cmdline, err := os.ReadFile(filepath.Join(proc, "cmdline"))
+	if err != nil {
+		return false
+	}
+	args := strings.Split(string(cmdline), "\x00")
+	for _, arg := range args[:min(len(args), 2)] {
+		if slices.Contains(want, arg) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+//go:build windows
+
+package node
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const processQueryLimitedInformation = 0x1000
+
+var (
+	kernel32                       = syscall.NewLazyDLL("kernel32.dll")
+	procGenerateConsoleCtrlEvent   = kernel32.NewProc("GenerateConsoleCtrlEvent")
+	procQueryFullProcessImageNameW = kernel32.NewProc("QueryFullProcessImageNameW")
+)
+
+// detachedProcAttr starts the node in its own process group so it outlives the CLI
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// processAlive reports whether a process with the given PID exists
+func processAlive(pid int) bool {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(h)
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	const stillActive = 259
+	return code == stillActive
+}
+
+// terminateProcess asks the node to shut down gracefully. Windows has no
+// SIGTERM: the node runs in its own process group, so it is sent a
+// CTRL_BREAK_EVENT, and killed if that cannot be delivered. Stop still kills
+// a node that ignores the event.
+func terminateProcess(proc *os.Process) error {
+	const ctrlBreakEvent = 1
+	if r, _, _ := procGenerateConsoleCtrlEvent.Call(ctrlBreakEvent, uintptr(proc.Pid)); r != 0 {
+		return nil
+	}
+	return proc.Kill()
+}
+
+// processRuns reports whether the process with the given PID runs binary
+func processRuns(pid int, binary string) bool {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(h)
+
+	buf := make([]uint16, syscall.MAX_LONG_PATH)
+	size := uint32(len(buf))
+	r, _, _ := procQueryFullProcessImageNameW.Call(uintptr(h), 0, uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)))
+	if r == 0 {
+		return false
+	}
+	return strings.EqualFold(syscall.UTF16ToString(buf[:size]), binary)
+}
@@ -0,0 +1,71 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// The avalanchego releases this Kinetic release has been tested against:
+// MinTestedAvalanchego up to, but not including, MaxTestedAvalanchego
+const (
+	MinTestedAvalanchego = "v1.10.0"
+	MaxTestedAvalanchego = "v1.12.0"
+)
+
+// NodeVersion is the version information a node reports
+type NodeVersion struct {
+	// Version is the release, e.g. v1.11.3
+	Version            string            `json:"version"`
+	DatabaseVersion    string            `json:"database_version"`
+	RPCProtocolVersion string            `json:"rpc_protocol_version"`
+	GitCommit          string            `json:"git_commit"`
+	VMVersions         map[string]string `json:"vm_versions,omitempty"`
+}
+
+// NodeVersion queries the node's version with info.getNodeVersion
+func (c *APIClient) NodeVersion(ctx context.Context) (*NodeVersion, error) {
+	var resp struct {
+		Version            string            `json:"version"`
+		DatabaseVersion    string            `json:"databaseVersion"`
+		RPCProtocolVersion string            `json:"rpcProtocolVersion"`
+		GitCommit          string            `json:"gitCommit"`
+		VMVersions         map[string]string `json:"vmVersions"`
+	}
+	if err := c.Call(ctx, "/ext/info", "info.getNodeVersion", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &NodeVersion{
+		Version:            releaseVersion(resp.Version),
+		DatabaseVersion:    resp.DatabaseVersion,
+		RPCProtocolVersion: resp.RPCProtocolVersion,
+		GitCommit:          resp.GitCommit,
+		VMVersions:         resp.VMVersions,
+	}, nil
+}
+
+// releaseVersion converts a node version such as avalanchego/1.11.3 to its
+// release, v1.11.3
+func releaseVersion(s string) string {
+	if i := strings.LastIndex(s, "/"); i >= 0 {
+		s = s[i+1:]
+	}
+	if s != "" && !strings.HasPrefix(s, "v") {
+		s = "v" + s
+	}
+	return s
+}
+
+// CheckTested returns an error describing the problem if an avalanchego
+// release is outside the range this Kinetic release has been tested against
+func CheckTested(release string) error {
+	if !semver.IsValid(release) {
+		return fmt.Errorf("avalanchego version %q is not a release version; Kinetic is tested with %s up to %s", release, MinTestedAvalanchego, MaxTestedAvalanchego)
+	}
+	if semver.Compare(release, MinTestedAvalanchego) < 0 || semver.Compare(release, MaxTestedAvalanchego) >= 0 {
+		return fmt.Errorf("avalanchego %s is outside the versions this Kinetic release has been tested against (%s up to %s)", release, MinTestedAvalanchego, MaxTestedAvalanchego)
+	}
+	return nil
+}
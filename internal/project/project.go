@@ -0,0 +1,226 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/system"
+)
+
+// Directories of the project layout
+const (
+	ContractsDir   = "contracts"
+	ScriptsDir     = "scripts"
+	TestDir        = "test"
+	DeploymentsDir = "deployments"
+	// LibDir holds vendored Solidity dependencies such as OpenZeppelin
+	LibDir = "lib"
+)
+
+// ErrNotFound is returned when a directory is not inside a project
+var ErrNotFound = errors.New("not inside a Kinetic project (no kinetic.yaml found); run 'kinetic init' to create one")
+
+// Project is a directory with a kinetic.yaml and the standard layout
+type Project struct {
+	Root string
+}
+
+// Find returns the project containing dir, found by walking up to the
+// nearest project config file
+func Find(dir string) (*Project, error) {
+	root := config.FindProjectRoot(dir)
+	if root == "" {
+		return nil, ErrNotFound
+	}
+	return &Project{Root: root}, nil
+}
+
+// Current returns the project containing the working directory
+func Current() (*Project, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return Find(cwd)
+}
+
+// Path joins path elements to the project root
+func (p *Project) Path(elem ...string) string {
+	return filepath.Join(append([]string{p.Root}, elem...)...)
+}
+
+// ContractsDir returns the directory contract sources are created in
+func (p *Project) ContractsDir() string {
+	return p.Path(ContractsDir)
+}
+
+// Kinds of deployment records
+const (
+	KindSubnet   = "subnets"
+	KindContract = "contracts"
+)
+
+// DeploymentPath returns the record of a deployment of a kind, such as
+// KindSubnet, on a network
+func (p *Project) DeploymentPath(network, kind, name string) string {
+	return p.Path(DeploymentsDir, network, kind, name+".json")
+}
+
+// WriteDeployment records a deployment on a network as indented JSON
+func (p *Project) WriteDeployment(network, kind, name string, v any) (string, error) {
+	path := p.DeploymentPath(network, kind, name)
+	if err := system.EnsureDir(filepath.Dir(path)); err != nil {
+		return "", fmt.Errorf("failed to create deployments directory: %w", err)
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode deployment: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write deployment: %w", err)
+	}
+	return path, nil
+}
+
+// InitOptions holds the options for project creation
+type InitOptions struct {
+	// Dir is the project root, created if needed
+	Dir string
+	// Force overwrites an existing kinetic.yaml and remappings.txt; other
+	// existing files are always kept
+	Force bool
+}
+
+// VendorOpenZeppelin vendors the OpenZeppelin release the contract templates
+// are written for; later majors drop Counters
+const VendorOpenZeppelin = "git submodule add -b v4.9.6 https://github.com/OpenZeppelin/openzeppelin-contracts " + LibDir + "/openzeppelin-contracts"
+
+// remappings map Solidity import prefixes to vendored dependencies
+const remappings = "@openzeppelin/contracts/=" + LibDir + "/openzeppelin-contracts/contracts/\n"
+
+// gitignore is written to new projects
+const gitignore = `# Build output
+out/
+cache/
+artifacts/
+
+# Local deployments; commit the other networks' records
+deployments/local/
+
+# Environment files may hold private keys
+.env
+`
+
+// projectConfig is the kinetic.yaml of new projects
+func projectConfig() string {
+	cfg := config.DefaultConfig()
+	return fmt.Sprintf(`# Kinetic project configuration
+#
+# Values here override the user config and are overridden by KINETIC_*
+# environment variables and flags. See 'kinetic config show --origin'.
+
+# Config file format version; upgraded automatically
+version: %d
+
+# Network targeted by deploy commands
+network:
+  # local, fuji or mainnet
+  name: local
+
+# Solidity compiler
+compiler:
+  version: %s
+  optimizer: %t
+  optimizer_runs: %d
+  evm_version: %s
+
+# Profiles for other networks, selected with --profile or 'kinetic profile use'
+profiles:
+  fuji:
+    network:
+      name: fuji
+`, config.CurrentVersion, cfg.Compiler.Version, cfg.Compiler.Optimizer, cfg.Compiler.OptimizerRuns, cfg.Compiler.EVMVersion)
+}
+
+// readme explains the layout of new projects
+const readme = `# %s
+
+A Kinetic project for Avalanche.
+
+- ` + "`contracts/`" + `: Solidity sources ('kinetic contract create' writes here)
+- ` + "`scripts/`" + `: deployment and maintenance scripts
+- ` + "`test/`" + `: contract tests
+- ` + "`deployments/`" + `: records of subnet and contract deployments, per network
+- ` + "`lib/`" + `: vendored dependencies, mapped in remappings.txt
+
+Vendor OpenZeppelin before compiling:
+
+    ` + VendorOpenZeppelin + `
+`
+
+// Init creates a project layout in opts.Dir, returning the paths it created
+// or overwrote. A directory already holding a project config is refused
+// unless opts.Force is set.
+func Init(opts InitOptions) (*Project, []string, error) {
+	root, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	if existing := config.ProjectConfigFile(root); !opts.Force {
+		if _, err := os.Stat(existing); err == nil {
+			return nil, nil, fmt.Errorf("%s already exists; use --force to overwrite the project files", existing)
+		}
+	}
+
+	p := &Project{Root: root}
+	var created []string
+	for _, dir := range []string{ContractsDir, ScriptsDir, TestDir, DeploymentsDir, LibDir} {
+		path := p.Path(dir)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := system.EnsureDir(path); err != nil {
+			return nil, nil, fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		created = append(created, path)
+	}
+
+	files := []struct {
+		name      string
+		contents  string
+		overwrite bool
+	}{
+		{config.ProjectConfigName + ".yaml", projectConfig(), opts.Force},
+		{"remappings.txt", remappings, opts.Force},
+		{".gitignore", gitignore, false},
+		{"README.md", fmt.Sprintf(readme, filepath.Base(root)), false},
+		{filepath.Join(DeploymentsDir, ".gitkeep"), "", false},
+		{filepath.Join(LibDir, ".gitkeep"), "", false},
+	}
+	for _, f := range files {
+		path := p.Path(f.name)
+		if _, err := os.Stat(path); err == nil && !f.overwrite {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(f.contents), 0644); err != nil {
+			return nil, nil, fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+		created = append(created, path)
+	}
+	return p, created, nil
+}
+
+// Rel returns path relative to the project root, or path itself if it is
+// outside the project
+func (p *Project) Rel(path string) string {
+	rel, err := filepath.Rel(p.Root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
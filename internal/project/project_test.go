@@ -0,0 +1,101 @@
+package project
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+)
+
+func TestInit(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "my-dapp")
+	p, created, err := Init(InitOptions{Dir: root})
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if p.Root != root {
+		t.Errorf("expected root %s, got %s", root, p.Root)
+	}
+	for _, name := range []string{ContractsDir, ScriptsDir, TestDir, DeploymentsDir, "kinetic.yaml", "remappings.txt", ".gitignore", "README.md"} {
+		if _, err := os.Stat(p.Path(name)); err != nil {
+			t.Errorf("expected %s: %v", name, err)
+		}
+	}
+	if len(created) != 11 {
+		t.Errorf("expected 11 created paths, got %v", created)
+	}
+
+	// The project config loads cleanly and defines the fuji profile
+	if err := config.ValidateFile(p.Path("kinetic.yaml")); err != nil {
+		t.Errorf("invalid kinetic.yaml: %v", err)
+	}
+	values, err := config.FileValues(p.Path("kinetic.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["compiler.version"] != "0.8.20" || values["profiles.fuji.network.name"] != "fuji" {
+		t.Errorf("unexpected kinetic.yaml values %v", values)
+	}
+	remap, _ := os.ReadFile(p.Path("remappings.txt"))
+	if !strings.HasPrefix(string(remap), "@openzeppelin/contracts/=lib/openzeppelin-contracts/contracts/") {
+		t.Errorf("unexpected remappings %q", remap)
+	}
+
+	if _, _, err := Init(InitOptions{Dir: root}); err == nil {
+		t.Error("expected error initializing an existing project")
+	}
+
+	// Force rewrites the project config but keeps user files
+	os.WriteFile(p.Path("README.md"), []byte("mine"), 0644)
+	if _, created, err = Init(InitOptions{Dir: root, Force: true}); err != nil {
+		t.Fatalf("Init() with force error = %v", err)
+	}
+	if len(created) != 2 {
+		t.Errorf("expected kinetic.yaml and remappings.txt to be rewritten, got %v", created)
+	}
+	if data, _ := os.ReadFile(p.Path("README.md")); string(data) != "mine" {
+		t.Errorf("expected README.md to be kept, got %q", data)
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	if _, _, err := Init(InitOptions{Dir: root}); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(root, ContractsDir, "tokens")
+	os.MkdirAll(nested, 0755)
+
+	p, err := Find(nested)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if p.Root != root {
+		t.Errorf("expected root %s, got %s", root, p.Root)
+	}
+	if rel := p.Rel(filepath.Join(nested, "Token.sol")); rel != filepath.Join(ContractsDir, "tokens", "Token.sol") {
+		t.Errorf("unexpected relative path %s", rel)
+	}
+
+	if _, err := Find(t.TempDir()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound outside a project, got %v", err)
+	}
+}
+
+func TestWriteDeployment(t *testing.T) {
+	p := &Project{Root: t.TempDir()}
+	path, err := p.WriteDeployment("local", KindSubnet, "mysubnet", map[string]string{"subnet_id": "abc"})
+	if err != nil {
+		t.Fatalf("WriteDeployment() error = %v", err)
+	}
+	if want := filepath.Join(p.Root, "deployments", "local", "subnets", "mysubnet.json"); path != want {
+		t.Errorf("expected %s, got %s", want, path)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"subnet_id": "abc"`) {
+		t.Errorf("unexpected record %s", data)
+	}
+}
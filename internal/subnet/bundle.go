@@ -0,0 +1,286 @@
+package subnet
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BundleSchemaVersion is the version of the export bundle format written by
+// this build. Bundles from newer builds are rejected rather than misread.
+const BundleSchemaVersion = 1
+
+// chainConfigFile holds a subnet's chain config in its store directory
+const chainConfigFile = "chain_config.json"
+
+// Bundle is a portable, network-independent subnet definition used to share
+// a subnet between machines
+type Bundle struct {
+	SchemaVersion int    `json:"schema_version"`
+	Name          string `json:"name"`
+	VM            string `json:"vm"`
+	VMID          string `json:"vm_id"`
+	ChainID       uint64 `json:"chain_id,omitempty"`
+	TokenName     string `json:"token_name,omitempty"`
+
+	// VM reference for custom VMs; the binary itself is not bundled
+	VMName     string `json:"vm_name,omitempty"`
+	VMModule   string `json:"vm_module,omitempty"`
+	VMChecksum string `json:"vm_sha256,omitempty"`
+
+	// Genesis holds genesis files in Kinetic's indented JSON form inline;
+	// anything else is carried base64 encoded in GenesisBase64 so the bytes,
+	// and therefore the chain, are recreated identically
+	Genesis       json.RawMessage `json:"genesis,omitempty"`
+	GenesisBase64 string          `json:"genesis_base64,omitempty"`
+
+	Upgrades    *UpgradeConfig  `json:"upgrades,omitempty"`
+	ChainConfig json.RawMessage `json:"chain_config,omitempty"`
+}
+
+// ChainConfig returns a subnet's chain config, or nil if none was set
+func (s *Store) ChainConfig(name string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(s.Dir(name), chainConfigFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chain config: %w", err)
+	}
+	return data, nil
+}
+
+// SaveChainConfig writes a subnet's chain config
+func (s *Store) SaveChainConfig(name string, data []byte) error {
+	if err := os.WriteFile(filepath.Join(s.Dir(name), chainConfigFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write chain config: %w", err)
+	}
+	return nil
+}
+
+// Export builds the portable bundle of a stored subnet. Deployments and
+// other machine-specific state are left out.
+func Export(store *Store, name string) (*Bundle, error) {
+	sn, err := store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	genesis, err := store.Genesis(name)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{
+		SchemaVersion: BundleSchemaVersion,
+		Name:          sn.Name,
+		VM:            sn.VM,
+		VMID:          sn.VMID,
+		ChainID:       sn.ChainID,
+		TokenName:     sn.TokenName,
+		VMName:        sn.VMName,
+		VMModule:      sn.VMModule,
+		VMChecksum:    sn.VMChecksum,
+	}
+	if canonical, ok := indentJSON(genesis); ok && bytes.Equal(canonical, genesis) {
+		b.Genesis = json.RawMessage(genesis)
+	} else {
+		b.GenesisBase64 = base64.StdEncoding.EncodeToString(genesis)
+	}
+
+	upgrades, err := store.Upgrades(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(upgrades.PrecompileUpgrades) > 0 {
+		b.Upgrades = upgrades
+	}
+
+	chainConfig, err := store.ChainConfig(name)
+	if err != nil {
+		return nil, err
+	}
+	if chainConfig != nil {
+		b.ChainConfig = json.RawMessage(chainConfig)
+	}
+	return b, nil
+}
+
+// indentJSON returns JSON in the two-space indented form genesis files are
+// written in
+func indentJSON(data []byte) ([]byte, bool) {
+	var buf bytes.Buffer
+	if len(data) == 0 || json.Indent(&buf, data, "", "  ") != nil {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+// ParseBundle decodes a bundle and checks its schema version
+func ParseBundle(data []byte) (*Bundle, error) {
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse subnet bundle: %w", err)
+	}
+	if b.SchemaVersion <= 0 {
+		return nil, fmt.Errorf("subnet bundle has no schema version")
+	}
+	if b.SchemaVersion > BundleSchemaVersion {
+		return nil, fmt.Errorf("subnet bundle schema version %d is newer than the supported version %d; upgrade kinetic", b.SchemaVersion, BundleSchemaVersion)
+	}
+	return &b, nil
+}
+
+// ImportOptions holds the options for importing a bundle
+type ImportOptions struct {
+	// Name overrides the bundle's subnet name
+	Name string
+	// VMBinary is the custom VM binary; required unless the bundle's VM can
+	// be rebuilt from its module
+	VMBinary string
+	// GOOS is the target OS when rebuilding a custom VM
+	GOOS string
+}
+
+// Import recreates a subnet from a bundle
+func Import(store *Store, b *Bundle, opts ImportOptions) (*Subnet, error) {
+	name := b.Name
+	if opts.Name != "" {
+		name = opts.Name
+	}
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	if store.Exists(name) {
+		return nil, fmt.Errorf("subnet %s already exists", name)
+	}
+
+	genesis, _ := indentJSON(b.Genesis)
+	if b.GenesisBase64 != "" {
+		var err error
+		if genesis, err = base64.StdEncoding.DecodeString(b.GenesisBase64); err != nil {
+			return nil, fmt.Errorf("invalid bundle genesis: %w", err)
+		}
+	}
+	if len(genesis) == 0 {
+		return nil, fmt.Errorf("subnet bundle has no genesis")
+	}
+
+	// The genesis defines the chain, so its chain ID is checked the way
+	// create checks it rather than trusting the bundle's copy
+	chainID := evmChainID(genesis)
+	if b.ChainID != 0 && b.ChainID != chainID {
+		return nil, fmt.Errorf("bundle chain ID %d does not match the genesis chain ID %d", b.ChainID, chainID)
+	}
+	switch b.VM {
+	case VMSubnetEVM:
+		var g Genesis
+		if err := json.Unmarshal(genesis, &g); err != nil {
+			return nil, fmt.Errorf("invalid subnet-evm genesis: %w", err)
+		}
+		if vmID, _ := VMID(subnetEVMName); b.VMID != vmID {
+			return nil, fmt.Errorf("bundle VM ID %s does not match subnet-evm", b.VMID)
+		}
+		if chainID == 0 {
+			return nil, fmt.Errorf("subnet-evm genesis has no chain ID")
+		}
+		if name, ok := KnownChainIDs[chainID]; ok {
+			return nil, fmt.Errorf("chain ID %d is already used by %s", chainID, name)
+		}
+	case VMCustom:
+		vmID, err := VMID(b.VMName)
+		if err != nil || b.VMID != vmID {
+			return nil, fmt.Errorf("bundle VM ID %s does not match VM name %q", b.VMID, b.VMName)
+		}
+		if err := checkVMID(store, b.VMName, vmID); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported VM type %q", b.VM)
+	}
+	if chainID != 0 {
+		if err := checkChainID(store, chainID); err != nil {
+			return nil, err
+		}
+	}
+	if b.Upgrades != nil {
+		if err := ValidateUpgrades(genesis, b.Upgrades); err != nil {
+			return nil, fmt.Errorf("invalid bundle upgrades: %w", err)
+		}
+	}
+	if b.ChainConfig != nil && !json.Valid(b.ChainConfig) {
+		return nil, fmt.Errorf("invalid bundle chain config")
+	}
+
+	sn := &Subnet{
+		Name:      name,
+		VM:        b.VM,
+		VMID:      b.VMID,
+		ChainID:   chainID,
+		TokenName: b.TokenName,
+		CreatedAt: time.Now().UTC(),
+	}
+	if b.VM == VMCustom {
+		sn.VMName, sn.VMModule, sn.VMChecksum = b.VMName, b.VMModule, b.VMChecksum
+	}
+	if err := store.Save(sn, genesis); err != nil {
+		return nil, err
+	}
+	if b.VM == VMCustom {
+		if err := importCustomVM(store, sn, opts); err != nil {
+			store.Delete(name)
+			return nil, err
+		}
+	}
+
+	if b.Upgrades != nil {
+		if err := store.SaveUpgrades(name, b.Upgrades); err != nil {
+			return nil, err
+		}
+	}
+	if b.ChainConfig != nil {
+		chainConfig, _ := indentJSON(b.ChainConfig)
+		if err := store.SaveChainConfig(name, chainConfig); err != nil {
+			return nil, err
+		}
+	}
+	return sn, nil
+}
+
+// importCustomVM installs the VM binary of an imported custom subnet from
+// the given binary or by rebuilding its module, verifying the checksum of
+// binaries so the subnet runs the same VM as on the exporting machine
+func importCustomVM(store *Store, sn *Subnet, opts ImportOptions) error {
+	target := store.VMBinaryPath(sn.Name)
+	switch {
+	case opts.VMBinary != "":
+		if sn.VMChecksum != "" {
+			sum, err := fileChecksum(opts.VMBinary)
+			if err != nil {
+				return err
+			}
+			if sum != sn.VMChecksum {
+				return fmt.Errorf("VM binary checksum %s does not match the bundle's %s", sum, sn.VMChecksum)
+			}
+		}
+		return copyExecutable(opts.VMBinary, target)
+	case sn.VMModule != "":
+		// Builds are not byte-for-byte reproducible across toolchains, so
+		// the rebuilt binary's checksum replaces the exported one
+		if err := buildVM(sn.VMModule, opts.GOOS, target); err != nil {
+			return err
+		}
+		sum, err := fileChecksum(target)
+		if err != nil {
+			return err
+		}
+		sn.VMChecksum = sum
+		return store.Update(sn)
+	default:
+		return fmt.Errorf("custom VM %s must be provided with a VM binary", sn.VMName)
+	}
+}
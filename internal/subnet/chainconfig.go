@@ -0,0 +1,256 @@
+package subnet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/system"
+)
+
+// nodeChainConfigFile is the chain config file avalanchego reads from
+// <chain-config-dir>/<blockchainID>/
+const nodeChainConfigFile = "config.json"
+
+// Chain config value types
+const (
+	ConfigBool     = "bool"
+	ConfigString   = "string"
+	ConfigUint     = "uint"
+	ConfigFloat    = "float"
+	ConfigDuration = "duration"
+	ConfigList     = "list"
+	ConfigAddress  = "address"
+)
+
+// ConfigKey describes a known chain config key
+type ConfigKey struct {
+	Type        string
+	Description string
+	// Values restricts string keys to a set of values
+	Values []string
+}
+
+// SubnetEVMConfigKeys are the subnet-evm chain config keys Kinetic validates
+var SubnetEVMConfigKeys = map[string]ConfigKey{
+	"eth-apis":                       {Type: ConfigList, Description: "Enabled RPC API namespaces, e.g. eth,eth-filter,net,web3,internal-eth,internal-blockchain,internal-transaction,debug-tracer"},
+	"admin-api-enabled":              {Type: ConfigBool, Description: "Enable the admin API"},
+	"warp-api-enabled":               {Type: ConfigBool, Description: "Enable the warp API"},
+	"pruning-enabled":                {Type: ConfigBool, Description: "Prune old state; disable for an archive node"},
+	"commit-interval":                {Type: ConfigUint, Description: "Blocks between trie commits to disk"},
+	"accepted-cache-size":            {Type: ConfigUint, Description: "Number of accepted blocks kept in memory"},
+	"state-sync-enabled":             {Type: ConfigBool, Description: "Bootstrap with state sync"},
+	"offline-pruning-enabled":        {Type: ConfigBool, Description: "Run offline pruning on startup"},
+	"offline-pruning-data-directory": {Type: ConfigString, Description: "Directory for offline pruning data"},
+	"allow-unfinalized-queries":      {Type: ConfigBool, Description: "Allow queries of unfinalized blocks"},
+	"allow-unprotected-txs":          {Type: ConfigBool, Description: "Allow transactions without EIP-155 replay protection"},
+	"local-txs-enabled":              {Type: ConfigBool, Description: "Treat transactions from local accounts as local"},
+	"api-max-duration":               {Type: ConfigDuration, Description: "Maximum duration of an API call, e.g. 30s"},
+	"api-max-blocks-per-request":     {Type: ConfigUint, Description: "Maximum blocks served per getLogs request"},
+	"rpc-gas-cap":                    {Type: ConfigUint, Description: "Gas cap for eth_call and eth_estimateGas"},
+	"rpc-tx-fee-cap":                 {Type: ConfigFloat, Description: "Transaction fee cap for send methods, in AVAX"},
+	"tx-pool-price-limit":            {Type: ConfigUint, Description: "Minimum gas price for the transaction pool"},
+	"tx-pool-global-slots":           {Type: ConfigUint, Description: "Executable transaction slots for all accounts"},
+	"tx-pool-global-queue":           {Type: ConfigUint, Description: "Non-executable transaction slots for all accounts"},
+	"metrics-expensive-enabled":      {Type: ConfigBool, Description: "Enable expensive metrics"},
+	"continuous-profiler-dir":        {Type: ConfigString, Description: "Directory for continuous profiles"},
+	"skip-upgrade-check":             {Type: ConfigBool, Description: "Skip checking upgrades against the chain's history"},
+	"feeRecipient":                   {Type: ConfigAddress, Description: "Fee recipient when the chain allows fee recipients"},
+	"log-level":                      {Type: ConfigString, Description: "Chain log level", Values: []string{"trace", "debug", "info", "warn", "error", "crit"}},
+	"log-json-format":                {Type: ConfigBool, Description: "Log in JSON format"},
+}
+
+// ParseConfigValue parses a chain config value given on the command line.
+// subnet-evm keys are checked against the schema; unknown keys are rejected
+// unless force is set. Values for unknown keys and custom VMs are read as
+// JSON, falling back to a plain string.
+func ParseConfigValue(vm, key, value string, force bool) (any, error) {
+	schema, known := SubnetEVMConfigKeys[key]
+	if vm != VMSubnetEVM || !known {
+		if vm == VMSubnetEVM && !force {
+			return nil, fmt.Errorf("unknown subnet-evm config key %q; use --force to set it anyway", key)
+		}
+		var v any
+		if err := json.Unmarshal([]byte(value), &v); err == nil {
+			return v, nil
+		}
+		return value, nil
+	}
+
+	switch schema.Type {
+	case ConfigBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", key)
+		}
+		return b, nil
+	case ConfigUint:
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a non-negative integer", key)
+		}
+		return n, nil
+	case ConfigFloat:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < 0 {
+			return nil, fmt.Errorf("%s must be a non-negative number", key)
+		}
+		return f, nil
+	case ConfigDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("%s must be a duration such as 30s", key)
+		}
+		return value, nil
+	case ConfigList:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	case ConfigAddress:
+		if err := ValidateAddress(value); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		return value, nil
+	default:
+		if len(schema.Values) > 0 && !slices.Contains(schema.Values, value) {
+			return nil, fmt.Errorf("%s must be one of %s", key, strings.Join(schema.Values, ", "))
+		}
+		return value, nil
+	}
+}
+
+// LoadChainConfig returns a subnet's chain config as key/value pairs
+func LoadChainConfig(store *Store, name string) (map[string]any, error) {
+	values := make(map[string]any)
+	data, err := store.ChainConfig(name)
+	if err != nil || data == nil {
+		return values, err
+	}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse chain config: %w", err)
+	}
+	return values, nil
+}
+
+// UpdateChainConfig sets and removes chain config keys of a subnet, reporting
+// whether the stored config changed
+func UpdateChainConfig(store *Store, name string, set map[string]any, unset []string) (map[string]any, bool, error) {
+	if _, err := store.Get(name); err != nil {
+		return nil, false, err
+	}
+	values, err := LoadChainConfig(store, name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	changed := false
+	for key, value := range set {
+		if old, ok := values[key]; !ok || !reflect.DeepEqual(normalizeJSON(old), normalizeJSON(value)) {
+			values[key] = value
+			changed = true
+		}
+	}
+	for _, key := range unset {
+		if _, ok := values[key]; ok {
+			delete(values, key)
+			changed = true
+		}
+	}
+	if !changed {
+		return values, false, nil
+	}
+
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode chain config: %w", err)
+	}
+	if err := store.SaveChainConfig(name, data); err != nil {
+		return nil, false, err
+	}
+	return values, true, nil
+}
+
+// ConfigKeys returns the sorted keys of a chain config
+func ConfigKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normalizeJSON converts a value to its decoded JSON form for comparison
+func normalizeJSON(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	json.Unmarshal(data, &out)
+	return out
+}
+
+// InstallChainConfig writes a locally deployed subnet's chain config into the
+// node's chain config directory, returning the installed path. The node reads
+// it when the chain next starts.
+func InstallChainConfig(store *Store, name string, cfg *config.Config) (string, error) {
+	_, d, err := localDeployment(store, name)
+	if err != nil {
+		return "", err
+	}
+	return installChainFiles(store, name, cfg, d.BlockchainID, nodeChainConfigFile)
+}
+
+// installChainFiles copies the named per-chain files of a subnet into the
+// node's directory for the blockchain, returning the path of the last one.
+// Files the subnet does not have are removed from the node.
+func installChainFiles(store *Store, name string, cfg *config.Config, blockchainID string, files ...string) (string, error) {
+	var path string
+	for _, file := range files {
+		var data []byte
+		var err error
+		switch file {
+		case nodeChainConfigFile:
+			data, err = store.ChainConfig(name)
+		case upgradeFile:
+			data, err = os.ReadFile(store.UpgradePath(name))
+			if os.IsNotExist(err) {
+				data, err = nil, nil
+			}
+		default:
+			return "", fmt.Errorf("unknown chain file %s", file)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if path, err = ChainConfigPath(cfg, blockchainID, file); err != nil {
+			return "", err
+		}
+		if data == nil {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return "", fmt.Errorf("failed to remove %s: %w", file, err)
+			}
+			continue
+		}
+		if err := system.EnsureDir(filepath.Dir(path)); err != nil {
+			return "", fmt.Errorf("failed to create chain config directory: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return "", fmt.Errorf("failed to install %s: %w", file, err)
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,257 @@
+package subnet
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/kinetic-dev/kinetic/internal/system"
+)
+
+// VMCustom is a user-supplied virtual machine
+const VMCustom = "custom"
+
+// vmBinaryFile is the name of a custom VM binary in the subnet's directory
+const vmBinaryFile = "vm"
+
+// CustomVMOptions describes a custom VM and its genesis
+type CustomVMOptions struct {
+	// Name is the VM name the VM ID is derived from; defaults to the subnet name
+	Name string
+	// BinaryPath is a prebuilt VM plugin binary
+	BinaryPath string
+	// Module is a Go module path (module/pkg@version) or local directory to
+	// build the VM from when no binary is given
+	Module string
+	// GOOS is the target OS when building; the node's OS, so linux for
+	// container runtimes
+	GOOS string
+	// GenesisPath is the genesis file, used verbatim
+	GenesisPath string
+}
+
+// Validate checks the custom VM options
+func (o CustomVMOptions) Validate() error {
+	if (o.BinaryPath == "") == (o.Module == "") {
+		return fmt.Errorf("a custom VM requires exactly one of a binary path or a Go module to build")
+	}
+	if o.GenesisPath == "" {
+		return fmt.Errorf("a custom VM requires a genesis file")
+	}
+	if o.Name == subnetEVMName {
+		return fmt.Errorf("VM name %q is reserved for subnet-evm", o.Name)
+	}
+	if o.BinaryPath != "" {
+		info, err := os.Stat(o.BinaryPath)
+		if err != nil {
+			return fmt.Errorf("invalid VM binary: %w", err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("VM binary %s is a directory", o.BinaryPath)
+		}
+	}
+	return nil
+}
+
+// VMBinaryPath returns the path of a custom VM's binary in the store
+func (s *Store) VMBinaryPath(name string) string {
+	return filepath.Join(s.Dir(name), vmBinaryFile)
+}
+
+// createCustom registers a custom VM subnet: the genesis is stored verbatim
+// and the VM binary is copied or built into the subnet's directory
+func createCustom(store *Store, opts CreateOptions) (*Subnet, error) {
+	vm := opts.CustomVM
+	if vm.Name == "" {
+		vm.Name = opts.Name
+	}
+	if err := vm.Validate(); err != nil {
+		return nil, err
+	}
+
+	vmID, err := VMID(vm.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVMID(store, vm.Name, vmID); err != nil {
+		return nil, err
+	}
+
+	genesis, err := os.ReadFile(vm.GenesisPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis: %w", err)
+	}
+	if len(bytes.TrimSpace(genesis)) == 0 {
+		return nil, fmt.Errorf("genesis file %s is empty", vm.GenesisPath)
+	}
+
+	sn := &Subnet{
+		Name:      opts.Name,
+		VM:        VMCustom,
+		VMName:    vm.Name,
+		VMID:      vmID,
+		TokenName: opts.TokenName,
+		CreatedAt: time.Now().UTC(),
+		// EVM-based custom VMs still get their chain ID recorded for display
+		ChainID: evmChainID(genesis),
+	}
+
+	if err := store.Save(sn, genesis); err != nil {
+		return nil, err
+	}
+
+	target := store.VMBinaryPath(sn.Name)
+	if vm.BinaryPath != "" {
+		err = copyExecutable(vm.BinaryPath, target)
+	} else {
+		err = buildVM(vm.Module, vm.GOOS, target)
+	}
+	if err != nil {
+		store.Delete(sn.Name)
+		return nil, err
+	}
+
+	// Record how the VM was obtained so exported bundles can reproduce it
+	if vm.Module != "" {
+		sn.VMModule = vm.Module
+	}
+	if sn.VMChecksum, err = fileChecksum(target); err != nil {
+		store.Delete(sn.Name)
+		return nil, err
+	}
+	if err := store.Update(sn); err != nil {
+		return nil, err
+	}
+	return sn, nil
+}
+
+// checkVMID rejects a custom VM whose ID is already used by another subnet.
+// Plugins are keyed by VM ID, so two subnets with the same VM name would
+// overwrite each other's binary.
+func checkVMID(store *Store, vmName, vmID string) error {
+	existing, err := store.List()
+	if err != nil {
+		return err
+	}
+	for _, sn := range existing {
+		if sn.VM == VMCustom && sn.VMID == vmID {
+			return fmt.Errorf("VM name %s is already used by subnet %s", vmName, sn.Name)
+		}
+	}
+	return nil
+}
+
+// evmChainID returns the chain ID of an EVM genesis, or 0 if the genesis has
+// none
+func evmChainID(genesis []byte) uint64 {
+	var evm struct {
+		Config struct {
+			ChainID uint64 `json:"chainId"`
+		} `json:"config"`
+	}
+	if json.Unmarshal(genesis, &evm) != nil {
+		return 0
+	}
+	return evm.Config.ChainID
+}
+
+// fileChecksum returns the hex sha256 of a file
+func fileChecksum(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// buildVM builds a VM plugin from a local directory or a remote module
+func buildVM(module, goos, target string) error {
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	env := append(os.Environ(), "GOOS="+goos, "GOARCH="+runtime.GOARCH, "CGO_ENABLED=0")
+
+	if info, err := os.Stat(module); err == nil && info.IsDir() {
+		abs, err := filepath.Abs(target)
+		if err != nil {
+			return err
+		}
+		cmd := exec.Command("go", "build", "-o", abs, ".")
+		cmd.Dir = module
+		return runBuild(cmd, env, module)
+	}
+
+	if !strings.Contains(module, "@") {
+		module += "@latest"
+	}
+
+	// go install refuses GOBIN for cross-compiles, so install into a
+	// throwaway GOPATH that shares the regular module cache
+	modCache, err := exec.Command("go", "env", "GOMODCACHE").Output()
+	if err != nil {
+		return fmt.Errorf("failed to locate Go module cache: %w", err)
+	}
+	gopath, err := os.MkdirTemp("", "kinetic-vm-")
+	if err != nil {
+		return fmt.Errorf("failed to create build directory: %w", err)
+	}
+	defer os.RemoveAll(gopath)
+
+	env = append(env, "GOPATH="+gopath, "GOMODCACHE="+strings.TrimSpace(string(modCache)), "GOBIN=")
+	if err := runBuild(exec.Command("go", "install", module), env, module); err != nil {
+		return err
+	}
+
+	// The binary is in bin/ or, when cross-compiled, bin/<goos>_<goarch>/
+	var built []string
+	filepath.WalkDir(filepath.Join(gopath, "bin"), func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			built = append(built, path)
+		}
+		return nil
+	})
+	if len(built) != 1 {
+		return fmt.Errorf("expected building %s to produce one binary, got %d", module, len(built))
+	}
+	return copyExecutable(built[0], target)
+}
+
+// runBuild runs a go build command, including its output in errors
+func runBuild(cmd *exec.Cmd, env []string, module string) error {
+	cmd.Env = env
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to build VM %s: %w: %s", module, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// copyExecutable copies a binary, keeping it executable
+func copyExecutable(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open VM binary: %w", err)
+	}
+	defer in.Close()
+
+	if err := system.EnsureDir(filepath.Dir(dst)); err != nil {
+		return fmt.Errorf("failed to create plugin directory: %w", err)
+	}
+	return writePlugin(in, dst)
+}
+
+// installCustomVM copies a custom subnet's VM binary into the node's plugin
+// directory under its VM ID
+func installCustomVM(store *Store, sn *Subnet, pluginDir string) error {
+	return copyExecutable(store.VMBinaryPath(sn.Name), filepath.Join(pluginDir, sn.VMID))
+}
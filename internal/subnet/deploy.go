@@ -0,0 +1,306 @@
+package subnet
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/node"
+	"github.com/kinetic-dev/kinetic/internal/system"
+)
+
+// DefaultSubnetEVMVersion is the subnet-evm release installed when none is given
+const DefaultSubnetEVMVersion = "v0.6.3"
+
+// DeployOptions holds the options for deploying to the local node
+type DeployOptions struct {
+	// Config is the node config; PluginDir, ChainConfigDir and TrackSubnets
+	// are updated in place and should be saved by the caller
+	Config  *config.Config
+	Manager node.Manager
+	// VMVersion is the subnet-evm release to install as the node plugin
+	VMVersion string
+	Out       io.Writer
+	// Timeout bounds waiting for the node and the new chain after restart
+	Timeout time.Duration
+}
+
+// DeployLocal creates the subnet and its blockchain on the local node using
+// the prefunded ewoq key, then restarts the node tracking the new subnet
+func DeployLocal(ctx context.Context, store *Store, name string, opts DeployOptions) (*Deployment, error) {
+	sn, err := store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if sn.Deployment("local") != nil {
+		return nil, fmt.Errorf("subnet %s is already deployed to local", name)
+	}
+	if opts.VMVersion == "" {
+		opts.VMVersion = DefaultSubnetEVMVersion
+	}
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 2 * time.Minute
+	}
+	cfg := opts.Config
+
+	status, err := opts.Manager.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check node status: %w", err)
+	}
+	if !status.IsRunning {
+		return nil, fmt.Errorf("local node is not running; start it with 'kinetic node start'")
+	}
+
+	genesis, err := store.Genesis(name)
+	if err != nil {
+		return nil, err
+	}
+	vmID, err := ParseID(sn.VMID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VM ID for subnet %s: %w", name, err)
+	}
+
+	if err := cfg.SetDefaultDirs(); err != nil {
+		return nil, err
+	}
+	if sn.VM == VMCustom {
+		// Always refresh so a rebuilt VM binary is picked up
+		if err := installCustomVM(store, sn, cfg.Node.PluginDir); err != nil {
+			return nil, err
+		}
+	} else if err := ensureSubnetEVMPlugin(ctx, cfg.Node.PluginDir, sn.VMID, opts.VMVersion, PluginOS(cfg), opts.Out); err != nil {
+		return nil, err
+	}
+
+	api := node.NewAPIClient(cfg.Node.APIPort)
+	wallet, err := NewWallet(api, EwoqKey(), uint32(cfg.Node.NetworkID))
+	if err != nil {
+		return nil, err
+	}
+
+	// A failed deployment records what it created on-chain, so a retry
+	// picks up from there instead of creating a second subnet
+	pending := sn.PendingDeployment("local")
+	if pending == nil {
+		pending = &Deployment{}
+	}
+	var subnetID ID
+	if pending.SubnetID != "" {
+		if subnetID, err = ParseID(pending.SubnetID); err != nil {
+			return nil, fmt.Errorf("invalid pending subnet ID for subnet %s: %w", name, err)
+		}
+		fmt.Fprintf(opts.Out, "Resuming deployment of subnet %s\n", subnetID)
+	} else {
+		fmt.Fprintln(opts.Out, "Creating subnet...")
+		if subnetID, err = wallet.CreateSubnet(ctx); err != nil {
+			return nil, fmt.Errorf("failed to create subnet: %w", err)
+		}
+		fmt.Fprintf(opts.Out, "Subnet ID: %s\n", subnetID)
+		pending.SubnetID = subnetID.String()
+		if err := savePending(store, sn, pending); err != nil {
+			return nil, err
+		}
+	}
+
+	var blockchainID ID
+	if pending.BlockchainID != "" {
+		if blockchainID, err = ParseID(pending.BlockchainID); err != nil {
+			return nil, fmt.Errorf("invalid pending blockchain ID for subnet %s: %w", name, err)
+		}
+	} else {
+		fmt.Fprintln(opts.Out, "Creating blockchain...")
+		if blockchainID, err = wallet.CreateChain(ctx, subnetID, name, vmID, genesis); err != nil {
+			return nil, fmt.Errorf("failed to create blockchain: %w", err)
+		}
+		fmt.Fprintf(opts.Out, "Blockchain ID: %s\n", blockchainID)
+		pending.BlockchainID = blockchainID.String()
+		if err := savePending(store, sn, pending); err != nil {
+			return nil, err
+		}
+	}
+
+	// The chain reads its config and upgrades when it first starts
+	if _, err := installChainFiles(store, name, cfg, blockchainID.String(), nodeChainConfigFile, upgradeFile); err != nil {
+		return nil, err
+	}
+
+	// The node only runs chains of subnets it tracks, which requires a restart
+	if !slices.Contains(cfg.Node.TrackSubnets, subnetID.String()) {
+		cfg.Node.TrackSubnets = append(cfg.Node.TrackSubnets, subnetID.String())
+	}
+	fmt.Fprintln(opts.Out, "Restarting node to track the subnet...")
+	if err := opts.Manager.Restart(ctx); err != nil {
+		return nil, fmt.Errorf("failed to restart node: %w", err)
+	}
+	if err := opts.Manager.WaitForHealthy(ctx, opts.Timeout); err != nil {
+		return nil, fmt.Errorf("node failed to become healthy: %w", err)
+	}
+
+	fmt.Fprintln(opts.Out, "Waiting for the blockchain to bootstrap...")
+	if err := waitForChain(ctx, api, blockchainID.String(), opts.Timeout); err != nil {
+		return nil, err
+	}
+
+	// Without sybil protection the local nodes validate every tracked subnet
+	validators, err := LocalNodeIDs(ctx, api)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Deployment{
+		SubnetID:     subnetID.String(),
+		BlockchainID: blockchainID.String(),
+		Validators:   validators,
+		RPCURL:       fmt.Sprintf("http://localhost:%d/ext/bc/%s/rpc", cfg.Node.APIPort, blockchainID),
+		DeployedAt:   time.Now().UTC(),
+	}
+	sn.SetDeployment("local", d)
+	if err := store.Update(sn); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// savePending records the progress of a local deployment
+func savePending(store *Store, sn *Subnet, pending *Deployment) error {
+	sn.SetPendingDeployment("local", pending)
+	if err := store.Update(sn); err != nil {
+		return fmt.Errorf("failed to record the deployment of subnet %s (subnet ID %s): %w", sn.Name, pending.SubnetID, err)
+	}
+	return nil
+}
+
+// PluginOS returns the OS VM plugins must be built for to run on the node
+func PluginOS(cfg *config.Config) string {
+	if cfg.Node.Runtime == "native" {
+		return runtime.GOOS
+	}
+	return "linux"
+}
+
+// waitForChain polls until the node reports the chain as bootstrapped. Errors
+// are expected while the chain is still being created after a restart.
+func waitForChain(ctx context.Context, api *node.APIClient, chain string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		var result struct {
+			IsBootstrapped bool `json:"isBootstrapped"`
+		}
+		err := api.Call(ctx, "/ext/info", "info.isBootstrapped", map[string]string{"chain": chain}, &result)
+		if err == nil && result.IsBootstrapped {
+			return nil
+		}
+		if err != nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("timeout waiting for blockchain %s to bootstrap: %w", chain, lastErr)
+			}
+			return fmt.Errorf("timeout waiting for blockchain %s to bootstrap", chain)
+		case <-ticker.C:
+		}
+	}
+}
+
+// ensureSubnetEVMPlugin installs the subnet-evm binary as pluginDir/<vmID>
+// unless it is already present
+func ensureSubnetEVMPlugin(ctx context.Context, pluginDir, vmID, version, goos string, out io.Writer) error {
+	target := filepath.Join(pluginDir, vmID)
+	if _, err := os.Stat(target); err == nil {
+		return nil
+	}
+
+	if err := system.EnsureDir(pluginDir); err != nil {
+		return fmt.Errorf("failed to create plugin directory: %w", err)
+	}
+
+	fmt.Fprintf(out, "Downloading subnet-evm %s...\n", version)
+	url := fmt.Sprintf(
+		"https://github.com/ava-labs/subnet-evm/releases/download/%s/subnet-evm_%s_%s_%s.tar.gz",
+		version, strings.TrimPrefix(version, "v"), goos, runtime.GOARCH,
+	)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create download request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download subnet-evm: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download subnet-evm %s: %s", version, resp.Status)
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read subnet-evm archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read subnet-evm archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || filepath.Base(hdr.Name) != "subnet-evm" {
+			continue
+		}
+		return writePlugin(tr, target)
+	}
+
+	return fmt.Errorf("subnet-evm binary missing from release archive")
+}
+
+// writePlugin writes a plugin binary atomically so a partial download is
+// never picked up by the node
+func writePlugin(r io.Reader, target string) error {
+	tmp := target + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to write plugin: %w", err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write plugin: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write plugin: %w", err)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		return fmt.Errorf("failed to install plugin: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package subnet
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/kinetic-dev/kinetic/internal/node"
+	"golang.org/x/crypto/sha3"
+)
+
+// keccak256 hashes data with the legacy Keccak-256 Ethereum uses
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// EVMAddress returns the key's 0x-prefixed Ethereum address
+func (k *Key) EVMAddress() string {
+	pub := k.priv.PubKey().SerializeUncompressed()
+	return "0x" + hex.EncodeToString(keccak256(pub[1:])[12:])
+}
+
+// rlpList is an RLP list; other RLP items are []byte, uint64 and *big.Int
+type rlpList []any
+
+// rlpEncode encodes an item with Ethereum's recursive length prefix encoding
+func rlpEncode(item any) []byte {
+	switch v := item.(type) {
+	case []byte:
+		if len(v) == 1 && v[0] < 0x80 {
+			return v
+		}
+		return append(rlpHeader(0x80, len(v)), v...)
+	case uint64:
+		return rlpEncode(new(big.Int).SetUint64(v))
+	case *big.Int:
+		return rlpEncode(v.Bytes())
+	case rlpList:
+		var body []byte
+		for _, elem := range v {
+			body = append(body, rlpEncode(elem)...)
+		}
+		return append(rlpHeader(0xc0, len(body)), body...)
+	default:
+		panic(fmt.Sprintf("rlp: unsupported type %T", item))
+	}
+}
+
+// rlpHeader returns the prefix of a string (0x80) or list (0xc0) of n bytes
+func rlpHeader(base byte, n int) []byte {
+	if n < 56 {
+		return []byte{base + byte(n)}
+	}
+	var size [8]byte
+	binary.BigEndian.PutUint64(size[:], uint64(n))
+	trimmed := strings.TrimLeft(string(size[:]), "\x00")
+	return append([]byte{base + 55 + byte(len(trimmed))}, trimmed...)
+}
+
+// accessTuple is an EIP-2930 access list entry
+type accessTuple struct {
+	Address     []byte
+	StorageKeys [][]byte
+}
+
+// dynamicFeeTx is an EIP-1559 transaction
+type dynamicFeeTx struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	Gas        uint64
+	To         []byte
+	Value      *big.Int
+	Data       []byte
+	AccessList []accessTuple
+}
+
+// fields returns the transaction's unsigned RLP fields
+func (tx *dynamicFeeTx) fields() rlpList {
+	accessList := rlpList{}
+	for _, t := range tx.AccessList {
+		keys := rlpList{}
+		for _, k := range t.StorageKeys {
+			keys = append(keys, k)
+		}
+		accessList = append(accessList, rlpList{t.Address, keys})
+	}
+	return rlpList{tx.ChainID, tx.Nonce, tx.GasTipCap, tx.GasFeeCap, tx.Gas, tx.To, tx.Value, tx.Data, accessList}
+}
+
+// sign returns the signed transaction's raw bytes and hash
+func (tx *dynamicFeeTx) sign(key *Key) ([]byte, []byte) {
+	const txType = 0x02
+	sig := key.SignHash(keccak256([]byte{txType}, rlpEncode(tx.fields())))
+
+	fields := append(tx.fields(), uint64(sig[64]), new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]))
+	raw := append([]byte{txType}, rlpEncode(fields)...)
+	return raw, keccak256(raw)
+}
+
+// evmClient calls the Ethereum JSON-RPC API of a chain on the local node
+type evmClient struct {
+	api  *node.APIClient
+	path string
+}
+
+// newEVMClient creates a client for a blockchain's /rpc endpoint
+func newEVMClient(api *node.APIClient, blockchainID string) *evmClient {
+	return &evmClient{api: api, path: "/ext/bc/" + blockchainID + "/rpc"}
+}
+
+// evmLog is a log entry returned by eth_getLogs
+type evmLog struct {
+	Address     string   `json:"address"`
+	Topics      []string `json:"topics"`
+	Data        string   `json:"data"`
+	BlockNumber string   `json:"blockNumber"`
+	TxHash      string   `json:"transactionHash"`
+}
+
+// quantity calls a method returning a hex quantity
+func (c *evmClient) quantity(ctx context.Context, method string, params ...any) (*big.Int, error) {
+	if params == nil {
+		params = []any{}
+	}
+	var s string
+	if err := c.api.Call(ctx, c.path, method, params, &s); err != nil {
+		return nil, err
+	}
+	n, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s result %q", method, s)
+	}
+	return n, nil
+}
+
+// blockNumber returns the chain's latest block number
+func (c *evmClient) blockNumber(ctx context.Context) (uint64, error) {
+	n, err := c.quantity(ctx, "eth_blockNumber")
+	if err != nil {
+		return 0, err
+	}
+	return n.Uint64(), nil
+}
+
+// logs returns the logs of a contract with the given first topic in a block
+// range
+func (c *evmClient) logs(ctx context.Context, from, to uint64, address, topic string) ([]evmLog, error) {
+	filter := map[string]any{
+		"fromBlock": fmt.Sprintf("0x%x", from),
+		"toBlock":   fmt.Sprintf("0x%x", to),
+		"address":   address,
+		"topics":    []string{topic},
+	}
+	var logs []evmLog
+	if err := c.api.Call(ctx, c.path, "eth_getLogs", []any{filter}, &logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// sendTx signs and submits a call with an access list, paying the current
+// gas price, and waits for it to be mined
+func (c *evmClient) sendTx(ctx context.Context, key *Key, to string, data []byte, accessList []accessTuple) (string, error) {
+	from := key.EVMAddress()
+	chainID, err := c.quantity(ctx, "eth_chainId")
+	if err != nil {
+		return "", err
+	}
+	nonce, err := c.quantity(ctx, "eth_getTransactionCount", from, "pending")
+	if err != nil {
+		return "", err
+	}
+	gasPrice, err := c.quantity(ctx, "eth_gasPrice")
+	if err != nil {
+		return "", err
+	}
+
+	toBytes, err := hex.DecodeString(strings.TrimPrefix(to, "0x"))
+	if err != nil || len(toBytes) != 20 {
+		return "", fmt.Errorf("invalid address %s", to)
+	}
+	call := map[string]any{"from": from, "to": to, "data": "0x" + hex.EncodeToString(data)}
+	if len(accessList) > 0 {
+		var list []map[string]any
+		for _, t := range accessList {
+			keys := make([]string, len(t.StorageKeys))
+			for i, k := range t.StorageKeys {
+				keys[i] = "0x" + hex.EncodeToString(k)
+			}
+			list = append(list, map[string]any{"address": "0x" + hex.EncodeToString(t.Address), "storageKeys": keys})
+		}
+		call["accessList"] = list
+	}
+	gas, err := c.quantity(ctx, "eth_estimateGas", call)
+	if err != nil {
+		return "", err
+	}
+
+	tx := &dynamicFeeTx{
+		ChainID:    chainID,
+		Nonce:      nonce.Uint64(),
+		GasTipCap:  gasPrice,
+		GasFeeCap:  new(big.Int).Mul(gasPrice, big.NewInt(2)),
+		Gas:        gas.Uint64() * 12 / 10,
+		To:         toBytes,
+		Value:      new(big.Int),
+		Data:       data,
+		AccessList: accessList,
+	}
+	raw, hash := tx.sign(key)
+	txHash := "0x" + hex.EncodeToString(hash)
+
+	if err := c.api.Call(ctx, c.path, "eth_sendRawTransaction", []any{"0x" + hex.EncodeToString(raw)}, nil); err != nil {
+		return "", err
+	}
+	return txHash, c.waitForReceipt(ctx, txHash)
+}
+
+// waitForReceipt polls until a transaction is mined, failing if it reverted
+func (c *evmClient) waitForReceipt(ctx context.Context, txHash string) error {
+	for {
+		var receipt *struct {
+			Status string `json:"status"`
+		}
+		if err := c.api.Call(ctx, c.path, "eth_getTransactionReceipt", []any{txHash}, &receipt); err != nil {
+			return err
+		}
+		if receipt != nil {
+			if receipt.Status != "0x1" {
+				return fmt.Errorf("transaction %s reverted", txHash)
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package subnet
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// EwoqAddress is the pre-funded address of the well-known local development
+// key, funded by default so local subnets are usable out of the box
+const EwoqAddress = "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"
+
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// Genesis is a subnet-evm genesis file
+type Genesis struct {
+	Config     ChainConfig               `json:"config"`
+	Alloc      map[string]GenesisAccount `json:"alloc"`
+	Nonce      string                    `json:"nonce"`
+	Timestamp  string                    `json:"timestamp"`
+	ExtraData  string                    `json:"extraData"`
+	GasLimit   string                    `json:"gasLimit"`
+	Difficulty string                    `json:"difficulty"`
+	MixHash    string                    `json:"mixHash"`
+	Coinbase   string                    `json:"coinbase"`
+	Number     string                    `json:"number"`
+	GasUsed    string                    `json:"gasUsed"`
+	ParentHash string                    `json:"parentHash"`
+}
+
+// ChainConfig is the chain configuration section of a subnet-evm genesis
+type ChainConfig struct {
+	ChainID             uint64    `json:"chainId"`
+	HomesteadBlock      uint64    `json:"homesteadBlock"`
+	EIP150Block         uint64    `json:"eip150Block"`
+	EIP155Block         uint64    `json:"eip155Block"`
+	EIP158Block         uint64    `json:"eip158Block"`
+	ByzantiumBlock      uint64    `json:"byzantiumBlock"`
+	ConstantinopleBlock uint64    `json:"constantinopleBlock"`
+	PetersburgBlock     uint64    `json:"petersburgBlock"`
+	IstanbulBlock       uint64    `json:"istanbulBlock"`
+	MuirGlacierBlock    uint64    `json:"muirGlacierBlock"`
+	FeeConfig           FeeConfig `json:"feeConfig"`
+	AllowFeeRecipients  bool      `json:"allowFeeRecipients"`
+
+	ContractDeployerAllowListConfig *AllowListConfig     `json:"contractDeployerAllowListConfig,omitempty"`
+	TxAllowListConfig               *AllowListConfig     `json:"txAllowListConfig,omitempty"`
+	ContractNativeMinterConfig      *AllowListConfig     `json:"contractNativeMinterConfig,omitempty"`
+	FeeManagerConfig                *AllowListConfig     `json:"feeManagerConfig,omitempty"`
+	RewardManagerConfig             *RewardManagerConfig `json:"rewardManagerConfig,omitempty"`
+	WarpConfig                      *WarpConfig          `json:"warpConfig,omitempty"`
+}
+
+// FeeConfig is the dynamic fee configuration of a subnet-evm chain
+type FeeConfig struct {
+	GasLimit                 uint64 `json:"gasLimit"`
+	TargetBlockRate          uint64 `json:"targetBlockRate"`
+	MinBaseFee               uint64 `json:"minBaseFee"`
+	TargetGas                uint64 `json:"targetGas"`
+	BaseFeeChangeDenominator uint64 `json:"baseFeeChangeDenominator"`
+	MinBlockGasCost          uint64 `json:"minBlockGasCost"`
+	MaxBlockGasCost          uint64 `json:"maxBlockGasCost"`
+	BlockGasCostStep         uint64 `json:"blockGasCostStep"`
+}
+
+// GenesisAccount is an initial account allocation
+type GenesisAccount struct {
+	Balance string `json:"balance"`
+}
+
+// Allocation funds an address with a balance in wei at genesis
+type Allocation struct {
+	Address string
+	Balance *big.Int
+}
+
+// GenesisOptions holds the tunable parameters of a subnet-evm genesis
+type GenesisOptions struct {
+	ChainID         uint64
+	GasLimit        uint64
+	TargetGas       uint64
+	TargetBlockRate uint64
+	MinBaseFee      uint64
+	Allocations     []Allocation
+	Precompiles     Precompiles
+	// Warp enables Avalanche Warp Messaging from genesis
+	Warp bool
+}
+
+// DefaultGenesisOptions returns the genesis parameters used by default
+func DefaultGenesisOptions() GenesisOptions {
+	return GenesisOptions{
+		GasLimit:        8_000_000,
+		TargetGas:       15_000_000,
+		TargetBlockRate: 2,
+		MinBaseFee:      25_000_000_000, // 25 nAVAX
+	}
+}
+
+// Validate checks the genesis parameters
+func (o GenesisOptions) Validate() error {
+	if o.ChainID == 0 {
+		return fmt.Errorf("chain ID is required")
+	}
+	if name, ok := KnownChainIDs[o.ChainID]; ok {
+		return fmt.Errorf("chain ID %d is already used by %s", o.ChainID, name)
+	}
+	if o.GasLimit == 0 {
+		return fmt.Errorf("gas limit must be greater than zero")
+	}
+	if o.TargetGas == 0 {
+		return fmt.Errorf("target gas must be greater than zero")
+	}
+	if o.TargetBlockRate == 0 {
+		return fmt.Errorf("target block rate must be greater than zero")
+	}
+
+	seen := make(map[string]bool, len(o.Allocations))
+	for _, alloc := range o.Allocations {
+		if err := ValidateAddress(alloc.Address); err != nil {
+			return fmt.Errorf("invalid allocation: %w", err)
+		}
+		key := strings.ToLower(alloc.Address)
+		if seen[key] {
+			return fmt.Errorf("duplicate allocation for %s", alloc.Address)
+		}
+		seen[key] = true
+		if alloc.Balance == nil || alloc.Balance.Sign() < 0 {
+			return fmt.Errorf("invalid allocation balance for %s", alloc.Address)
+		}
+	}
+	return o.Precompiles.Validate()
+}
+
+// NewGenesis builds a subnet-evm genesis from the given options
+func NewGenesis(opts GenesisOptions) (*Genesis, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	allocations := opts.Allocations
+	if len(allocations) == 0 {
+		allocations = []Allocation{{Address: EwoqAddress, Balance: Tokens(1_000_000)}}
+	}
+
+	alloc := make(map[string]GenesisAccount, len(allocations))
+	for _, a := range allocations {
+		alloc[strings.TrimPrefix(a.Address, "0x")] = GenesisAccount{
+			Balance: fmt.Sprintf("0x%x", a.Balance),
+		}
+	}
+
+	genesis := &Genesis{
+		Config: ChainConfig{
+			ChainID: opts.ChainID,
+			FeeConfig: FeeConfig{
+				GasLimit:                 opts.GasLimit,
+				TargetBlockRate:          opts.TargetBlockRate,
+				MinBaseFee:               opts.MinBaseFee,
+				TargetGas:                opts.TargetGas,
+				BaseFeeChangeDenominator: 36,
+				MinBlockGasCost:          0,
+				MaxBlockGasCost:          1_000_000,
+				BlockGasCostStep:         200_000,
+			},
+		},
+		Alloc:      alloc,
+		Nonce:      "0x0",
+		Timestamp:  "0x0",
+		ExtraData:  "0x",
+		GasLimit:   fmt.Sprintf("0x%x", opts.GasLimit),
+		Difficulty: "0x0",
+		MixHash:    "0x" + strings.Repeat("0", 64),
+		Coinbase:   "0x" + strings.Repeat("0", 40),
+		Number:     "0x0",
+		GasUsed:    "0x0",
+		ParentHash: "0x" + strings.Repeat("0", 64),
+	}
+	opts.Precompiles.apply(&genesis.Config)
+	if opts.Warp {
+		genesis.Config.WarpConfig = &WarpConfig{QuorumNumerator: DefaultWarpQuorum}
+	}
+	return genesis, nil
+}
+
+// Marshal encodes the genesis as indented JSON
+func (g *Genesis) Marshal() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+// ValidateAddress checks that s is a 0x-prefixed hex EVM address
+func ValidateAddress(s string) error {
+	if !addressPattern.MatchString(s) {
+		return fmt.Errorf("%q is not a valid address", s)
+	}
+	return nil
+}
+
+// Tokens converts a whole token amount to wei
+func Tokens(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), big.NewInt(1e18))
+}
+
+// ParseAllocation parses an "address=amount" allocation, with the amount in
+// whole tokens (decimals allowed)
+func ParseAllocation(s string) (Allocation, error) {
+	address, amount, ok := strings.Cut(s, "=")
+	if !ok {
+		return Allocation{}, fmt.Errorf("invalid allocation %q, expected address=amount", s)
+	}
+	if err := ValidateAddress(address); err != nil {
+		return Allocation{}, err
+	}
+
+	tokens, ok := new(big.Rat).SetString(amount)
+	if !ok || tokens.Sign() < 0 {
+		return Allocation{}, fmt.Errorf("invalid allocation amount %q", amount)
+	}
+	wei := new(big.Rat).Mul(tokens, new(big.Rat).SetInt(Tokens(1)))
+	if !wei.IsInt() {
+		return Allocation{}, fmt.Errorf("allocation amount %q has more than 18 decimals", amount)
+	}
+
+	return Allocation{Address: address, Balance: wei.Num()}, nil
+}
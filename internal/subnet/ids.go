@@ -0,0 +1,137 @@
+package subnet
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// ID is a 32-byte avalanchego identifier such as a transaction, subnet or VM ID
+type ID [32]byte
+
+// String returns the CB58 encoding of the ID
+func (id ID) String() string {
+	return CB58Encode(id[:])
+}
+
+// ParseID decodes a CB58 encoded ID
+func ParseID(s string) (ID, error) {
+	var id ID
+	b, err := CB58Decode(s)
+	if err != nil {
+		return id, fmt.Errorf("invalid ID %q: %w", s, err)
+	}
+	if len(b) != len(id) {
+		return id, fmt.Errorf("invalid ID %q: expected 32 bytes, got %d", s, len(b))
+	}
+	copy(id[:], b)
+	return id, nil
+}
+
+// nodeIDPrefix prefixes the CB58 encoding of node IDs
+const nodeIDPrefix = "NodeID-"
+
+// NodeID returns the short ID formatted as a node ID
+func (id ShortID) NodeID() string {
+	return nodeIDPrefix + CB58Encode(id[:])
+}
+
+// ParseNodeID decodes a NodeID-<cb58> node ID
+func ParseNodeID(s string) (ShortID, error) {
+	var id ShortID
+	if !strings.HasPrefix(s, nodeIDPrefix) {
+		return id, fmt.Errorf("invalid node ID %q: missing %s prefix", s, nodeIDPrefix)
+	}
+	b, err := CB58Decode(strings.TrimPrefix(s, nodeIDPrefix))
+	if err != nil {
+		return id, fmt.Errorf("invalid node ID %q: %w", s, err)
+	}
+	if len(b) != len(id) {
+		return id, fmt.Errorf("invalid node ID %q: expected 20 bytes, got %d", s, len(b))
+	}
+	copy(id[:], b)
+	return id, nil
+}
+
+// VMID computes the VM ID avalanchego derives from a VM name: the name
+// zero-padded to 32 bytes, CB58 encoded
+func VMID(name string) (string, error) {
+	if len(name) > 32 {
+		return "", fmt.Errorf("VM name %q is longer than 32 bytes", name)
+	}
+	var id [32]byte
+	copy(id[:], name)
+	return CB58Encode(id[:]), nil
+}
+
+// CB58Encode encodes bytes as base58 with a 4-byte sha256 checksum, the
+// format avalanchego uses for IDs
+func CB58Encode(b []byte) string {
+	checksum := sha256.Sum256(b)
+	return base58Encode(append(append([]byte{}, b...), checksum[len(checksum)-4:]...))
+}
+
+// CB58Decode decodes a CB58 string, verifying its checksum
+func CB58Decode(s string) ([]byte, error) {
+	b, err := base58Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < 4 {
+		return nil, fmt.Errorf("input too short")
+	}
+	payload, checksum := b[:len(b)-4], b[len(b)-4:]
+	expected := sha256.Sum256(payload)
+	if !bytes.Equal(checksum, expected[len(expected)-4:]) {
+		return nil, fmt.Errorf("invalid checksum")
+	}
+	return payload, nil
+}
+
+// base58Decode decodes a string in the bitcoin base58 alphabet
+func base58Decode(s string) ([]byte, error) {
+	n := new(big.Int)
+	base := big.NewInt(58)
+	for _, c := range s {
+		i := strings.IndexRune(base58Alphabet, c)
+		if i < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", c)
+		}
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// base58Encode encodes bytes using the bitcoin base58 alphabet
+func base58Encode(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+	base := big.NewInt(58)
+	mod := new(big.Int)
+
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, base, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
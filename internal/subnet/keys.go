@@ -0,0 +1,84 @@
+package subnet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// ewoqPrivateKey is the well-known key pre-funded on local networks
+const ewoqPrivateKey = "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027"
+
+// ShortID is a 20-byte avalanchego address
+type ShortID [20]byte
+
+// Key is a secp256k1 key used to sign P-Chain transactions
+type Key struct {
+	priv *secp256k1.PrivateKey
+}
+
+// EwoqKey returns the pre-funded local network key
+func EwoqKey() *Key {
+	key, _ := KeyFromHex(ewoqPrivateKey)
+	return key
+}
+
+// KeyFromHex parses a hex encoded private key
+func KeyFromHex(s string) (*Key, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(b) != 32 {
+		return nil, fmt.Errorf("invalid private key")
+	}
+	return &Key{priv: secp256k1.PrivKeyFromBytes(b)}, nil
+}
+
+// Address returns the key's short ID: ripemd160(sha256(compressed public key))
+func (k *Key) Address() ShortID {
+	sha := sha256.Sum256(k.priv.PubKey().SerializeCompressed())
+	h := ripemd160.New()
+	h.Write(sha[:])
+
+	var addr ShortID
+	copy(addr[:], h.Sum(nil))
+	return addr
+}
+
+// SignHash signs a 32-byte hash, returning the [r || s || v] recoverable
+// signature format avalanchego expects
+func (k *Key) SignHash(hash []byte) [65]byte {
+	// SignCompact returns [27 + v || r || s]
+	compact := ecdsa.SignCompact(k.priv, hash, false)
+
+	var sig [65]byte
+	copy(sig[:64], compact[1:])
+	sig[64] = compact[0] - 27
+	return sig
+}
+
+// HRP returns the bech32 human-readable part avalanchego uses for a network
+func HRP(networkID uint32) string {
+	switch networkID {
+	case 1:
+		return "avax"
+	case 5:
+		return "fuji"
+	case 12345:
+		return "local"
+	default:
+		return "custom"
+	}
+}
+
+// FormatAddress formats an address for a chain, e.g. P-local1...
+func FormatAddress(chain string, networkID uint32, addr ShortID) (string, error) {
+	encoded, err := bech32Encode(HRP(networkID), addr[:])
+	if err != nil {
+		return "", err
+	}
+	return chain + "-" + encoded, nil
+}
@@ -0,0 +1,18 @@
+package subnet
+
+// KnownChainIDs maps EVM chain IDs in use by well-known networks to their
+// names. Subnets must not reuse them, or wallets will confuse the chains.
+var KnownChainIDs = map[uint64]string{
+	1:        "Ethereum Mainnet",
+	5:        "Ethereum Goerli",
+	10:       "Optimism",
+	56:       "BNB Smart Chain",
+	137:      "Polygon",
+	250:      "Fantom Opera",
+	8453:     "Base",
+	42161:    "Arbitrum One",
+	43112:    "Avalanche Local C-Chain",
+	43113:    "Avalanche Fuji C-Chain",
+	43114:    "Avalanche C-Chain",
+	11155111: "Ethereum Sepolia",
+}
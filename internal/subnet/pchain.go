@@ -0,0 +1,321 @@
+package subnet
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// P-Chain codec version and type IDs, as registered by avalanchego's
+// platformvm transaction codec
+const (
+	codecVersion uint16 = 0
+
+	typeTransferInput           uint32 = 5
+	typeTransferOutput          uint32 = 7
+	typeCredential              uint32 = 9
+	typeInput                   uint32 = 10
+	typeOutputOwners            uint32 = 11
+	typeAddSubnetValidatorTx    uint32 = 13
+	typeCreateChainTx           uint32 = 15
+	typeCreateSubnetTx          uint32 = 16
+	typeRemoveSubnetValidatorTx uint32 = 23
+)
+
+// packer serializes values in avalanchego's big-endian linear codec format
+type packer struct {
+	buf bytes.Buffer
+}
+
+func (p *packer) u16(v uint16) {
+	binary.Write(&p.buf, binary.BigEndian, v)
+}
+
+func (p *packer) u32(v uint32) {
+	binary.Write(&p.buf, binary.BigEndian, v)
+}
+
+func (p *packer) u64(v uint64) {
+	binary.Write(&p.buf, binary.BigEndian, v)
+}
+
+func (p *packer) fixed(b []byte) {
+	p.buf.Write(b)
+}
+
+func (p *packer) varBytes(b []byte) {
+	p.u32(uint32(len(b)))
+	p.buf.Write(b)
+}
+
+func (p *packer) str(s string) {
+	p.u16(uint16(len(s)))
+	p.buf.WriteString(s)
+}
+
+// unpacker deserializes values in avalanchego's linear codec format
+type unpacker struct {
+	b   []byte
+	err error
+}
+
+func (u *unpacker) take(n int) []byte {
+	if u.err != nil {
+		return make([]byte, n)
+	}
+	if len(u.b) < n {
+		u.err = fmt.Errorf("unexpected end of input")
+		return make([]byte, n)
+	}
+	out := u.b[:n]
+	u.b = u.b[n:]
+	return out
+}
+
+func (u *unpacker) u16() uint16 { return binary.BigEndian.Uint16(u.take(2)) }
+func (u *unpacker) u32() uint32 { return binary.BigEndian.Uint32(u.take(4)) }
+func (u *unpacker) u64() uint64 { return binary.BigEndian.Uint64(u.take(8)) }
+
+// OutputOwners is a set of addresses that can spend an output or control a subnet
+type OutputOwners struct {
+	Locktime  uint64
+	Threshold uint32
+	Addrs     []ShortID
+}
+
+func (o OutputOwners) pack(p *packer) {
+	p.u64(o.Locktime)
+	p.u32(o.Threshold)
+	p.u32(uint32(len(o.Addrs)))
+	for _, addr := range o.Addrs {
+		p.fixed(addr[:])
+	}
+}
+
+// utxo is a spendable secp256k1 transfer output
+type utxo struct {
+	TxID        ID
+	OutputIndex uint32
+	AssetID     ID
+	Amount      uint64
+	Owners      OutputOwners
+}
+
+// parseUTXO decodes a codec-serialized UTXO, skipping non-transfer outputs
+func parseUTXO(b []byte) (*utxo, error) {
+	u := &unpacker{b: b}
+	if version := u.u16(); version != codecVersion {
+		return nil, fmt.Errorf("unsupported codec version %d", version)
+	}
+
+	out := &utxo{}
+	copy(out.TxID[:], u.take(32))
+	out.OutputIndex = u.u32()
+	copy(out.AssetID[:], u.take(32))
+
+	if typeID := u.u32(); typeID != typeTransferOutput {
+		// Locked and other output types are never spent by Kinetic
+		return nil, nil
+	}
+	out.Amount = u.u64()
+	out.Owners.Locktime = u.u64()
+	out.Owners.Threshold = u.u32()
+	n := u.u32()
+	if u.err == nil && int(n)*20 > len(u.b) {
+		return nil, fmt.Errorf("invalid address count %d", n)
+	}
+	for i := uint32(0); i < n; i++ {
+		var addr ShortID
+		copy(addr[:], u.take(20))
+		out.Owners.Addrs = append(out.Owners.Addrs, addr)
+	}
+
+	if u.err != nil {
+		return nil, fmt.Errorf("failed to parse UTXO: %w", u.err)
+	}
+	return out, nil
+}
+
+// transferableInput spends a UTXO
+type transferableInput struct {
+	TxID        ID
+	OutputIndex uint32
+	AssetID     ID
+	Amount      uint64
+	SigIndices  []uint32
+}
+
+func (in transferableInput) pack(p *packer) {
+	p.fixed(in.TxID[:])
+	p.u32(in.OutputIndex)
+	p.fixed(in.AssetID[:])
+	p.u32(typeTransferInput)
+	p.u64(in.Amount)
+	p.u32(uint32(len(in.SigIndices)))
+	for _, i := range in.SigIndices {
+		p.u32(i)
+	}
+}
+
+// transferableOutput creates a UTXO
+type transferableOutput struct {
+	AssetID ID
+	Amount  uint64
+	Owners  OutputOwners
+}
+
+func (out transferableOutput) pack(p *packer) {
+	p.fixed(out.AssetID[:])
+	p.u32(typeTransferOutput)
+	p.u64(out.Amount)
+	out.Owners.pack(p)
+}
+
+// baseTx holds the fields shared by all P-Chain transactions
+type baseTx struct {
+	NetworkID    uint32
+	BlockchainID ID
+	Outs         []transferableOutput
+	Ins          []transferableInput
+	Memo         []byte
+}
+
+func (tx baseTx) pack(p *packer) {
+	p.u32(tx.NetworkID)
+	p.fixed(tx.BlockchainID[:])
+	p.u32(uint32(len(tx.Outs)))
+	for _, out := range tx.Outs {
+		out.pack(p)
+	}
+	p.u32(uint32(len(tx.Ins)))
+	for _, in := range tx.Ins {
+		in.pack(p)
+	}
+	p.varBytes(tx.Memo)
+}
+
+// sortInputs orders inputs by UTXO ID as the codec requires
+func sortInputs(ins []transferableInput) {
+	sort.Slice(ins, func(i, j int) bool {
+		if c := bytes.Compare(ins[i].TxID[:], ins[j].TxID[:]); c != 0 {
+			return c < 0
+		}
+		return ins[i].OutputIndex < ins[j].OutputIndex
+	})
+}
+
+// unsignedTx is a P-Chain transaction body
+type unsignedTx interface {
+	pack(p *packer)
+}
+
+// createSubnetTx creates a subnet controlled by Owner
+type createSubnetTx struct {
+	baseTx
+	Owner OutputOwners
+}
+
+func (tx createSubnetTx) pack(p *packer) {
+	p.u32(typeCreateSubnetTx)
+	tx.baseTx.pack(p)
+	p.u32(typeOutputOwners)
+	tx.Owner.pack(p)
+}
+
+// createChainTx creates a blockchain on a subnet
+type createChainTx struct {
+	baseTx
+	SubnetID          ID
+	ChainName         string
+	VMID              ID
+	GenesisData       []byte
+	SubnetAuthIndices []uint32
+}
+
+func (tx createChainTx) pack(p *packer) {
+	p.u32(typeCreateChainTx)
+	tx.baseTx.pack(p)
+	p.fixed(tx.SubnetID[:])
+	p.str(tx.ChainName)
+	p.fixed(tx.VMID[:])
+	p.u32(0) // no feature extensions
+	p.varBytes(tx.GenesisData)
+	packSubnetAuth(p, tx.SubnetAuthIndices)
+}
+
+// addSubnetValidatorTx adds a primary network validator to a subnet
+type addSubnetValidatorTx struct {
+	baseTx
+	NodeID            ShortID
+	Start             uint64
+	End               uint64
+	Weight            uint64
+	SubnetID          ID
+	SubnetAuthIndices []uint32
+}
+
+func (tx addSubnetValidatorTx) pack(p *packer) {
+	p.u32(typeAddSubnetValidatorTx)
+	tx.baseTx.pack(p)
+	p.fixed(tx.NodeID[:])
+	p.u64(tx.Start)
+	p.u64(tx.End)
+	p.u64(tx.Weight)
+	p.fixed(tx.SubnetID[:])
+	packSubnetAuth(p, tx.SubnetAuthIndices)
+}
+
+// removeSubnetValidatorTx removes a validator from a subnet before its end time
+type removeSubnetValidatorTx struct {
+	baseTx
+	NodeID            ShortID
+	SubnetID          ID
+	SubnetAuthIndices []uint32
+}
+
+func (tx removeSubnetValidatorTx) pack(p *packer) {
+	p.u32(typeRemoveSubnetValidatorTx)
+	tx.baseTx.pack(p)
+	p.fixed(tx.NodeID[:])
+	p.fixed(tx.SubnetID[:])
+	packSubnetAuth(p, tx.SubnetAuthIndices)
+}
+
+// packSubnetAuth serializes the proof of subnet ownership
+func packSubnetAuth(p *packer, sigIndices []uint32) {
+	p.u32(typeInput)
+	p.u32(uint32(len(sigIndices)))
+	for _, i := range sigIndices {
+		p.u32(i)
+	}
+}
+
+// unsignedBytes serializes a transaction body with its codec version
+func unsignedBytes(tx unsignedTx) []byte {
+	p := &packer{}
+	p.u16(codecVersion)
+	tx.pack(p)
+	return p.buf.Bytes()
+}
+
+// signTx signs the transaction once per credential, returning the signed
+// bytes and the transaction ID
+func signTx(tx unsignedTx, key *Key, credentials int) ([]byte, ID) {
+	unsigned := unsignedBytes(tx)
+	hash := sha256.Sum256(unsigned)
+	sig := key.SignHash(hash[:])
+
+	p := &packer{}
+	p.fixed(unsigned)
+	p.u32(uint32(credentials))
+	for i := 0; i < credentials; i++ {
+		p.u32(typeCredential)
+		p.u32(1)
+		p.fixed(sig[:])
+	}
+
+	signed := p.buf.Bytes()
+	return signed, sha256.Sum256(signed)
+}
@@ -0,0 +1,218 @@
+package subnet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Precompile names as accepted on the command line
+const (
+	PrecompileDeployerAllowList = "deployer-allowlist"
+	PrecompileTxAllowList       = "tx-allowlist"
+	PrecompileNativeMinter      = "native-minter"
+	PrecompileFeeManager        = "fee-manager"
+	PrecompileRewardManager     = "reward-manager"
+)
+
+// PrecompileNames lists the supported precompiles in display order
+var PrecompileNames = []string{
+	PrecompileDeployerAllowList,
+	PrecompileTxAllowList,
+	PrecompileNativeMinter,
+	PrecompileFeeManager,
+	PrecompileRewardManager,
+}
+
+// precompileTitles are the human readable precompile names
+var precompileTitles = map[string]string{
+	PrecompileDeployerAllowList: "contract deployer allow list",
+	PrecompileTxAllowList:       "transaction allow list",
+	PrecompileNativeMinter:      "native minter",
+	PrecompileFeeManager:        "fee manager",
+	PrecompileRewardManager:     "reward manager",
+}
+
+// precompileConfigKeys are the chain config keys of each precompile in
+// genesis and upgrade.json
+var precompileConfigKeys = map[string]string{
+	PrecompileDeployerAllowList: "contractDeployerAllowListConfig",
+	PrecompileTxAllowList:       "txAllowListConfig",
+	PrecompileNativeMinter:      "contractNativeMinterConfig",
+	PrecompileFeeManager:        "feeManagerConfig",
+	PrecompileRewardManager:     "rewardManagerConfig",
+}
+
+// PrecompileTitle returns the human readable name of a precompile
+func PrecompileTitle(name string) string {
+	return precompileTitles[name]
+}
+
+// AllowList holds the addresses granted a role on a precompile. Admins can
+// change the list; enabled addresses can use the precompile.
+type AllowList struct {
+	Admins  []string
+	Enabled []string
+}
+
+// Precompiles selects the subnet-evm precompiles activated at genesis. A nil
+// allow list leaves the precompile disabled.
+type Precompiles struct {
+	DeployerAllowList *AllowList
+	TxAllowList       *AllowList
+	NativeMinter      *AllowList
+	FeeManager        *AllowList
+	RewardManager     *AllowList
+
+	// AllowFeeRecipients lets validators choose their fee recipient
+	AllowFeeRecipients bool
+	// RewardAddress receives all fees; requires the reward manager
+	RewardAddress string
+}
+
+// Get returns the allow list of a precompile by name
+func (p *Precompiles) Get(name string) *AllowList {
+	switch name {
+	case PrecompileDeployerAllowList:
+		return p.DeployerAllowList
+	case PrecompileTxAllowList:
+		return p.TxAllowList
+	case PrecompileNativeMinter:
+		return p.NativeMinter
+	case PrecompileFeeManager:
+		return p.FeeManager
+	case PrecompileRewardManager:
+		return p.RewardManager
+	}
+	return nil
+}
+
+// Set enables a precompile by name with the given allow list
+func (p *Precompiles) Set(name string, list *AllowList) error {
+	switch name {
+	case PrecompileDeployerAllowList:
+		p.DeployerAllowList = list
+	case PrecompileTxAllowList:
+		p.TxAllowList = list
+	case PrecompileNativeMinter:
+		p.NativeMinter = list
+	case PrecompileFeeManager:
+		p.FeeManager = list
+	case PrecompileRewardManager:
+		p.RewardManager = list
+	default:
+		return fmt.Errorf("unknown precompile %q", name)
+	}
+	return nil
+}
+
+// Validate checks address lists and rejects settings that cannot work together
+func (p *Precompiles) Validate() error {
+	for _, name := range PrecompileNames {
+		list := p.Get(name)
+		if list == nil {
+			continue
+		}
+		if len(list.Admins) == 0 && len(list.Enabled) == 0 {
+			return fmt.Errorf("%s requires at least one admin or enabled address", name)
+		}
+
+		seen := make(map[string]string)
+		for _, group := range []struct {
+			role  string
+			addrs []string
+		}{{"admin", list.Admins}, {"enabled", list.Enabled}} {
+			for _, addr := range group.addrs {
+				if err := ValidateAddress(addr); err != nil {
+					return fmt.Errorf("invalid %s %s address: %w", name, group.role, err)
+				}
+				key := strings.ToLower(addr)
+				if role, ok := seen[key]; ok {
+					return fmt.Errorf("%s lists %s as both %s and %s", name, addr, role, group.role)
+				}
+				seen[key] = group.role
+			}
+		}
+	}
+
+	// Precompile admins must be able to send the transactions that manage them
+	if p.TxAllowList != nil {
+		allowed := make(map[string]bool)
+		for _, addr := range append(append([]string{}, p.TxAllowList.Admins...), p.TxAllowList.Enabled...) {
+			allowed[strings.ToLower(addr)] = true
+		}
+		for _, name := range PrecompileNames {
+			list := p.Get(name)
+			if list == nil || name == PrecompileTxAllowList {
+				continue
+			}
+			for _, addr := range list.Admins {
+				if !allowed[strings.ToLower(addr)] {
+					return fmt.Errorf("%s admin %s is not allowed to send transactions by the %s", name, addr, PrecompileTxAllowList)
+				}
+			}
+		}
+	}
+
+	if p.RewardAddress != "" {
+		if p.RewardManager == nil {
+			return fmt.Errorf("a reward address requires the %s precompile", PrecompileRewardManager)
+		}
+		if p.AllowFeeRecipients {
+			return fmt.Errorf("a reward address cannot be combined with allowing fee recipients")
+		}
+		if err := ValidateAddress(p.RewardAddress); err != nil {
+			return fmt.Errorf("invalid reward address: %w", err)
+		}
+	}
+	return nil
+}
+
+// AllowListConfig is the genesis config of a subnet-evm allow list precompile
+type AllowListConfig struct {
+	BlockTimestamp   uint64   `json:"blockTimestamp"`
+	AdminAddresses   []string `json:"adminAddresses,omitempty"`
+	EnabledAddresses []string `json:"enabledAddresses,omitempty"`
+}
+
+// RewardManagerConfig is the genesis config of the reward manager precompile
+type RewardManagerConfig struct {
+	AllowListConfig
+	InitialRewardConfig *RewardConfig `json:"initialRewardConfig,omitempty"`
+}
+
+// RewardConfig is the reward manager's initial fee distribution
+type RewardConfig struct {
+	AllowFeeRecipients bool   `json:"allowFeeRecipients,omitempty"`
+	RewardAddress      string `json:"rewardAddress,omitempty"`
+}
+
+// allowListConfig converts an allow list to its genesis form, active from
+// genesis
+func allowListConfig(list *AllowList) *AllowListConfig {
+	if list == nil {
+		return nil
+	}
+	return &AllowListConfig{AdminAddresses: list.Admins, EnabledAddresses: list.Enabled}
+}
+
+// apply writes the precompile configs into a chain config
+func (p *Precompiles) apply(c *ChainConfig) {
+	c.ContractDeployerAllowListConfig = allowListConfig(p.DeployerAllowList)
+	c.TxAllowListConfig = allowListConfig(p.TxAllowList)
+	c.ContractNativeMinterConfig = allowListConfig(p.NativeMinter)
+	c.FeeManagerConfig = allowListConfig(p.FeeManager)
+
+	if p.RewardManager == nil {
+		c.AllowFeeRecipients = p.AllowFeeRecipients
+		return
+	}
+	// With the reward manager active the initial reward config decides where
+	// fees go
+	c.RewardManagerConfig = &RewardManagerConfig{AllowListConfig: *allowListConfig(p.RewardManager)}
+	if p.AllowFeeRecipients || p.RewardAddress != "" {
+		c.RewardManagerConfig.InitialRewardConfig = &RewardConfig{
+			AllowFeeRecipients: p.AllowFeeRecipients,
+			RewardAddress:      p.RewardAddress,
+		}
+	}
+}
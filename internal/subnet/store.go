@@ -0,0 +1,150 @@
+package subnet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kinetic-dev/kinetic/internal/system"
+)
+
+const (
+	definitionFile = "subnet.json"
+	genesisFile    = "genesis.json"
+)
+
+// ErrNotFound is returned when a subnet is not in the store
+var ErrNotFound = errors.New("subnet not found")
+
+// Store is the on-disk registry of subnet definitions, one directory per
+// subnet holding its definition, genesis and related files
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store rooted at dir
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// DefaultStore returns the store under the Kinetic data directory
+func DefaultStore() (*Store, error) {
+	dataDir, err := system.GetDataDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data directory: %w", err)
+	}
+	return NewStore(filepath.Join(dataDir, "subnets")), nil
+}
+
+// Dir returns the directory holding the named subnet's files
+func (s *Store) Dir(name string) string {
+	return filepath.Join(s.dir, name)
+}
+
+// GenesisPath returns the path of the named subnet's genesis file
+func (s *Store) GenesisPath(name string) string {
+	return filepath.Join(s.Dir(name), genesisFile)
+}
+
+// Exists reports whether a subnet with the given name is stored
+func (s *Store) Exists(name string) bool {
+	_, err := os.Stat(filepath.Join(s.Dir(name), definitionFile))
+	return err == nil
+}
+
+// Save writes a subnet definition and its genesis
+func (s *Store) Save(sn *Subnet, genesis []byte) error {
+	if err := s.Update(sn); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(s.GenesisPath(sn.Name), genesis, 0644); err != nil {
+		return fmt.Errorf("failed to write genesis: %w", err)
+	}
+	return nil
+}
+
+// Update writes a subnet definition, leaving its genesis untouched
+func (s *Store) Update(sn *Subnet) error {
+	if err := system.EnsureDir(s.Dir(sn.Name)); err != nil {
+		return fmt.Errorf("failed to create subnet directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(sn, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode subnet definition: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir(sn.Name), definitionFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write subnet definition: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the named subnet and all of its files
+func (s *Store) Delete(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if !s.Exists(name) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err := os.RemoveAll(s.Dir(name)); err != nil {
+		return fmt.Errorf("failed to delete subnet: %w", err)
+	}
+	return nil
+}
+
+// Get reads the named subnet definition
+func (s *Store) Get(name string) (*Subnet, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(s.Dir(name), definitionFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to read subnet definition: %w", err)
+	}
+
+	sn := &Subnet{}
+	if err := json.Unmarshal(data, sn); err != nil {
+		return nil, fmt.Errorf("failed to parse subnet definition: %w", err)
+	}
+	return sn, nil
+}
+
+// Genesis reads the named subnet's genesis file
+func (s *Store) Genesis(name string) ([]byte, error) {
+	data, err := os.ReadFile(s.GenesisPath(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis: %w", err)
+	}
+	return data, nil
+}
+
+// List returns all stored subnet definitions sorted by name
+func (s *Store) List() ([]*Subnet, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read subnet store: %w", err)
+	}
+
+	var subnets []*Subnet
+	for _, entry := range entries {
+		if !entry.IsDir() || !s.Exists(entry.Name()) {
+			continue
+		}
+		sn, err := s.Get(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		subnets = append(subnets, sn)
+	}
+	return subnets, nil
+}
@@ -0,0 +1,165 @@
+package subnet
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// VMSubnetEVM is the default virtual machine for subnets
+const VMSubnetEVM = "subnet-evm"
+
+// subnetEVMName is the name subnet-evm's VM ID is derived from
+const subnetEVMName = "subnetevm"
+
+// Networks are the networks a subnet can be deployed to
+var Networks = []string{"local", "fuji", "mainnet"}
+
+var namePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+// Subnet is a stored subnet definition
+type Subnet struct {
+	Name        string                 `json:"name"`
+	VM          string                 `json:"vm"`
+	VMName      string                 `json:"vm_name,omitempty"`
+	VMModule    string                 `json:"vm_module,omitempty"`
+	VMChecksum  string                 `json:"vm_sha256,omitempty"`
+	VMID        string                 `json:"vm_id"`
+	ChainID     uint64                 `json:"chain_id"`
+	TokenName   string                 `json:"token_name"`
+	CreatedAt   time.Time              `json:"created_at"`
+	Deployments map[string]*Deployment `json:"deployments,omitempty"`
+	// PendingDeployments hold what unfinished deployments already created
+	// on-chain, so a retry reuses it instead of creating it again
+	PendingDeployments map[string]*Deployment `json:"pending_deployments,omitempty"`
+}
+
+// Deployment is the state of a subnet on one network
+type Deployment struct {
+	SubnetID     string    `json:"subnet_id"`
+	BlockchainID string    `json:"blockchain_id"`
+	Validators   []string  `json:"validators,omitempty"`
+	RPCURL       string    `json:"rpc_url"`
+	DeployedAt   time.Time `json:"deployed_at"`
+}
+
+// Deployment returns the subnet's deployment on a network, or nil
+func (s *Subnet) Deployment(network string) *Deployment {
+	return s.Deployments[network]
+}
+
+// SetDeployment records the subnet's deployment on a network, which
+// completes any pending deployment there
+func (s *Subnet) SetDeployment(network string, d *Deployment) {
+	if s.Deployments == nil {
+		s.Deployments = make(map[string]*Deployment)
+	}
+	s.Deployments[network] = d
+	delete(s.PendingDeployments, network)
+}
+
+// PendingDeployment returns the subnet's unfinished deployment on a network,
+// or nil
+func (s *Subnet) PendingDeployment(network string) *Deployment {
+	return s.PendingDeployments[network]
+}
+
+// SetPendingDeployment records the progress of an unfinished deployment
+func (s *Subnet) SetPendingDeployment(network string, d *Deployment) {
+	if s.PendingDeployments == nil {
+		s.PendingDeployments = make(map[string]*Deployment)
+	}
+	s.PendingDeployments[network] = d
+}
+
+// CreateOptions holds the options for subnet creation
+type CreateOptions struct {
+	Name      string
+	VM        string
+	TokenName string
+	Genesis   GenesisOptions
+	// CustomVM configures the VM and genesis when VM is custom
+	CustomVM CustomVMOptions
+}
+
+// ValidateName checks that a subnet name is usable as a directory name and
+// as an avalanchego chain alias
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid subnet name %q: must start with a letter and contain only letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// Create generates a subnet genesis and stores the subnet definition
+func Create(store *Store, opts CreateOptions) (*Subnet, error) {
+	if err := ValidateName(opts.Name); err != nil {
+		return nil, err
+	}
+	if store.Exists(opts.Name) {
+		return nil, fmt.Errorf("subnet %s already exists", opts.Name)
+	}
+	if opts.VM == "" {
+		opts.VM = VMSubnetEVM
+	}
+	if opts.VM == VMCustom {
+		return createCustom(store, opts)
+	}
+	if opts.VM != VMSubnetEVM {
+		return nil, fmt.Errorf("unsupported VM type %q", opts.VM)
+	}
+	if opts.TokenName == "" {
+		return nil, fmt.Errorf("token name is required")
+	}
+
+	if err := checkChainID(store, opts.Genesis.ChainID); err != nil {
+		return nil, err
+	}
+
+	genesis, err := NewGenesis(opts.Genesis)
+	if err != nil {
+		return nil, fmt.Errorf("invalid genesis: %w", err)
+	}
+	data, err := genesis.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode genesis: %w", err)
+	}
+
+	vmID, err := VMID(subnetEVMName)
+	if err != nil {
+		return nil, err
+	}
+
+	sn := &Subnet{
+		Name:      opts.Name,
+		VM:        opts.VM,
+		VMID:      vmID,
+		ChainID:   opts.Genesis.ChainID,
+		TokenName: opts.TokenName,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := store.Save(sn, data); err != nil {
+		return nil, err
+	}
+	if opts.Genesis.Warp {
+		// Relayers fetch aggregate signatures from the warp API
+		if _, _, err := UpdateChainConfig(store, sn.Name, map[string]any{warpAPIKey: true}, nil); err != nil {
+			return nil, err
+		}
+	}
+	return sn, nil
+}
+
+// checkChainID rejects chain IDs already used by our own subnets
+func checkChainID(store *Store, chainID uint64) error {
+	existing, err := store.List()
+	if err != nil {
+		return err
+	}
+	for _, sn := range existing {
+		if sn.ChainID == chainID {
+			return fmt.Errorf("chain ID %d is already used by subnet %s", sn.ChainID, sn.Name)
+		}
+	}
+	return nil
+}
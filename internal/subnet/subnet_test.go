@@ -1,10 +1,1454 @@
 package subnet
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/node"
+	"github.com/kinetic-dev/kinetic/internal/system"
 )
 
-// TODO: Add subnet package tests once the package implementation is complete
-func TestSubnet(t *testing.T) {
-	t.Skip("Subnet package tests not implemented yet")
+func TestNewGenesis(t *testing.T) {
+	opts := DefaultGenesisOptions()
+	opts.ChainID = 99999
+	opts.GasLimit = 15_000_000
+
+	alloc, err := ParseAllocation("0x1111111111111111111111111111111111111111=1.5")
+	if err != nil {
+		t.Fatalf("Failed to parse allocation: %v", err)
+	}
+	opts.Allocations = []Allocation{alloc}
+
+	genesis, err := NewGenesis(opts)
+	if err != nil {
+		t.Fatalf("Failed to create genesis: %v", err)
+	}
+
+	data, err := genesis.Marshal()
+	if err != nil {
+		t.Fatalf("Failed to marshal genesis: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Genesis is not valid JSON: %v", err)
+	}
+
+	config := decoded["config"].(map[string]any)
+	if config["chainId"].(float64) != 99999 {
+		t.Errorf("expected chain ID 99999, got %v", config["chainId"])
+	}
+	feeConfig := config["feeConfig"].(map[string]any)
+	if feeConfig["gasLimit"].(float64) != 15_000_000 {
+		t.Errorf("expected fee config gas limit 15000000, got %v", feeConfig["gasLimit"])
+	}
+	if decoded["gasLimit"] != "0xe4e1c0" {
+		t.Errorf("expected header gas limit 0xe4e1c0, got %v", decoded["gasLimit"])
+	}
+
+	account, ok := genesis.Alloc["1111111111111111111111111111111111111111"]
+	if !ok {
+		t.Fatal("expected allocation for test address")
+	}
+	if account.Balance != "0x14d1120d7b160000" {
+		t.Errorf("expected balance 1.5 tokens in wei, got %s", account.Balance)
+	}
+}
+
+func TestNewGenesisDefaultAllocation(t *testing.T) {
+	opts := DefaultGenesisOptions()
+	opts.ChainID = 99999
+
+	genesis, err := NewGenesis(opts)
+	if err != nil {
+		t.Fatalf("Failed to create genesis: %v", err)
+	}
+	if _, ok := genesis.Alloc[EwoqAddress[2:]]; !ok {
+		t.Error("expected the ewoq address to be funded by default")
+	}
+}
+
+func TestGenesisOptionsValidate(t *testing.T) {
+	valid := DefaultGenesisOptions()
+	valid.ChainID = 99999
+
+	tests := []struct {
+		name    string
+		modify  func(o *GenesisOptions)
+		wantErr bool
+	}{
+		{
+			name:    "valid options",
+			modify:  func(o *GenesisOptions) {},
+			wantErr: false,
+		},
+		{
+			name:    "missing chain ID",
+			modify:  func(o *GenesisOptions) { o.ChainID = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "C-Chain chain ID collision",
+			modify:  func(o *GenesisOptions) { o.ChainID = 43114 },
+			wantErr: true,
+		},
+		{
+			name:    "zero gas limit",
+			modify:  func(o *GenesisOptions) { o.GasLimit = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "zero target block rate",
+			modify:  func(o *GenesisOptions) { o.TargetBlockRate = 0 },
+			wantErr: true,
+		},
+		{
+			name: "duplicate allocation",
+			modify: func(o *GenesisOptions) {
+				o.Allocations = []Allocation{
+					{Address: EwoqAddress, Balance: Tokens(1)},
+					{Address: EwoqAddress, Balance: Tokens(2)},
+				}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := valid
+			tt.modify(&opts)
+			if err := opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseAllocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "whole tokens", input: EwoqAddress + "=100", wantErr: false},
+		{name: "fractional tokens", input: EwoqAddress + "=0.25", wantErr: false},
+		{name: "missing amount", input: EwoqAddress, wantErr: true},
+		{name: "invalid address", input: "0x1234=100", wantErr: true},
+		{name: "negative amount", input: EwoqAddress + "=-1", wantErr: true},
+		{name: "too many decimals", input: EwoqAddress + "=0.0000000000000000001", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseAllocation(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("ParseAllocation(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	opts := CreateOptions{
+		Name:      "mysubnet",
+		TokenName: "MYT",
+		Genesis:   DefaultGenesisOptions(),
+	}
+	opts.Genesis.ChainID = 99999
+
+	sn, err := Create(store, opts)
+	if err != nil {
+		t.Fatalf("Failed to create subnet: %v", err)
+	}
+	if sn.VM != VMSubnetEVM {
+		t.Errorf("expected default VM %s, got %s", VMSubnetEVM, sn.VM)
+	}
+
+	if _, err := os.Stat(filepath.Join(store.Dir("mysubnet"), "genesis.json")); err != nil {
+		t.Errorf("expected genesis file: %v", err)
+	}
+
+	loaded, err := store.Get("mysubnet")
+	if err != nil {
+		t.Fatalf("Failed to load subnet: %v", err)
+	}
+	if loaded.ChainID != 99999 || loaded.TokenName != "MYT" {
+		t.Errorf("unexpected stored subnet: %+v", loaded)
+	}
+
+	// Creating the same subnet again should fail
+	if _, err := Create(store, opts); err == nil {
+		t.Error("expected error creating duplicate subnet")
+	}
+
+	// Reusing the chain ID of another subnet should fail
+	opts.Name = "othersubnet"
+	if _, err := Create(store, opts); err == nil {
+		t.Error("expected error for chain ID used by another subnet")
+	}
+
+	// Invalid names should fail
+	opts.Name = "../escape"
+	opts.Genesis.ChainID = 88888
+	if _, err := Create(store, opts); err == nil {
+		t.Error("expected error for invalid subnet name")
+	}
+}
+
+func TestVMID(t *testing.T) {
+	id, err := VMID("subnetevm")
+	if err != nil {
+		t.Fatalf("Failed to compute VM ID: %v", err)
+	}
+	if id != "srEXiWaHuhNyGwPUi444Tu47ZEDwxTWrbQiuD7FmgSAQ6X7Dy" {
+		t.Errorf("unexpected subnet-evm VM ID %s", id)
+	}
+
+	if _, err := VMID("a-vm-name-that-is-longer-than-32-bytes"); err == nil {
+		t.Error("expected error for VM name longer than 32 bytes")
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	for i, name := range []string{"beta", "alpha"} {
+		opts := CreateOptions{Name: name, TokenName: "TKN", Genesis: DefaultGenesisOptions()}
+		opts.Genesis.ChainID = uint64(90000 + i)
+		if _, err := Create(store, opts); err != nil {
+			t.Fatalf("Failed to create subnet %s: %v", name, err)
+		}
+	}
+
+	subnets, err := store.List()
+	if err != nil {
+		t.Fatalf("Failed to list subnets: %v", err)
+	}
+	if len(subnets) != 2 || subnets[0].Name != "alpha" || subnets[1].Name != "beta" {
+		t.Fatalf("expected subnets [alpha beta], got %v", subnets)
+	}
+
+	// Record a deployment and read it back
+	sn := subnets[0]
+	sn.SetDeployment("local", &Deployment{
+		SubnetID:     "subnet-id",
+		BlockchainID: "blockchain-id",
+		RPCURL:       "http://localhost:9650/ext/bc/blockchain-id/rpc",
+	})
+	if err := store.Update(sn); err != nil {
+		t.Fatalf("Failed to update subnet: %v", err)
+	}
+
+	loaded, err := store.Get("alpha")
+	if err != nil {
+		t.Fatalf("Failed to load subnet: %v", err)
+	}
+	if d := loaded.Deployment("local"); d == nil || d.BlockchainID != "blockchain-id" {
+		t.Errorf("expected local deployment to be persisted, got %+v", d)
+	}
+	if loaded.Deployment("fuji") != nil {
+		t.Error("expected no fuji deployment")
+	}
+	if _, err := store.Genesis("alpha"); err != nil {
+		t.Errorf("expected genesis to survive update: %v", err)
+	}
+
+	// Delete and verify it is gone
+	if err := store.Delete("alpha"); err != nil {
+		t.Fatalf("Failed to delete subnet: %v", err)
+	}
+	if _, err := store.Get("alpha"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+	if err := store.Delete("alpha"); err == nil {
+		t.Error("expected error deleting a missing subnet")
+	}
+}
+
+func TestEwoqKey(t *testing.T) {
+	key := EwoqKey()
+	addr := key.Address()
+	if got := hex.EncodeToString(addr[:]); got != "3cb7d3842e8cee6a0ebd09f1fe884f6861e1b29c" {
+		t.Errorf("unexpected ewoq short ID %s", got)
+	}
+
+	formatted, err := FormatAddress("P", 12345, addr)
+	if err != nil {
+		t.Fatalf("Failed to format address: %v", err)
+	}
+	if formatted != "P-local18jma8ppw3nhx5r4ap8clazz0dps7rv5u00z96u" {
+		t.Errorf("unexpected ewoq P-Chain address %s", formatted)
+	}
+
+	// The [r || s || v] signature must recover to the signing key
+	hash := sha256.Sum256([]byte("kinetic"))
+	sig := key.SignHash(hash[:])
+	compact := append([]byte{sig[64] + 27}, sig[:64]...)
+	pub, _, err := ecdsa.RecoverCompact(compact, hash[:])
+	if err != nil {
+		t.Fatalf("Failed to recover public key: %v", err)
+	}
+	if !pub.IsEqual(key.priv.PubKey()) {
+		t.Error("recovered public key does not match signing key")
+	}
+}
+
+func TestParseID(t *testing.T) {
+	vmID, _ := VMID("subnetevm")
+	id, err := ParseID(vmID)
+	if err != nil {
+		t.Fatalf("Failed to parse ID: %v", err)
+	}
+	if id.String() != vmID {
+		t.Errorf("expected round trip to %s, got %s", vmID, id)
+	}
+
+	for _, s := range []string{"", "not-cb58!", vmID[:len(vmID)-1] + "1"} {
+		if _, err := ParseID(s); err == nil {
+			t.Errorf("expected error parsing %q", s)
+		}
+	}
+
+	b := []byte("payload")
+	decoded, err := decodeHex(encodeHex(b))
+	if err != nil || string(decoded) != "payload" {
+		t.Errorf("checksummed hex round trip failed: %q, %v", decoded, err)
+	}
+}
+
+// testNodeID is the node ID reported by fakePChain
+var testNodeID = ShortID{1, 2, 3}.NodeID()
+
+// fakePChain serves the node APIs used by a local deployment and records the
+// transactions issued to it
+func fakePChain(t *testing.T, cfg *config.Config) *[]string {
+	t.Helper()
+	key := EwoqKey()
+	var avaxID, fundingTx ID
+	avaxID[0], fundingTx[0] = 1, 2
+
+	p := &packer{}
+	p.u16(codecVersion)
+	p.fixed(fundingTx[:])
+	p.u32(0)
+	p.fixed(avaxID[:])
+	p.u32(typeTransferOutput)
+	p.u64(300_000_000_000_000_000)
+	OutputOwners{Threshold: 1, Addrs: []ShortID{key.Address()}}.pack(p)
+	fundedUTXO := encodeHex(p.buf.Bytes())
+
+	var issued []string
+	methods := map[string]func(params json.RawMessage) any{
+		"health.health": func(json.RawMessage) any {
+			return map[string]any{"healthy": true, "checks": map[string]any{}}
+		},
+		"info.isBootstrapped": func(json.RawMessage) any { return map[string]any{"isBootstrapped": true} },
+		"info.getNodeID":      func(json.RawMessage) any { return map[string]any{"nodeID": testNodeID} },
+		"info.getTxFee": func(json.RawMessage) any {
+			return map[string]any{"txFee": "1000000", "createSubnetTxFee": "100000000", "createBlockchainTxFee": "100000000"}
+		},
+		"platform.getStakingAssetID": func(json.RawMessage) any { return map[string]any{"assetID": avaxID.String()} },
+		"platform.getUTXOs":          func(json.RawMessage) any { return map[string]any{"utxos": []string{fundedUTXO}} },
+		"platform.getTxStatus":       func(json.RawMessage) any { return map[string]any{"status": "Committed"} },
+		"platform.getCurrentValidators": func(params json.RawMessage) any {
+			var req struct {
+				SubnetID string `json:"subnetID"`
+			}
+			json.Unmarshal(params, &req)
+			if req.SubnetID != "" {
+				return map[string]any{"validators": []any{}}
+			}
+			// The local node validates the primary network for the next day
+			now := time.Now()
+			return map[string]any{"validators": []any{map[string]any{
+				"nodeID":    testNodeID,
+				"weight":    "2000000000000",
+				"startTime": strconv.FormatInt(now.Add(-time.Hour).Unix(), 10),
+				"endTime":   strconv.FormatInt(now.Add(24*time.Hour).Unix(), 10),
+			}}}
+		},
+		"platform.issueTx": func(params json.RawMessage) any {
+			var req struct {
+				Tx string `json:"tx"`
+			}
+			json.Unmarshal(params, &req)
+			issued = append(issued, req.Tx)
+			return map[string]any{}
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		handler, ok := methods[req.Method]
+		if !ok {
+			http.Error(w, "unexpected method "+req.Method, http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": 1, "result": handler(req.Params)})
+	}))
+	t.Cleanup(server.Close)
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to parse test server address: %v", err)
+	}
+	cfg.Node.APIPort, _ = strconv.Atoi(port)
+	return &issued
+}
+
+// failingRestart is a node manager whose restarts fail
+type failingRestart struct {
+	node.Manager
+}
+
+func (failingRestart) Restart(ctx context.Context) error {
+	return errors.New("restart failed")
+}
+
+func TestDeployLocal(t *testing.T) {
+	store := NewStore(t.TempDir())
+	opts := CreateOptions{Name: "mysubnet", TokenName: "TKN", Genesis: DefaultGenesisOptions()}
+	opts.Genesis.ChainID = 99999
+	sn, err := Create(store, opts)
+	if err != nil {
+		t.Fatalf("Failed to create subnet: %v", err)
+	}
+
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(dir, "share"))
+	cfg := config.DefaultConfig()
+	cfg.Node.DBDir = filepath.Join(dir, "db")
+	cfg.Node.LogDir = filepath.Join(dir, "logs")
+	cfg.Node.StakingDir = filepath.Join(dir, "staking")
+	cfg.Node.PluginDir = filepath.Join(dir, "plugins")
+	issued := fakePChain(t, cfg)
+
+	// Pre-install the plugin so the test does not download subnet-evm
+	if err := os.MkdirAll(cfg.Node.PluginDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Node.PluginDir, sn.VMID), []byte("plugin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	store.SaveChainConfig("mysubnet", []byte("{\n  \"log-level\": \"debug\"\n}"))
+
+	runtime := system.NewFakeRuntime()
+	manager := node.NewManagerWithRuntime(cfg, runtime)
+	ctx := context.Background()
+
+	deployOpts := DeployOptions{Config: cfg, Manager: manager, Timeout: 5 * time.Second}
+	if _, err := DeployLocal(ctx, store, "mysubnet", deployOpts); err == nil {
+		t.Error("expected error deploying while the node is stopped")
+	}
+
+	if err := manager.Start(ctx, cfg); err != nil {
+		t.Fatalf("Failed to start node: %v", err)
+	}
+
+	// A deployment failing after the transactions records them, and the
+	// retry reuses them
+	failing := deployOpts
+	failing.Manager = failingRestart{manager}
+	if _, err := DeployLocal(ctx, store, "mysubnet", failing); err == nil {
+		t.Fatal("expected error when the node fails to restart")
+	}
+	unfinished, err := store.Get("mysubnet")
+	if err != nil {
+		t.Fatalf("Failed to load subnet: %v", err)
+	}
+	pending := unfinished.PendingDeployment("local")
+	if pending == nil || pending.SubnetID == "" || pending.BlockchainID == "" || unfinished.Deployment("local") != nil {
+		t.Fatalf("expected the unfinished deployment to be recorded, got %+v", unfinished)
+	}
+
+	d, err := DeployLocal(ctx, store, "mysubnet", deployOpts)
+	if err != nil {
+		t.Fatalf("Failed to deploy subnet: %v", err)
+	}
+
+	if len(*issued) != 2 {
+		t.Fatalf("expected CreateSubnetTx and CreateChainTx to be issued once, got %d txs", len(*issued))
+	}
+	if d.SubnetID != pending.SubnetID || d.BlockchainID != pending.BlockchainID {
+		t.Errorf("retry deployed %s/%s, want the pending %s/%s", d.SubnetID, d.BlockchainID, pending.SubnetID, pending.BlockchainID)
+	}
+	chainTx, err := decodeHex((*issued)[1])
+	if err != nil {
+		t.Fatalf("Failed to decode issued tx: %v", err)
+	}
+	genesis, _ := store.Genesis("mysubnet")
+	if !bytes.Contains(chainTx, genesis) {
+		t.Error("expected CreateChainTx to carry the subnet genesis")
+	}
+	if txID := sha256.Sum256(chainTx); ID(txID).String() != d.BlockchainID {
+		t.Errorf("expected blockchain ID to be the CreateChainTx ID, got %s", d.BlockchainID)
+	}
+
+	// Unset node directories default to the container's node directory
+	if want := filepath.Join(dir, "share", "kinetic", "node", cfg.Docker.ContainerName, "configs", "chains"); cfg.Node.ChainConfigDir != want {
+		t.Errorf("chain config dir = %s, want %s", cfg.Node.ChainConfigDir, want)
+	}
+	installed, err := os.ReadFile(filepath.Join(cfg.Node.ChainConfigDir, d.BlockchainID, "config.json"))
+	if err != nil || !bytes.Contains(installed, []byte(`"log-level"`)) {
+		t.Errorf("expected chain config to be installed before the restart, got %s: %v", installed, err)
+	}
+
+	if len(cfg.Node.TrackSubnets) != 1 || cfg.Node.TrackSubnets[0] != d.SubnetID {
+		t.Errorf("expected node to track %s, got %v", d.SubnetID, cfg.Node.TrackSubnets)
+	}
+	container := runtime.Containers[cfg.Docker.ContainerName]
+	if container == nil || !container.Running {
+		t.Fatal("expected node container to be running after restart")
+	}
+	if !slices.Contains(container.Config.Cmd, "--track-subnets="+d.SubnetID) {
+		t.Errorf("expected restarted node to track the subnet, got %v", container.Config.Cmd)
+	}
+
+	wantRPC := fmt.Sprintf("http://localhost:%d/ext/bc/%s/rpc", cfg.Node.APIPort, d.BlockchainID)
+	if d.RPCURL != wantRPC || len(d.Validators) != 1 || d.Validators[0] != testNodeID {
+		t.Errorf("unexpected deployment %+v", d)
+	}
+
+	loaded, err := store.Get("mysubnet")
+	if err != nil {
+		t.Fatalf("Failed to load subnet: %v", err)
+	}
+	if loaded.Deployment("local") == nil || loaded.PendingDeployment("local") != nil {
+		t.Error("expected local deployment to be recorded and no longer pending")
+	}
+	if _, err := DeployLocal(ctx, store, "mysubnet", deployOpts); err == nil {
+		t.Error("expected error deploying an already deployed subnet")
+	}
+}
+
+func TestPrecompilesValidate(t *testing.T) {
+	const other = "0x0000000000000000000000000000000000000001"
+
+	tests := []struct {
+		name    string
+		p       Precompiles
+		wantErr bool
+	}{
+		{
+			name: "valid allow lists",
+			p: Precompiles{
+				TxAllowList:  &AllowList{Admins: []string{EwoqAddress}, Enabled: []string{other}},
+				NativeMinter: &AllowList{Admins: []string{EwoqAddress}},
+			},
+		},
+		{
+			name:    "empty allow list",
+			p:       Precompiles{FeeManager: &AllowList{}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid address",
+			p:       Precompiles{DeployerAllowList: &AllowList{Admins: []string{"0x1234"}}},
+			wantErr: true,
+		},
+		{
+			name:    "address is admin and enabled",
+			p:       Precompiles{DeployerAllowList: &AllowList{Admins: []string{EwoqAddress}, Enabled: []string{EwoqAddress}}},
+			wantErr: true,
+		},
+		{
+			name: "admin blocked by tx allow list",
+			p: Precompiles{
+				TxAllowList:  &AllowList{Admins: []string{EwoqAddress}},
+				NativeMinter: &AllowList{Admins: []string{other}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "reward address without reward manager",
+			p:       Precompiles{RewardAddress: EwoqAddress},
+			wantErr: true,
+		},
+		{
+			name: "reward address with fee recipients",
+			p: Precompiles{
+				RewardManager:      &AllowList{Admins: []string{EwoqAddress}},
+				RewardAddress:      other,
+				AllowFeeRecipients: true,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.p.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewGenesisPrecompiles(t *testing.T) {
+	opts := DefaultGenesisOptions()
+	opts.ChainID = 99999
+	opts.Precompiles = Precompiles{
+		TxAllowList:   &AllowList{Admins: []string{EwoqAddress}},
+		RewardManager: &AllowList{Admins: []string{EwoqAddress}},
+		RewardAddress: EwoqAddress,
+	}
+
+	genesis, err := NewGenesis(opts)
+	if err != nil {
+		t.Fatalf("Failed to create genesis: %v", err)
+	}
+	data, err := genesis.Marshal()
+	if err != nil {
+		t.Fatalf("Failed to marshal genesis: %v", err)
+	}
+
+	var decoded struct {
+		Config map[string]json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to decode genesis: %v", err)
+	}
+	if _, ok := decoded.Config["contractNativeMinterConfig"]; ok {
+		t.Error("expected disabled precompiles to be omitted")
+	}
+
+	var txAllowList AllowListConfig
+	if err := json.Unmarshal(decoded.Config["txAllowListConfig"], &txAllowList); err != nil {
+		t.Fatalf("Failed to decode txAllowListConfig: %v", err)
+	}
+	if len(txAllowList.AdminAddresses) != 1 || txAllowList.AdminAddresses[0] != EwoqAddress {
+		t.Errorf("unexpected txAllowListConfig %+v", txAllowList)
+	}
+
+	var rewardManager RewardManagerConfig
+	if err := json.Unmarshal(decoded.Config["rewardManagerConfig"], &rewardManager); err != nil {
+		t.Fatalf("Failed to decode rewardManagerConfig: %v", err)
+	}
+	if rewardManager.InitialRewardConfig == nil || rewardManager.InitialRewardConfig.RewardAddress != EwoqAddress {
+		t.Errorf("unexpected rewardManagerConfig %+v", rewardManager)
+	}
+}
+
+func TestValidateUpgrades(t *testing.T) {
+	opts := DefaultGenesisOptions()
+	opts.ChainID = 99999
+	opts.Precompiles.TxAllowList = &AllowList{Admins: []string{EwoqAddress}}
+	g, err := NewGenesis(opts)
+	if err != nil {
+		t.Fatalf("Failed to create genesis: %v", err)
+	}
+	genesis, _ := g.Marshal()
+
+	upgrade := func(name string, at uint64, disable bool) PrecompileUpgrade {
+		entry := &UpgradeEntry{BlockTimestamp: at, Disable: disable}
+		if !disable {
+			entry.AdminAddresses = []string{EwoqAddress}
+		}
+		u, err := NewPrecompileUpgrade(name, entry)
+		if err != nil {
+			t.Fatalf("Failed to create upgrade: %v", err)
+		}
+		return u
+	}
+
+	tests := []struct {
+		name     string
+		upgrades []PrecompileUpgrade
+		wantErr  bool
+	}{
+		{
+			name: "enable then disable",
+			upgrades: []PrecompileUpgrade{
+				upgrade(PrecompileNativeMinter, 100, false),
+				upgrade(PrecompileTxAllowList, 200, true),
+				upgrade(PrecompileNativeMinter, 300, true),
+				upgrade(PrecompileNativeMinter, 400, false),
+			},
+		},
+		{
+			name:     "enable precompile active at genesis",
+			upgrades: []PrecompileUpgrade{upgrade(PrecompileTxAllowList, 100, false)},
+			wantErr:  true,
+		},
+		{
+			name:     "disable inactive precompile",
+			upgrades: []PrecompileUpgrade{upgrade(PrecompileFeeManager, 100, true)},
+			wantErr:  true,
+		},
+		{
+			name:     "activation at genesis",
+			upgrades: []PrecompileUpgrade{upgrade(PrecompileNativeMinter, 0, false)},
+			wantErr:  true,
+		},
+		{
+			name: "timestamps out of order",
+			upgrades: []PrecompileUpgrade{
+				upgrade(PrecompileNativeMinter, 200, false),
+				upgrade(PrecompileFeeManager, 100, false),
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate activation",
+			upgrades: []PrecompileUpgrade{
+				upgrade(PrecompileNativeMinter, 100, false),
+				upgrade(PrecompileNativeMinter, 100, true),
+			},
+			wantErr: true,
+		},
+		{
+			name: "fee config on another precompile",
+			upgrades: []PrecompileUpgrade{{"contractNativeMinterConfig": {
+				BlockTimestamp:   100,
+				AdminAddresses:   []string{EwoqAddress},
+				InitialFeeConfig: &FeeConfig{GasLimit: 1, TargetGas: 1, TargetBlockRate: 1},
+			}}},
+			wantErr: true,
+		},
+		{
+			name:     "unknown precompile",
+			upgrades: []PrecompileUpgrade{{"unknownConfig": {BlockTimestamp: 100}}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpgrades(genesis, &UpgradeConfig{PrecompileUpgrades: tt.upgrades})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpgrades() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddUpgrade(t *testing.T) {
+	store := NewStore(t.TempDir())
+	opts := CreateOptions{Name: "mysubnet", TokenName: "TKN", Genesis: DefaultGenesisOptions()}
+	opts.Genesis.ChainID = 99999
+	if _, err := Create(store, opts); err != nil {
+		t.Fatalf("Failed to create subnet: %v", err)
+	}
+
+	now := time.Now()
+	at, err := ParseActivationTime("+10m", now)
+	if err != nil {
+		t.Fatalf("Failed to parse activation time: %v", err)
+	}
+	minter, _ := NewPrecompileUpgrade(PrecompileNativeMinter, &UpgradeEntry{
+		BlockTimestamp: uint64(at.Unix()),
+		AdminAddresses: []string{EwoqAddress},
+	})
+	if _, err := AddUpgrade(store, "mysubnet", minter, now); err != nil {
+		t.Fatalf("Failed to add upgrade: %v", err)
+	}
+
+	past, _ := NewPrecompileUpgrade(PrecompileNativeMinter, &UpgradeEntry{BlockTimestamp: uint64(now.Add(-time.Minute).Unix()), Disable: true})
+	if _, err := AddUpgrade(store, "mysubnet", past, now); err == nil {
+		t.Error("expected error for an activation in the past")
+	}
+	again, _ := NewPrecompileUpgrade(PrecompileNativeMinter, &UpgradeEntry{BlockTimestamp: uint64(at.Add(time.Hour).Unix()), AdminAddresses: []string{EwoqAddress}})
+	if _, err := AddUpgrade(store, "mysubnet", again, now); err == nil {
+		t.Error("expected error enabling an already enabled precompile")
+	}
+
+	upgrades, err := store.Upgrades("mysubnet")
+	if err != nil {
+		t.Fatalf("Failed to load upgrades: %v", err)
+	}
+	if len(upgrades.PrecompileUpgrades) != 1 {
+		t.Fatalf("expected rejected upgrades not to be saved, got %d", len(upgrades.PrecompileUpgrades))
+	}
+
+	// Installing requires a local deployment
+	cfg := config.DefaultConfig()
+	cfg.Node.PluginDir = filepath.Join(t.TempDir(), "plugins")
+	cfg.Node.ChainConfigDir = filepath.Join(t.TempDir(), "chains")
+	if _, err := InstallUpgrades(store, "mysubnet", cfg); err == nil {
+		t.Error("expected error installing upgrades for an undeployed subnet")
+	}
+
+	sn, _ := store.Get("mysubnet")
+	sn.SetDeployment("local", &Deployment{BlockchainID: "chain"})
+	store.Update(sn)
+	path, err := InstallUpgrades(store, "mysubnet", cfg)
+	if err != nil {
+		t.Fatalf("Failed to install upgrades: %v", err)
+	}
+	if path != filepath.Join(cfg.Node.ChainConfigDir, "chain", "upgrade.json") {
+		t.Errorf("unexpected install path %s", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.Contains(data, []byte(`"contractNativeMinterConfig"`)) {
+		t.Errorf("unexpected installed upgrades %s: %v", data, err)
+	}
+}
+
+func TestCreateCustom(t *testing.T) {
+	store := NewStore(t.TempDir())
+	dir := t.TempDir()
+
+	genesisPath := filepath.Join(dir, "genesis.json")
+	genesis := []byte(`{"config":{"chainId":424242},"custom":"kept as is"}`)
+	os.WriteFile(genesisPath, genesis, 0644)
+	binary := filepath.Join(dir, "myvm")
+	os.WriteFile(binary, []byte("#!/bin/sh\n"), 0755)
+
+	opts := CreateOptions{
+		Name:     "myvm",
+		VM:       VMCustom,
+		CustomVM: CustomVMOptions{BinaryPath: binary, GenesisPath: genesisPath},
+	}
+	sn, err := Create(store, opts)
+	if err != nil {
+		t.Fatalf("Failed to create custom subnet: %v", err)
+	}
+	wantID, _ := VMID("myvm")
+	if sn.VMID != wantID || sn.VMName != "myvm" || sn.ChainID != 424242 {
+		t.Errorf("unexpected custom subnet %+v", sn)
+	}
+	if stored, _ := store.Genesis("myvm"); !bytes.Equal(stored, genesis) {
+		t.Errorf("expected genesis to be stored verbatim, got %s", stored)
+	}
+
+	pluginDir := filepath.Join(dir, "plugins")
+	if err := installCustomVM(store, sn, pluginDir); err != nil {
+		t.Fatalf("Failed to install VM: %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(pluginDir, wantID)); err != nil || info.Mode()&0100 == 0 {
+		t.Errorf("expected executable plugin named by VM ID: %v", err)
+	}
+
+	invalid := []CreateOptions{
+		{Name: "other", VM: VMCustom, CustomVM: CustomVMOptions{Name: "myvm", BinaryPath: binary, GenesisPath: genesisPath}},
+		{Name: "nogenesis", VM: VMCustom, CustomVM: CustomVMOptions{BinaryPath: binary}},
+		{Name: "nobinary", VM: VMCustom, CustomVM: CustomVMOptions{GenesisPath: genesisPath}},
+		{Name: "both", VM: VMCustom, CustomVM: CustomVMOptions{BinaryPath: binary, Module: dir, GenesisPath: genesisPath}},
+		{Name: "reserved", VM: VMCustom, CustomVM: CustomVMOptions{Name: "subnetevm", BinaryPath: binary, GenesisPath: genesisPath}},
+	}
+	for _, opts := range invalid {
+		if _, err := Create(store, opts); err == nil {
+			t.Errorf("expected error creating %s", opts.Name)
+		}
+	}
+}
+
+func TestCreateCustomFromModule(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a Go binary")
+	}
+	store := NewStore(t.TempDir())
+	src := t.TempDir()
+	os.WriteFile(filepath.Join(src, "go.mod"), []byte("module example.com/myvm\n\ngo 1.21\n"), 0644)
+	os.WriteFile(filepath.Join(src, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	genesisPath := filepath.Join(src, "genesis.json")
+	os.WriteFile(genesisPath, []byte(`{}`), 0644)
+
+	sn, err := Create(store, CreateOptions{
+		Name:     "built",
+		VM:       VMCustom,
+		CustomVM: CustomVMOptions{Module: src, GenesisPath: genesisPath},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create subnet from module: %v", err)
+	}
+	if _, err := os.Stat(store.VMBinaryPath(sn.Name)); err != nil {
+		t.Errorf("expected built VM binary: %v", err)
+	}
+}
+
+func TestParseNodeID(t *testing.T) {
+	id, err := ParseNodeID(testNodeID)
+	if err != nil {
+		t.Fatalf("Failed to parse node ID: %v", err)
+	}
+	if id.NodeID() != testNodeID {
+		t.Errorf("expected round trip to %s, got %s", testNodeID, id.NodeID())
+	}
+
+	vmID, _ := VMID("subnetevm")
+	for _, s := range []string{"", "NodeID-", strings.TrimPrefix(testNodeID, "NodeID-"), "NodeID-" + vmID} {
+		if _, err := ParseNodeID(s); err == nil {
+			t.Errorf("expected error parsing %q", s)
+		}
+	}
+}
+
+func TestValidators(t *testing.T) {
+	store := NewStore(t.TempDir())
+	opts := CreateOptions{Name: "mysubnet", TokenName: "TKN", Genesis: DefaultGenesisOptions()}
+	opts.Genesis.ChainID = 99999
+	sn, err := Create(store, opts)
+	if err != nil {
+		t.Fatalf("Failed to create subnet: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	issued := fakePChain(t, cfg)
+	ctx := context.Background()
+	vopts := ValidatorOptions{Config: cfg, NodeIDs: []string{testNodeID}}
+
+	if _, err := AddValidators(ctx, store, "mysubnet", vopts); err == nil {
+		t.Error("expected error for a subnet that is not deployed")
+	}
+	if _, err := AddValidators(ctx, store, "mysubnet", ValidatorOptions{Config: cfg}); !errors.Is(err, ErrNoNodeIDs) {
+		t.Errorf("expected ErrNoNodeIDs adding without node IDs, got %v", err)
+	}
+	if _, err := RemoveValidators(ctx, store, "mysubnet", ValidatorOptions{Config: cfg}); !errors.Is(err, ErrNoNodeIDs) {
+		t.Errorf("expected ErrNoNodeIDs removing without node IDs, got %v", err)
+	}
+
+	var subnetID ID
+	subnetID[0] = 9
+	sn.SetDeployment("local", &Deployment{SubnetID: subnetID.String(), BlockchainID: "chain"})
+	store.Update(sn)
+
+	added, err := AddValidators(ctx, store, "mysubnet", vopts)
+	if err != nil {
+		t.Fatalf("Failed to add validators: %v", err)
+	}
+	if len(added) != 1 || added[0].NodeID != testNodeID || added[0].Weight != DefaultValidatorWeight {
+		t.Fatalf("unexpected validators added %+v", added)
+	}
+	if len(*issued) != 1 {
+		t.Fatalf("expected one AddSubnetValidatorTx, got %d txs", len(*issued))
+	}
+	tx, _ := decodeHex((*issued)[0])
+	nodeID, _ := ParseNodeID(testNodeID)
+	if !bytes.Contains(tx, nodeID[:]) || !bytes.Contains(tx, subnetID[:]) {
+		t.Error("expected AddSubnetValidatorTx to reference the node and subnet")
+	}
+
+	loaded, _ := store.Get("mysubnet")
+	if v := loaded.Deployment("local").Validators; len(v) != 1 || v[0] != testNodeID {
+		t.Errorf("expected validator to be recorded, got %v", v)
+	}
+
+	// Periods must lie within the primary network validation
+	late := vopts
+	late.End = time.Now().Add(48 * time.Hour)
+	if _, err := AddValidators(ctx, store, "mysubnet", late); err == nil {
+		t.Error("expected error for an end time after primary network validation")
+	}
+	unknown := vopts
+	unknown.NodeIDs = []string{ShortID{4}.NodeID()}
+	if _, err := AddValidators(ctx, store, "mysubnet", unknown); err == nil {
+		t.Error("expected error for a node that does not validate the primary network")
+	}
+
+	removed, err := RemoveValidators(ctx, store, "mysubnet", vopts)
+	if err != nil {
+		t.Fatalf("Failed to remove validators: %v", err)
+	}
+	if len(removed) != 1 || len(*issued) != 2 {
+		t.Errorf("expected one RemoveSubnetValidatorTx, got removed %v and %d txs", removed, len(*issued))
+	}
+	loaded, _ = store.Get("mysubnet")
+	if v := loaded.Deployment("local").Validators; len(v) != 0 {
+		t.Errorf("expected validator to be removed, got %v", v)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	src := NewStore(t.TempDir())
+	opts := CreateOptions{Name: "mysubnet", TokenName: "TKN", Genesis: DefaultGenesisOptions()}
+	opts.Genesis.ChainID = 99999
+	sn, err := Create(src, opts)
+	if err != nil {
+		t.Fatalf("Failed to create subnet: %v", err)
+	}
+	minter, _ := NewPrecompileUpgrade(PrecompileNativeMinter, &UpgradeEntry{
+		BlockTimestamp: uint64(time.Now().Add(time.Hour).Unix()),
+		AdminAddresses: []string{EwoqAddress},
+	})
+	if _, err := AddUpgrade(src, "mysubnet", minter, time.Now()); err != nil {
+		t.Fatalf("Failed to add upgrade: %v", err)
+	}
+	src.SaveChainConfig("mysubnet", []byte("{\n  \"pruning-enabled\": false\n}"))
+	sn.SetDeployment("local", &Deployment{SubnetID: "subnet", BlockchainID: "chain"})
+	src.Update(sn)
+
+	bundle, err := Export(src, "mysubnet")
+	if err != nil {
+		t.Fatalf("Failed to export subnet: %v", err)
+	}
+	data, _ := json.Marshal(bundle)
+	if bytes.Contains(data, []byte("deployments")) || bytes.Contains(data, []byte("created_at")) {
+		t.Errorf("expected bundle to be network independent, got %s", data)
+	}
+
+	parsed, err := ParseBundle(data)
+	if err != nil {
+		t.Fatalf("Failed to parse bundle: %v", err)
+	}
+	dst := NewStore(t.TempDir())
+	imported, err := Import(dst, parsed, ImportOptions{})
+	if err != nil {
+		t.Fatalf("Failed to import subnet: %v", err)
+	}
+	if imported.VMID != sn.VMID || imported.ChainID != sn.ChainID || imported.Deployment("local") != nil {
+		t.Errorf("unexpected imported subnet %+v", imported)
+	}
+
+	srcGenesis, _ := src.Genesis("mysubnet")
+	dstGenesis, _ := dst.Genesis("mysubnet")
+	if !bytes.Equal(srcGenesis, dstGenesis) {
+		t.Error("expected genesis to be recreated identically")
+	}
+	if upgrades, _ := dst.Upgrades("mysubnet"); len(upgrades.PrecompileUpgrades) != 1 {
+		t.Errorf("expected upgrades to be imported, got %+v", upgrades)
+	}
+	if chainConfig, _ := dst.ChainConfig("mysubnet"); string(chainConfig) != "{\n  \"pruning-enabled\": false\n}" {
+		t.Errorf("unexpected imported chain config %s", chainConfig)
+	}
+
+	if _, err := Import(dst, parsed, ImportOptions{}); err == nil {
+		t.Error("expected error importing an existing subnet")
+	}
+	if _, err := Import(dst, parsed, ImportOptions{Name: "copy"}); err == nil {
+		t.Error("expected error importing a colliding chain ID")
+	}
+
+	for _, raw := range []string{`{"name":"x"}`, `{"schema_version":99,"name":"x"}`, `not json`} {
+		if _, err := ParseBundle([]byte(raw)); err == nil {
+			t.Errorf("expected error parsing bundle %s", raw)
+		}
+	}
+}
+
+func TestImportChecks(t *testing.T) {
+	src := NewStore(t.TempDir())
+	opts := CreateOptions{Name: "mysubnet", TokenName: "TKN", Genesis: DefaultGenesisOptions()}
+	opts.Genesis.ChainID = 99999
+	if _, err := Create(src, opts); err != nil {
+		t.Fatalf("Failed to create subnet: %v", err)
+	}
+	exported, err := Export(src, "mysubnet")
+	if err != nil {
+		t.Fatalf("Failed to export subnet: %v", err)
+	}
+
+	// withChainID returns the bundle with the genesis chain ID replaced and
+	// the bundle's own chain ID set to bundleChainID
+	withChainID := func(genesisChainID, bundleChainID uint64) *Bundle {
+		var g map[string]any
+		json.Unmarshal(exported.Genesis, &g)
+		g["config"].(map[string]any)["chainId"] = genesisChainID
+		data, _ := json.Marshal(g)
+		b := *exported
+		b.Genesis, _ = indentJSON(data)
+		b.ChainID = bundleChainID
+		return &b
+	}
+
+	existing := NewStore(t.TempDir())
+	opts.Name = "existing"
+	opts.Genesis.ChainID = 88888
+	if _, err := Create(existing, opts); err != nil {
+		t.Fatalf("Failed to create subnet: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		store   *Store
+		bundle  *Bundle
+		want    uint64
+		wantErr string
+	}{
+		{name: "genesis chain ID", store: NewStore(t.TempDir()), bundle: withChainID(77777, 0), want: 77777},
+		{name: "known chain ID", store: NewStore(t.TempDir()), bundle: withChainID(43114, 0), wantErr: "chain ID 43114 is already used by Avalanche C-Chain"},
+		{name: "bundle chain ID differs", store: NewStore(t.TempDir()), bundle: withChainID(43114, 99999), wantErr: "bundle chain ID 99999 does not match the genesis chain ID 43114"},
+		{name: "local collision", store: existing, bundle: withChainID(88888, 0), wantErr: "chain ID 88888 is already used by subnet existing"},
+		{name: "no chain ID", store: NewStore(t.TempDir()), bundle: withChainID(0, 0), wantErr: "subnet-evm genesis has no chain ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sn, err := Import(tt.store, tt.bundle, ImportOptions{})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Import() error = %v, want %q", err, tt.wantErr)
+				}
+				if tt.store.Exists("mysubnet") {
+					t.Error("expected a rejected bundle not to be stored")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if sn.ChainID != tt.want {
+				t.Errorf("imported chain ID = %d, want %d", sn.ChainID, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportCustomVM(t *testing.T) {
+	dir := t.TempDir()
+	genesisPath := filepath.Join(dir, "genesis.bin")
+	os.WriteFile(genesisPath, []byte{0x00, 0xff, 0x10}, 0644)
+	binary := filepath.Join(dir, "myvm")
+	os.WriteFile(binary, []byte("#!/bin/sh\n"), 0755)
+
+	src := NewStore(t.TempDir())
+	_, err := Create(src, CreateOptions{
+		Name:     "myvm",
+		VM:       VMCustom,
+		CustomVM: CustomVMOptions{BinaryPath: binary, GenesisPath: genesisPath},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create custom subnet: %v", err)
+	}
+	bundle, err := Export(src, "myvm")
+	if err != nil {
+		t.Fatalf("Failed to export subnet: %v", err)
+	}
+	if bundle.GenesisBase64 == "" || bundle.VMChecksum == "" {
+		t.Fatalf("expected binary genesis and VM checksum in bundle, got %+v", bundle)
+	}
+
+	dst := NewStore(t.TempDir())
+	if _, err := Import(dst, bundle, ImportOptions{}); err == nil {
+		t.Error("expected error importing a custom VM without its binary")
+	}
+	other := filepath.Join(dir, "other")
+	os.WriteFile(other, []byte("different"), 0755)
+	if _, err := Import(dst, bundle, ImportOptions{VMBinary: other}); err == nil {
+		t.Error("expected error importing a VM binary with a different checksum")
+	}
+	if _, err := Import(dst, bundle, ImportOptions{VMBinary: binary}); err != nil {
+		t.Fatalf("Failed to import custom subnet: %v", err)
+	}
+	if genesis, _ := dst.Genesis("myvm"); !bytes.Equal(genesis, []byte{0x00, 0xff, 0x10}) {
+		t.Errorf("expected binary genesis to round trip, got %x", genesis)
+	}
+
+	// A second subnet with the same VM would overwrite its plugin
+	_, err = Import(dst, bundle, ImportOptions{Name: "copy", VMBinary: binary})
+	if err == nil || err.Error() != "VM name myvm is already used by subnet myvm" {
+		t.Errorf("expected VM ID collision error, got %v", err)
+	}
+}
+
+func TestParseConfigValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		vm      string
+		key     string
+		value   string
+		force   bool
+		want    any
+		wantErr bool
+	}{
+		{name: "bool", vm: VMSubnetEVM, key: "pruning-enabled", value: "false", want: false},
+		{name: "invalid bool", vm: VMSubnetEVM, key: "pruning-enabled", value: "no", wantErr: true},
+		{name: "uint", vm: VMSubnetEVM, key: "rpc-gas-cap", value: "50000000", want: uint64(50000000)},
+		{name: "negative uint", vm: VMSubnetEVM, key: "rpc-gas-cap", value: "-1", wantErr: true},
+		{name: "float", vm: VMSubnetEVM, key: "rpc-tx-fee-cap", value: "100.5", want: 100.5},
+		{name: "duration", vm: VMSubnetEVM, key: "api-max-duration", value: "30s", want: "30s"},
+		{name: "invalid duration", vm: VMSubnetEVM, key: "api-max-duration", value: "30", wantErr: true},
+		{name: "list", vm: VMSubnetEVM, key: "eth-apis", value: "eth, net,,web3", want: []string{"eth", "net", "web3"}},
+		{name: "enum", vm: VMSubnetEVM, key: "log-level", value: "debug", want: "debug"},
+		{name: "invalid enum", vm: VMSubnetEVM, key: "log-level", value: "verbose", wantErr: true},
+		{name: "address", vm: VMSubnetEVM, key: "feeRecipient", value: EwoqAddress, want: EwoqAddress},
+		{name: "invalid address", vm: VMSubnetEVM, key: "feeRecipient", value: "0x1234", wantErr: true},
+		{name: "unknown key", vm: VMSubnetEVM, key: "no-such-key", value: "1", wantErr: true},
+		{name: "forced unknown key", vm: VMSubnetEVM, key: "no-such-key", value: "1", force: true, want: float64(1)},
+		{name: "custom VM JSON", vm: VMCustom, key: "limits", value: `{"max":3}`, want: map[string]any{"max": float64(3)}},
+		{name: "custom VM string", vm: VMCustom, key: "mode", value: "fast", want: "fast"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseConfigValue(tt.vm, tt.key, tt.value, tt.force)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConfigValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("ParseConfigValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateChainConfig(t *testing.T) {
+	store := NewStore(t.TempDir())
+	opts := CreateOptions{Name: "mysubnet", TokenName: "TKN", Genesis: DefaultGenesisOptions()}
+	opts.Genesis.ChainID = 99999
+	if _, err := Create(store, opts); err != nil {
+		t.Fatalf("Failed to create subnet: %v", err)
+	}
+
+	values, changed, err := UpdateChainConfig(store, "mysubnet", map[string]any{"pruning-enabled": false, "rpc-gas-cap": uint64(100)}, nil)
+	if err != nil || !changed {
+		t.Fatalf("Failed to set chain config: changed %v, %v", changed, err)
+	}
+	if len(values) != 2 {
+		t.Errorf("unexpected chain config %v", values)
+	}
+
+	// Setting the same values again is not a change
+	if _, changed, err := UpdateChainConfig(store, "mysubnet", map[string]any{"rpc-gas-cap": uint64(100)}, nil); err != nil || changed {
+		t.Errorf("expected no change, got changed %v, %v", changed, err)
+	}
+
+	values, changed, err = UpdateChainConfig(store, "mysubnet", nil, []string{"rpc-gas-cap", "log-level"})
+	if err != nil || !changed {
+		t.Fatalf("Failed to unset chain config: changed %v, %v", changed, err)
+	}
+	if _, ok := values["rpc-gas-cap"]; ok || len(values) != 1 {
+		t.Errorf("unexpected chain config %v", values)
+	}
+	data, _ := store.ChainConfig("mysubnet")
+	if string(data) != "{\n  \"pruning-enabled\": false\n}" {
+		t.Errorf("unexpected stored chain config %s", data)
+	}
+
+	if _, _, err := UpdateChainConfig(store, "missing", nil, []string{"log-level"}); err == nil {
+		t.Error("expected error for a missing subnet")
+	}
+
+	// Installing requires a local deployment
+	cfg := config.DefaultConfig()
+	cfg.Node.PluginDir = filepath.Join(t.TempDir(), "plugins")
+	cfg.Node.ChainConfigDir = filepath.Join(t.TempDir(), "chains")
+	if _, err := InstallChainConfig(store, "mysubnet", cfg); err == nil {
+		t.Error("expected error installing chain config for an undeployed subnet")
+	}
+
+	sn, _ := store.Get("mysubnet")
+	sn.SetDeployment("local", &Deployment{BlockchainID: "chain"})
+	store.Update(sn)
+	path, err := InstallChainConfig(store, "mysubnet", cfg)
+	if err != nil {
+		t.Fatalf("Failed to install chain config: %v", err)
+	}
+	if path != filepath.Join(cfg.Node.ChainConfigDir, "chain", "config.json") {
+		t.Errorf("unexpected install path %s", path)
+	}
+	if installed, err := os.ReadFile(path); err != nil || !bytes.Equal(installed, data) {
+		t.Errorf("unexpected installed chain config %s: %v", installed, err)
+	}
+}
+
+func TestEVMAddress(t *testing.T) {
+	if got := EwoqKey().EVMAddress(); !strings.EqualFold(got, EwoqAddress) {
+		t.Errorf("EVMAddress() = %s, want %s", got, EwoqAddress)
+	}
+}
+
+func TestRLPEncode(t *testing.T) {
+	tests := []struct {
+		name string
+		item any
+		want string
+	}{
+		{name: "empty string", item: []byte{}, want: "80"},
+		{name: "single byte", item: []byte{0x0f}, want: "0f"},
+		{name: "string", item: []byte("dog"), want: "83646f67"},
+		{name: "zero", item: uint64(0), want: "80"},
+		{name: "integer", item: uint64(1024), want: "820400"},
+		{name: "empty list", item: rlpList{}, want: "c0"},
+		{name: "list", item: rlpList{[]byte("cat"), []byte("dog")}, want: "c88363617483646f67"},
+		{name: "long string", item: bytes.Repeat([]byte{'a'}, 56), want: "b838" + strings.Repeat("61", 56)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hex.EncodeToString(rlpEncode(tt.item)); got != tt.want {
+				t.Errorf("rlpEncode() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDynamicFeeTxSign(t *testing.T) {
+	key := EwoqKey()
+	tx := &dynamicFeeTx{
+		ChainID:   big.NewInt(99999),
+		Nonce:     3,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2),
+		Gas:       21000,
+		To:        make([]byte, 20),
+		Value:     big.NewInt(0),
+	}
+	raw, hash := tx.sign(key)
+	if raw[0] != 0x02 || !bytes.Equal(hash, keccak256(raw)) {
+		t.Fatalf("unexpected signed tx %x", raw)
+	}
+
+	// The signature must recover to the signer over the type-prefixed hash
+	sigHash := keccak256([]byte{0x02}, rlpEncode(tx.fields()))
+	sig := key.SignHash(sigHash)
+	compact := append([]byte{sig[64] + 27}, sig[:64]...)
+	pub, _, err := ecdsa.RecoverCompact(compact, sigHash)
+	if err != nil {
+		t.Fatalf("Failed to recover signer: %v", err)
+	}
+	if addr := "0x" + hex.EncodeToString(keccak256(pub.SerializeUncompressed()[1:])[12:]); addr != key.EVMAddress() {
+		t.Errorf("recovered %s, want %s", addr, key.EVMAddress())
+	}
+}
+
+func TestCreateWarp(t *testing.T) {
+	store := NewStore(t.TempDir())
+	opts := CreateOptions{Name: "mysubnet", TokenName: "TKN", Genesis: DefaultGenesisOptions()}
+	opts.Genesis.ChainID = 99999
+	opts.Genesis.Warp = true
+	if _, err := Create(store, opts); err != nil {
+		t.Fatalf("Failed to create subnet: %v", err)
+	}
+
+	genesis, _ := store.Genesis("mysubnet")
+	if !WarpEnabled(genesis) || !bytes.Contains(genesis, []byte(`"quorumNumerator": 67`)) {
+		t.Errorf("expected warp config in genesis, got %s", genesis)
+	}
+	values, err := LoadChainConfig(store, "mysubnet")
+	if err != nil || values["warp-api-enabled"] != true {
+		t.Errorf("expected warp API to be enabled, got %v: %v", values, err)
+	}
+}
+
+func TestRelayer(t *testing.T) {
+	store := NewStore(t.TempDir())
+	for i, name := range []string{"source", "dest", "nowarp"} {
+		opts := CreateOptions{Name: name, TokenName: "TKN", Genesis: DefaultGenesisOptions()}
+		opts.Genesis.ChainID = uint64(99990 + i)
+		opts.Genesis.Warp = name != "nowarp"
+		sn, err := Create(store, opts)
+		if err != nil {
+			t.Fatalf("Failed to create subnet: %v", err)
+		}
+		sn.SetDeployment("local", &Deployment{SubnetID: name + "-subnet", BlockchainID: name + "-chain"})
+		store.Update(sn)
+	}
+
+	sender := "0x00000000000000000000000000000000000000aa"
+	var messageID [32]byte
+	messageID[0] = 7
+	unsigned := []byte("unsigned warp message")
+	signed := append(append([]byte{}, unsigned...), "sig"...)
+
+	data := make([]byte, 64)
+	data[31] = 0x20
+	data[63] = byte(len(unsigned))
+	data = append(data, unsigned...)
+	data = append(data, make([]byte, 32-len(unsigned)%32)...)
+	warpLog := map[string]any{
+		"address":         WarpPrecompileAddress,
+		"topics":          []string{sendWarpMessageTopic, "0x" + strings.Repeat("0", 24) + sender[2:], "0x" + hex.EncodeToString(messageID[:])},
+		"data":            "0x" + hex.EncodeToString(data),
+		"blockNumber":     "0x3",
+		"transactionHash": "0x01",
+	}
+
+	var signatureParams json.RawMessage
+	var sent []string
+	chains := map[string]map[string]func(params json.RawMessage) any{
+		"/ext/bc/source-chain/rpc": {
+			"eth_blockNumber": func(json.RawMessage) any { return "0x5" },
+			"eth_getLogs":     func(json.RawMessage) any { return []any{warpLog} },
+			"warp_getMessageAggregateSignature": func(params json.RawMessage) any {
+				signatureParams = params
+				return "0x" + hex.EncodeToString(signed)
+			},
+		},
+		"/ext/bc/dest-chain/rpc": {
+			"eth_chainId":             func(json.RawMessage) any { return "0x1869e" },
+			"eth_getTransactionCount": func(json.RawMessage) any { return "0x0" },
+			"eth_gasPrice":            func(json.RawMessage) any { return "0x5d21dba00" },
+			"eth_estimateGas":         func(json.RawMessage) any { return "0x30000" },
+			"eth_sendRawTransaction": func(params json.RawMessage) any {
+				var raw []string
+				json.Unmarshal(params, &raw)
+				sent = append(sent, raw...)
+				return "0x"
+			},
+			"eth_getTransactionReceipt": func(json.RawMessage) any { return map[string]any{"status": "0x1"} },
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		handler, ok := chains[r.URL.Path][req.Method]
+		if !ok {
+			http.Error(w, "unexpected method "+req.Method, http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": 1, "result": handler(req.Params)})
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := config.DefaultConfig()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	cfg.Node.APIPort, _ = strconv.Atoi(port)
+	ctx := context.Background()
+
+	if _, err := NewRelayer(ctx, store, RelayerOptions{Config: cfg, Source: "source", Destination: "nowarp"}); err == nil {
+		t.Error("expected error relaying to a subnet without warp")
+	}
+	if _, err := NewRelayer(ctx, store, RelayerOptions{Config: cfg, Source: "source", Destination: "source"}); err == nil {
+		t.Error("expected error relaying a subnet to itself")
+	}
+
+	relayer, err := NewRelayer(ctx, store, RelayerOptions{Config: cfg, Source: "source", Destination: "dest", FromBlock: 1})
+	if err != nil {
+		t.Fatalf("Failed to create relayer: %v", err)
+	}
+	relayed, err := relayer.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Failed to relay: %v", err)
+	}
+	if len(relayed) != 1 || relayed[0].ID != CB58Encode(messageID[:]) || relayed[0].DestinationAddress != sender {
+		t.Fatalf("unexpected relayed messages %+v", relayed)
+	}
+	if !bytes.Contains(signatureParams, []byte(`"source-subnet"`)) {
+		t.Errorf("expected signatures to be aggregated for the source subnet, got %s", signatureParams)
+	}
+
+	if len(sent) != 1 {
+		t.Fatalf("expected one delivery tx, got %d", len(sent))
+	}
+	raw, _ := hex.DecodeString(strings.TrimPrefix(sent[0], "0x"))
+	predicate := append(append([]byte{}, signed...), 0xff)
+	if !bytes.Contains(raw, predicate) || !bytes.Contains(raw, receiveMessageSelector) {
+		t.Errorf("expected delivery tx to carry the signed message predicate, got %x", raw)
+	}
+
+	// Blocks already relayed are not polled again
+	if relayed, err := relayer.Poll(ctx); err != nil || len(relayed) != 0 {
+		t.Errorf("expected nothing to relay, got %v: %v", relayed, err)
+	}
 }
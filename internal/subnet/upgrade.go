@@ -0,0 +1,260 @@
+package subnet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+)
+
+const upgradeFile = "upgrade.json"
+
+// UpgradeConfig is a subnet-evm upgrade.json, applied by the node on top of
+// the genesis when a chain starts
+type UpgradeConfig struct {
+	PrecompileUpgrades []PrecompileUpgrade `json:"precompileUpgrades"`
+}
+
+// PrecompileUpgrade enables or disables a single precompile. It holds one
+// entry keyed by the precompile's chain config key, e.g. txAllowListConfig.
+type PrecompileUpgrade map[string]*UpgradeEntry
+
+// UpgradeEntry is the activation of a precompile config at a timestamp
+type UpgradeEntry struct {
+	BlockTimestamp      uint64        `json:"blockTimestamp"`
+	Disable             bool          `json:"disable,omitempty"`
+	AdminAddresses      []string      `json:"adminAddresses,omitempty"`
+	EnabledAddresses    []string      `json:"enabledAddresses,omitempty"`
+	InitialFeeConfig    *FeeConfig    `json:"initialFeeConfig,omitempty"`
+	InitialRewardConfig *RewardConfig `json:"initialRewardConfig,omitempty"`
+}
+
+// NewPrecompileUpgrade creates an upgrade for the named precompile
+func NewPrecompileUpgrade(name string, entry *UpgradeEntry) (PrecompileUpgrade, error) {
+	key, ok := precompileConfigKeys[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown precompile %q (supported: %s)", name, strings.Join(PrecompileNames, ", "))
+	}
+	return PrecompileUpgrade{key: entry}, nil
+}
+
+// Precompile returns the precompile name and entry of the upgrade
+func (u PrecompileUpgrade) Precompile() (string, *UpgradeEntry, error) {
+	if len(u) != 1 {
+		return "", nil, fmt.Errorf("precompile upgrade must configure exactly one precompile, got %d", len(u))
+	}
+	for key, entry := range u {
+		for name, k := range precompileConfigKeys {
+			if k == key && entry != nil {
+				return name, entry, nil
+			}
+		}
+		return "", nil, fmt.Errorf("unknown precompile config %q", key)
+	}
+	return "", nil, nil
+}
+
+// ActivationTime returns when the upgrade takes effect
+func (e *UpgradeEntry) ActivationTime() time.Time {
+	return time.Unix(int64(e.BlockTimestamp), 0).UTC()
+}
+
+// ParseActivationTime parses an activation time given as RFC 3339, unix
+// seconds, or a duration from now such as +10m
+func ParseActivationTime(s string, now time.Time) (time.Time, error) {
+	if strings.HasPrefix(s, "+") {
+		d, err := time.ParseDuration(s[1:])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid activation time %q: %w", s, err)
+		}
+		return now.Add(d).Truncate(time.Second), nil
+	}
+	if unix, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(unix, 0).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid activation time %q, expected RFC 3339, unix seconds or +duration", s)
+	}
+	return t, nil
+}
+
+// ValidateUpgrades checks upgrades against the genesis they apply to:
+// activations must be after genesis and in ascending order, a precompile may
+// only be enabled when disabled and vice versa, and each precompile activates
+// at most once per timestamp
+func ValidateUpgrades(genesis []byte, cfg *UpgradeConfig) error {
+	var g struct {
+		Timestamp string                     `json:"timestamp"`
+		Config    map[string]json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(genesis, &g); err != nil {
+		return fmt.Errorf("failed to parse genesis: %w", err)
+	}
+	genesisTime, err := strconv.ParseUint(strings.TrimPrefix(g.Timestamp, "0x"), 16, 64)
+	if err != nil && g.Timestamp != "" {
+		return fmt.Errorf("invalid genesis timestamp %q", g.Timestamp)
+	}
+
+	enabled := make(map[string]bool)
+	for _, name := range PrecompileNames {
+		_, enabled[name] = g.Config[precompileConfigKeys[name]]
+	}
+
+	var last uint64
+	lastByName := make(map[string]uint64)
+	for i, u := range cfg.PrecompileUpgrades {
+		name, e, err := u.Precompile()
+		if err != nil {
+			return fmt.Errorf("upgrade %d: %w", i, err)
+		}
+
+		if e.BlockTimestamp <= genesisTime {
+			return fmt.Errorf("upgrade %d: %s activation must be after genesis", i, name)
+		}
+		if e.BlockTimestamp < last {
+			return fmt.Errorf("upgrade %d: %s activates at %d, before the previous upgrade at %d", i, name, e.BlockTimestamp, last)
+		}
+		if prev, ok := lastByName[name]; ok && e.BlockTimestamp == prev {
+			return fmt.Errorf("upgrade %d: %s already has an activation at %d", i, name, e.BlockTimestamp)
+		}
+		last, lastByName[name] = e.BlockTimestamp, e.BlockTimestamp
+
+		if err := validateUpgradeEntry(name, e, enabled[name]); err != nil {
+			return fmt.Errorf("upgrade %d: %w", i, err)
+		}
+		enabled[name] = !e.Disable
+	}
+	return nil
+}
+
+// validateUpgradeEntry checks one entry given whether its precompile is
+// active beforehand
+func validateUpgradeEntry(name string, e *UpgradeEntry, active bool) error {
+	if e.Disable {
+		if !active {
+			return fmt.Errorf("cannot disable %s: it is not enabled", name)
+		}
+		if len(e.AdminAddresses) > 0 || len(e.EnabledAddresses) > 0 || e.InitialFeeConfig != nil || e.InitialRewardConfig != nil {
+			return fmt.Errorf("disabling %s cannot also configure it", name)
+		}
+		return nil
+	}
+
+	if active {
+		return fmt.Errorf("cannot enable %s: it is already enabled", name)
+	}
+	if e.InitialFeeConfig != nil && name != PrecompileFeeManager {
+		return fmt.Errorf("an initial fee config requires the %s precompile", PrecompileFeeManager)
+	}
+	if e.InitialRewardConfig != nil && name != PrecompileRewardManager {
+		return fmt.Errorf("an initial reward config requires the %s precompile", PrecompileRewardManager)
+	}
+	if fee := e.InitialFeeConfig; fee != nil && (fee.GasLimit == 0 || fee.TargetGas == 0 || fee.TargetBlockRate == 0) {
+		return fmt.Errorf("initial fee config requires a gas limit, target gas and target block rate")
+	}
+
+	p := &Precompiles{}
+	p.Set(name, &AllowList{Admins: e.AdminAddresses, Enabled: e.EnabledAddresses})
+	if r := e.InitialRewardConfig; r != nil {
+		p.AllowFeeRecipients, p.RewardAddress = r.AllowFeeRecipients, r.RewardAddress
+	}
+	return p.Validate()
+}
+
+// UpgradePath returns the path of a subnet's upgrade.json
+func (s *Store) UpgradePath(name string) string {
+	return filepath.Join(s.Dir(name), upgradeFile)
+}
+
+// Upgrades loads a subnet's upgrades, which are empty if none were authored
+func (s *Store) Upgrades(name string) (*UpgradeConfig, error) {
+	cfg := &UpgradeConfig{PrecompileUpgrades: []PrecompileUpgrade{}}
+	data, err := os.ReadFile(s.UpgradePath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upgrades: %w", err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse upgrades: %w", err)
+	}
+	return cfg, nil
+}
+
+// SaveUpgrades writes a subnet's upgrades
+func (s *Store) SaveUpgrades(name string, cfg *UpgradeConfig) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode upgrades: %w", err)
+	}
+	if err := os.WriteFile(s.UpgradePath(name), data, 0644); err != nil {
+		return fmt.Errorf("failed to write upgrades: %w", err)
+	}
+	return nil
+}
+
+// AddUpgrade validates and appends an upgrade to a subnet-evm subnet. The
+// upgrade must activate after now, since past activations cannot be applied.
+func AddUpgrade(store *Store, name string, upgrade PrecompileUpgrade, now time.Time) (*UpgradeConfig, error) {
+	sn, err := store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if sn.VM != VMSubnetEVM {
+		return nil, fmt.Errorf("upgrades are only supported for %s subnets", VMSubnetEVM)
+	}
+
+	precompile, entry, err := upgrade.Precompile()
+	if err != nil {
+		return nil, err
+	}
+	if !entry.ActivationTime().After(now) {
+		return nil, fmt.Errorf("%s activation %s is not in the future", precompile, entry.ActivationTime().Format(time.RFC3339))
+	}
+
+	genesis, err := store.Genesis(name)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := store.Upgrades(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.PrecompileUpgrades = append(cfg.PrecompileUpgrades, upgrade)
+	if err := ValidateUpgrades(genesis, cfg); err != nil {
+		return nil, err
+	}
+	if err := store.SaveUpgrades(name, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ChainConfigPath returns the path of a file in a blockchain's directory in
+// the node's chain config directory, filling in the directory if unset
+func ChainConfigPath(cfg *config.Config, blockchainID, file string) (string, error) {
+	if err := cfg.SetDefaultDirs(); err != nil {
+		return "", err
+	}
+	return filepath.Join(cfg.Node.ChainConfigDir, blockchainID, file), nil
+}
+
+// InstallUpgrades copies a locally deployed subnet's upgrades into the node's
+// chain config directory, returning the installed path. The node reads them
+// when the chain next starts.
+func InstallUpgrades(store *Store, name string, cfg *config.Config) (string, error) {
+	_, d, err := localDeployment(store, name)
+	if err != nil {
+		return "", err
+	}
+	return installChainFiles(store, name, cfg, d.BlockchainID, upgradeFile)
+}
@@ -0,0 +1,252 @@
+package subnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/node"
+)
+
+// DefaultValidatorWeight is the weight given to subnet validators by default
+const DefaultValidatorWeight = 20
+
+// Validator is a current validator of a subnet or the primary network
+type Validator struct {
+	NodeID    string    `json:"node_id"`
+	Weight    uint64    `json:"weight"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// CurrentValidators lists the validators of a subnet, or of the primary
+// network when subnetID is empty
+func CurrentValidators(ctx context.Context, api *node.APIClient, subnetID string) ([]Validator, error) {
+	params := map[string]any{}
+	if subnetID != "" {
+		params["subnetID"] = subnetID
+	}
+	var resp struct {
+		Validators []struct {
+			NodeID    string     `json:"nodeID"`
+			Weight    jsonUint64 `json:"weight"`
+			StartTime jsonUint64 `json:"startTime"`
+			EndTime   jsonUint64 `json:"endTime"`
+		} `json:"validators"`
+	}
+	if err := api.Call(ctx, platformPath, "platform.getCurrentValidators", params, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get validators: %w", err)
+	}
+
+	validators := make([]Validator, 0, len(resp.Validators))
+	for _, v := range resp.Validators {
+		validators = append(validators, Validator{
+			NodeID:    v.NodeID,
+			Weight:    uint64(v.Weight),
+			StartTime: time.Unix(int64(v.StartTime), 0).UTC(),
+			EndTime:   time.Unix(int64(v.EndTime), 0).UTC(),
+		})
+	}
+	return validators, nil
+}
+
+// ValidatorOptions holds the options for changing a local subnet's validators
+type ValidatorOptions struct {
+	Config *config.Config
+	// NodeIDs are the validators to add or remove. There is no default: the
+	// local node runs without sybil protection, so it already validates every
+	// subnet it tracks and is not a primary network validator that can be added.
+	NodeIDs []string
+	// Weight, Start and End apply when adding; zero values use the defaults:
+	// DefaultValidatorWeight, shortly from now, and the end of the node's
+	// primary network validation
+	Weight uint64
+	Start  time.Time
+	End    time.Time
+	Out    io.Writer
+}
+
+// ErrNoNodeIDs is returned when validators are added or removed without node IDs
+var ErrNoNodeIDs = errors.New("no node IDs given; the local node runs without sybil protection and already " +
+	"validates every subnet it tracks, so name other nodes that validate the primary network")
+
+// ListValidators returns the current validators of a locally deployed subnet
+func ListValidators(ctx context.Context, store *Store, name string, cfg *config.Config) ([]Validator, error) {
+	_, d, err := localDeployment(store, name)
+	if err != nil {
+		return nil, err
+	}
+	return CurrentValidators(ctx, node.NewAPIClient(cfg.Node.APIPort), d.SubnetID)
+}
+
+// AddValidators adds nodes as validators of a locally deployed subnet. Each
+// node must already validate the primary network for the whole period.
+func AddValidators(ctx context.Context, store *Store, name string, opts ValidatorOptions) ([]Validator, error) {
+	if len(opts.NodeIDs) == 0 {
+		return nil, ErrNoNodeIDs
+	}
+	sn, d, err := localDeployment(store, name)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+	if opts.Weight == 0 {
+		opts.Weight = DefaultValidatorWeight
+	}
+
+	api := node.NewAPIClient(opts.Config.Node.APIPort)
+	wallet, subnetID, nodeIDs, err := validatorSetup(api, d, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	primary, err := CurrentValidators(ctx, api, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var added []Validator
+	for _, nodeID := range nodeIDs {
+		v, err := validatorPeriod(nodeID, primary, opts)
+		if err != nil {
+			return added, err
+		}
+		id, err := ParseNodeID(nodeID)
+		if err != nil {
+			return added, err
+		}
+
+		fmt.Fprintf(opts.Out, "Adding %s as a validator of %s...\n", nodeID, name)
+		if _, err := wallet.AddSubnetValidator(ctx, subnetID, id, v.StartTime, v.EndTime, v.Weight); err != nil {
+			return added, fmt.Errorf("failed to add validator %s: %w", nodeID, err)
+		}
+		added = append(added, v)
+
+		if !slices.Contains(d.Validators, nodeID) {
+			d.Validators = append(d.Validators, nodeID)
+		}
+		if err := store.Update(sn); err != nil {
+			return added, err
+		}
+	}
+	return added, nil
+}
+
+// RemoveValidators removes nodes from the validator set of a locally deployed
+// subnet, returning the removed node IDs
+func RemoveValidators(ctx context.Context, store *Store, name string, opts ValidatorOptions) ([]string, error) {
+	if len(opts.NodeIDs) == 0 {
+		return nil, ErrNoNodeIDs
+	}
+	sn, d, err := localDeployment(store, name)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+
+	api := node.NewAPIClient(opts.Config.Node.APIPort)
+	wallet, subnetID, nodeIDs, err := validatorSetup(api, d, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, nodeID := range nodeIDs {
+		id, err := ParseNodeID(nodeID)
+		if err != nil {
+			return removed, err
+		}
+
+		fmt.Fprintf(opts.Out, "Removing %s from the validators of %s...\n", nodeID, name)
+		if _, err := wallet.RemoveSubnetValidator(ctx, subnetID, id); err != nil {
+			return removed, fmt.Errorf("failed to remove validator %s: %w", nodeID, err)
+		}
+		removed = append(removed, nodeID)
+
+		d.Validators = slices.DeleteFunc(d.Validators, func(v string) bool { return v == nodeID })
+		if err := store.Update(sn); err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+// LocalNodeIDs returns the node IDs of the local network's nodes
+func LocalNodeIDs(ctx context.Context, api *node.APIClient) ([]string, error) {
+	var resp struct {
+		NodeID string `json:"nodeID"`
+	}
+	if err := api.Call(ctx, "/ext/info", "info.getNodeID", nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get node ID: %w", err)
+	}
+	return []string{resp.NodeID}, nil
+}
+
+// localDeployment returns a subnet and its local deployment
+func localDeployment(store *Store, name string) (*Subnet, *Deployment, error) {
+	sn, err := store.Get(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	d := sn.Deployment("local")
+	if d == nil {
+		return nil, nil, fmt.Errorf("subnet %s is not deployed to local", name)
+	}
+	return sn, d, nil
+}
+
+// validatorSetup prepares the wallet, subnet ID and node IDs to act on
+func validatorSetup(api *node.APIClient, d *Deployment, opts ValidatorOptions) (*Wallet, ID, []string, error) {
+	subnetID, err := ParseID(d.SubnetID)
+	if err != nil {
+		return nil, ID{}, nil, fmt.Errorf("invalid subnet ID: %w", err)
+	}
+	wallet, err := NewWallet(api, EwoqKey(), uint32(opts.Config.Node.NetworkID))
+	if err != nil {
+		return nil, ID{}, nil, err
+	}
+
+	for _, nodeID := range opts.NodeIDs {
+		if _, err := ParseNodeID(nodeID); err != nil {
+			return nil, ID{}, nil, err
+		}
+	}
+	return wallet, subnetID, opts.NodeIDs, nil
+}
+
+// validatorPeriod resolves a subnet validator's period, which must lie within
+// the node's primary network validation
+func validatorPeriod(nodeID string, primary []Validator, opts ValidatorOptions) (Validator, error) {
+	idx := slices.IndexFunc(primary, func(v Validator) bool { return v.NodeID == nodeID })
+	if idx < 0 {
+		return Validator{}, fmt.Errorf("node %s is not a primary network validator; subnet validators must validate the primary network "+
+			"(a local node running without sybil protection already validates every subnet it tracks)", nodeID)
+	}
+	p := primary[idx]
+
+	v := Validator{NodeID: nodeID, Weight: opts.Weight, StartTime: opts.Start, EndTime: opts.End}
+	if v.StartTime.IsZero() {
+		// Leave time for the transaction to be accepted before it starts
+		v.StartTime = time.Now().Add(30 * time.Second).Truncate(time.Second).UTC()
+	}
+	if v.EndTime.IsZero() {
+		v.EndTime = p.EndTime
+	}
+
+	if !v.EndTime.After(v.StartTime) {
+		return Validator{}, fmt.Errorf("validator end time must be after its start time")
+	}
+	if v.StartTime.Before(p.StartTime) || v.EndTime.After(p.EndTime) {
+		return Validator{}, fmt.Errorf("validation period of %s must be within its primary network validation (%s to %s)",
+			nodeID, p.StartTime.Format(time.RFC3339), p.EndTime.Format(time.RFC3339))
+	}
+	return v, nil
+}
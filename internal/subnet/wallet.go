@@ -0,0 +1,331 @@
+package subnet
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kinetic-dev/kinetic/internal/node"
+)
+
+const platformPath = "/ext/bc/P"
+
+// jsonUint64 decodes avalanchego's quoted uint64 values
+type jsonUint64 uint64
+
+func (u *jsonUint64) UnmarshalJSON(b []byte) error {
+	v, err := strconv.ParseUint(strings.Trim(string(b), `"`), 10, 64)
+	if err != nil {
+		return err
+	}
+	*u = jsonUint64(v)
+	return nil
+}
+
+// Wallet issues P-Chain transactions funded and signed by a single key
+type Wallet struct {
+	api       *node.APIClient
+	key       *Key
+	networkID uint32
+	address   string
+
+	// PollInterval and Timeout control how long to wait for acceptance
+	PollInterval time.Duration
+	Timeout      time.Duration
+}
+
+// NewWallet creates a wallet for the given node API and key
+func NewWallet(api *node.APIClient, key *Key, networkID uint32) (*Wallet, error) {
+	address, err := FormatAddress("P", networkID, key.Address())
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet{
+		api:          api,
+		key:          key,
+		networkID:    networkID,
+		address:      address,
+		PollInterval: 500 * time.Millisecond,
+		Timeout:      30 * time.Second,
+	}, nil
+}
+
+// Owner returns output owners consisting of the wallet's key alone
+func (w *Wallet) Owner() OutputOwners {
+	return OutputOwners{Threshold: 1, Addrs: []ShortID{w.key.Address()}}
+}
+
+// CreateSubnet issues a CreateSubnetTx owned by the wallet's key and waits
+// for it to be committed. The transaction ID is the new subnet ID.
+func (w *Wallet) CreateSubnet(ctx context.Context) (ID, error) {
+	fees, err := w.fees(ctx)
+	if err != nil {
+		return ID{}, err
+	}
+	base, err := w.spend(ctx, uint64(fees.CreateSubnetTxFee))
+	if err != nil {
+		return ID{}, err
+	}
+
+	tx := createSubnetTx{baseTx: base, Owner: w.Owner()}
+	return w.issue(ctx, tx, len(base.Ins))
+}
+
+// CreateChain issues a CreateChainTx on a wallet-owned subnet and waits for
+// it to be committed. The transaction ID is the new blockchain ID.
+func (w *Wallet) CreateChain(ctx context.Context, subnetID ID, name string, vmID ID, genesis []byte) (ID, error) {
+	fees, err := w.fees(ctx)
+	if err != nil {
+		return ID{}, err
+	}
+	base, err := w.spend(ctx, uint64(fees.CreateBlockchainTxFee))
+	if err != nil {
+		return ID{}, err
+	}
+
+	tx := createChainTx{
+		baseTx:            base,
+		SubnetID:          subnetID,
+		ChainName:         name,
+		VMID:              vmID,
+		GenesisData:       genesis,
+		SubnetAuthIndices: []uint32{0},
+	}
+	// One credential per input plus one for the subnet owner
+	return w.issue(ctx, tx, len(base.Ins)+1)
+}
+
+// AddSubnetValidator adds a primary network validator to a wallet-owned
+// subnet for the given period and waits for the transaction to be committed
+func (w *Wallet) AddSubnetValidator(ctx context.Context, subnetID ID, nodeID ShortID, start, end time.Time, weight uint64) (ID, error) {
+	fees, err := w.fees(ctx)
+	if err != nil {
+		return ID{}, err
+	}
+	fee := fees.AddSubnetValidatorFee
+	if fee == 0 {
+		fee = fees.TxFee
+	}
+	base, err := w.spend(ctx, uint64(fee))
+	if err != nil {
+		return ID{}, err
+	}
+
+	tx := addSubnetValidatorTx{
+		baseTx:            base,
+		NodeID:            nodeID,
+		Start:             uint64(start.Unix()),
+		End:               uint64(end.Unix()),
+		Weight:            weight,
+		SubnetID:          subnetID,
+		SubnetAuthIndices: []uint32{0},
+	}
+	return w.issue(ctx, tx, len(base.Ins)+1)
+}
+
+// RemoveSubnetValidator removes a validator from a wallet-owned subnet and
+// waits for the transaction to be committed
+func (w *Wallet) RemoveSubnetValidator(ctx context.Context, subnetID ID, nodeID ShortID) (ID, error) {
+	fees, err := w.fees(ctx)
+	if err != nil {
+		return ID{}, err
+	}
+	base, err := w.spend(ctx, uint64(fees.TxFee))
+	if err != nil {
+		return ID{}, err
+	}
+
+	tx := removeSubnetValidatorTx{
+		baseTx:            base,
+		NodeID:            nodeID,
+		SubnetID:          subnetID,
+		SubnetAuthIndices: []uint32{0},
+	}
+	return w.issue(ctx, tx, len(base.Ins)+1)
+}
+
+// txFees are the P-Chain fees reported by info.getTxFee
+type txFees struct {
+	TxFee                 jsonUint64 `json:"txFee"`
+	CreateSubnetTxFee     jsonUint64 `json:"createSubnetTxFee"`
+	CreateBlockchainTxFee jsonUint64 `json:"createBlockchainTxFee"`
+	AddSubnetValidatorFee jsonUint64 `json:"addSubnetValidatorFee"`
+}
+
+func (w *Wallet) fees(ctx context.Context) (*txFees, error) {
+	fees := &txFees{}
+	if err := w.api.Call(ctx, "/ext/info", "info.getTxFee", nil, fees); err != nil {
+		return nil, err
+	}
+	return fees, nil
+}
+
+// spend builds a base transaction consuming enough AVAX UTXOs to pay fee,
+// returning change to the wallet
+func (w *Wallet) spend(ctx context.Context, fee uint64) (baseTx, error) {
+	var assetResp struct {
+		AssetID string `json:"assetID"`
+	}
+	if err := w.api.Call(ctx, platformPath, "platform.getStakingAssetID", nil, &assetResp); err != nil {
+		return baseTx{}, err
+	}
+	avaxID, err := ParseID(assetResp.AssetID)
+	if err != nil {
+		return baseTx{}, err
+	}
+
+	utxos, err := w.utxos(ctx)
+	if err != nil {
+		return baseTx{}, err
+	}
+
+	me := w.key.Address()
+	var ins []transferableInput
+	var consumed uint64
+	for _, u := range utxos {
+		if consumed >= fee {
+			break
+		}
+		if u.AssetID != avaxID || u.Owners.Locktime != 0 || u.Owners.Threshold != 1 {
+			continue
+		}
+		sigIndex := -1
+		for i, addr := range u.Owners.Addrs {
+			if addr == me {
+				sigIndex = i
+				break
+			}
+		}
+		if sigIndex < 0 {
+			continue
+		}
+
+		ins = append(ins, transferableInput{
+			TxID:        u.TxID,
+			OutputIndex: u.OutputIndex,
+			AssetID:     u.AssetID,
+			Amount:      u.Amount,
+			SigIndices:  []uint32{uint32(sigIndex)},
+		})
+		consumed += u.Amount
+	}
+	if consumed < fee {
+		return baseTx{}, fmt.Errorf("insufficient funds in %s: need %d nAVAX, have %d", w.address, fee, consumed)
+	}
+	sortInputs(ins)
+
+	var outs []transferableOutput
+	if change := consumed - fee; change > 0 {
+		outs = append(outs, transferableOutput{AssetID: avaxID, Amount: change, Owners: w.Owner()})
+	}
+
+	return baseTx{NetworkID: w.networkID, Outs: outs, Ins: ins}, nil
+}
+
+// utxos fetches the wallet's P-Chain UTXOs
+func (w *Wallet) utxos(ctx context.Context) ([]*utxo, error) {
+	params := map[string]any{
+		"addresses": []string{w.address},
+		"limit":     1024,
+		"encoding":  "hex",
+	}
+	var resp struct {
+		UTXOs []string `json:"utxos"`
+	}
+	if err := w.api.Call(ctx, platformPath, "platform.getUTXOs", params, &resp); err != nil {
+		return nil, err
+	}
+
+	var utxos []*utxo
+	for _, encoded := range resp.UTXOs {
+		b, err := decodeHex(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode UTXO: %w", err)
+		}
+		u, err := parseUTXO(b)
+		if err != nil {
+			return nil, err
+		}
+		if u != nil {
+			utxos = append(utxos, u)
+		}
+	}
+	return utxos, nil
+}
+
+// issue signs and submits a transaction, then waits for it to be committed
+func (w *Wallet) issue(ctx context.Context, tx unsignedTx, credentials int) (ID, error) {
+	signed, txID := signTx(tx, w.key, credentials)
+
+	params := map[string]string{"tx": encodeHex(signed), "encoding": "hex"}
+	if err := w.api.Call(ctx, platformPath, "platform.issueTx", params, nil); err != nil {
+		return ID{}, err
+	}
+
+	if err := w.waitForCommit(ctx, txID); err != nil {
+		return ID{}, err
+	}
+	return txID, nil
+}
+
+// waitForCommit polls the transaction status until it is committed
+func (w *Wallet) waitForCommit(ctx context.Context, txID ID) error {
+	ticker := time.NewTicker(w.PollInterval)
+	defer ticker.Stop()
+
+	timeoutCh := time.After(w.Timeout)
+
+	for {
+		var resp struct {
+			Status string `json:"status"`
+			Reason string `json:"reason"`
+		}
+		params := map[string]string{"txID": txID.String()}
+		if err := w.api.Call(ctx, platformPath, "platform.getTxStatus", params, &resp); err != nil {
+			return err
+		}
+
+		switch resp.Status {
+		case "Committed":
+			return nil
+		case "Dropped", "Aborted":
+			return fmt.Errorf("transaction %s was %s: %s", txID, strings.ToLower(resp.Status), resp.Reason)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeoutCh:
+			return fmt.Errorf("timeout waiting for transaction %s to be committed", txID)
+		case <-ticker.C:
+		}
+	}
+}
+
+// encodeHex encodes bytes in avalanchego's checksummed hex format
+func encodeHex(b []byte) string {
+	checksum := sha256.Sum256(b)
+	return "0x" + hex.EncodeToString(append(append([]byte{}, b...), checksum[len(checksum)-4:]...))
+}
+
+// decodeHex decodes avalanchego's checksummed hex format
+func decodeHex(s string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < 4 {
+		return nil, fmt.Errorf("input too short")
+	}
+	payload, checksum := b[:len(b)-4], b[len(b)-4:]
+	expected := sha256.Sum256(payload)
+	if !bytes.Equal(checksum, expected[len(expected)-4:]) {
+		return nil, fmt.Errorf("invalid checksum")
+	}
+	return payload, nil
+}
@@ -0,0 +1,293 @@
+package subnet
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kinetic-dev/kinetic/internal/config"
+	"github.com/kinetic-dev/kinetic/internal/node"
+)
+
+// WarpPrecompileAddress is the address of subnet-evm's warp precompile
+const WarpPrecompileAddress = "0x0200000000000000000000000000000000000005"
+
+// DefaultWarpQuorum is the percentage of stake that must sign a warp message
+const DefaultWarpQuorum = 67
+
+// warpAPIKey is the chain config key that serves the warp_ RPC namespace the
+// relayer fetches signatures from
+const warpAPIKey = "warp-api-enabled"
+
+var (
+	// sendWarpMessageTopic identifies SendWarpMessage(address,bytes32,bytes)
+	// logs emitted by the warp precompile
+	sendWarpMessageTopic = "0x" + hex.EncodeToString(keccak256([]byte("SendWarpMessage(address,bytes32,bytes)")))
+	// receiveMessageSelector calls receiveCrossChainMessage(uint32,address),
+	// the delivery entry point of Teleporter messengers
+	receiveMessageSelector = keccak256([]byte("receiveCrossChainMessage(uint32,address)"))[:4]
+)
+
+// WarpConfig enables Avalanche Warp Messaging on a subnet-evm chain
+type WarpConfig struct {
+	BlockTimestamp  uint64 `json:"blockTimestamp"`
+	QuorumNumerator uint64 `json:"quorumNumerator,omitempty"`
+}
+
+// WarpEnabled reports whether a subnet-evm genesis enables warp messaging
+func WarpEnabled(genesis []byte) bool {
+	var g Genesis
+	return json.Unmarshal(genesis, &g) == nil && g.Config.WarpConfig != nil
+}
+
+// WarpMessage is a warp message sent from a source chain
+type WarpMessage struct {
+	// ID is the unsigned message ID in CB58
+	ID            string `json:"message_id"`
+	SourceAddress string `json:"source_address"`
+	TxHash        string `json:"source_tx"`
+	// Unsigned is the unsigned message bytes
+	Unsigned []byte `json:"-"`
+}
+
+// parseWarpLog decodes a SendWarpMessage log
+func parseWarpLog(l evmLog) (*WarpMessage, error) {
+	if len(l.Topics) != 3 || l.Topics[0] != sendWarpMessageTopic {
+		return nil, fmt.Errorf("not a SendWarpMessage log")
+	}
+	sourceAddress, err := hex.DecodeString(strings.TrimPrefix(l.Topics[1], "0x"))
+	if err != nil || len(sourceAddress) != 32 {
+		return nil, fmt.Errorf("invalid warp source address")
+	}
+	messageID, err := hex.DecodeString(strings.TrimPrefix(l.Topics[2], "0x"))
+	if err != nil || len(messageID) != 32 {
+		return nil, fmt.Errorf("invalid warp message ID")
+	}
+
+	// The data is the ABI encoding of a single bytes value: offset, length,
+	// then the padded bytes
+	data, err := hex.DecodeString(strings.TrimPrefix(l.Data, "0x"))
+	if err != nil || len(data) < 64 {
+		return nil, fmt.Errorf("invalid warp message data")
+	}
+	size := binary.BigEndian.Uint64(data[56:64])
+	if uint64(len(data)-64) < size {
+		return nil, fmt.Errorf("truncated warp message")
+	}
+
+	return &WarpMessage{
+		ID:            CB58Encode(messageID),
+		SourceAddress: "0x" + hex.EncodeToString(sourceAddress[12:]),
+		TxHash:        l.TxHash,
+		Unsigned:      data[64 : 64+size],
+	}, nil
+}
+
+// warpPredicate packs a signed warp message into the storage keys of the warp
+// precompile's access list entry: the bytes, a 0xff delimiter, and zero
+// padding to a multiple of 32 bytes
+func warpPredicate(signed []byte) accessTuple {
+	packed := append(append([]byte{}, signed...), 0xff)
+	if rem := len(packed) % 32; rem != 0 {
+		packed = append(packed, make([]byte, 32-rem)...)
+	}
+
+	address, _ := hex.DecodeString(strings.TrimPrefix(WarpPrecompileAddress, "0x"))
+	tuple := accessTuple{Address: address}
+	for i := 0; i < len(packed); i += 32 {
+		tuple.StorageKeys = append(tuple.StorageKeys, packed[i:i+32])
+	}
+	return tuple
+}
+
+// RelayerOptions holds the options for relaying warp messages between two
+// locally deployed subnets
+type RelayerOptions struct {
+	Config      *config.Config
+	Source      string
+	Destination string
+	// DestinationAddress receives messages through receiveCrossChainMessage;
+	// empty delivers to the sender's address on the destination chain, as
+	// Teleporter messengers share an address across chains
+	DestinationAddress string
+	// Key pays for delivery transactions; nil uses the local ewoq key
+	Key *Key
+	// FromBlock is the first source block to relay; zero starts at the next
+	// block produced
+	FromBlock    uint64
+	PollInterval time.Duration
+	Out          io.Writer
+}
+
+// RelayedMessage is a warp message delivered to the destination chain
+type RelayedMessage struct {
+	WarpMessage
+	DestinationAddress string `json:"destination_address"`
+	DeliveryTx         string `json:"delivery_tx"`
+}
+
+// Relayer delivers warp messages from one local subnet to another
+type Relayer struct {
+	opts      RelayerOptions
+	source    *evmClient
+	dest      *evmClient
+	subnetID  string
+	nextBlock uint64
+	delivered map[string]bool
+}
+
+// NewRelayer checks that both subnets are deployed locally with warp enabled
+// and connects to their chains
+func NewRelayer(ctx context.Context, store *Store, opts RelayerOptions) (*Relayer, error) {
+	if opts.Source == opts.Destination {
+		return nil, fmt.Errorf("source and destination subnets must differ")
+	}
+	if opts.Key == nil {
+		opts.Key = EwoqKey()
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = 2 * time.Second
+	}
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+	if opts.DestinationAddress != "" {
+		if err := ValidateAddress(opts.DestinationAddress); err != nil {
+			return nil, fmt.Errorf("invalid destination address: %w", err)
+		}
+	}
+
+	deployments := make([]*Deployment, 2)
+	for i, name := range []string{opts.Source, opts.Destination} {
+		sn, d, err := localDeployment(store, name)
+		if err != nil {
+			return nil, err
+		}
+		if sn.VM != VMSubnetEVM {
+			return nil, fmt.Errorf("subnet %s does not run subnet-evm; warp relaying needs the warp precompile", name)
+		}
+		genesis, err := store.Genesis(name)
+		if err != nil {
+			return nil, err
+		}
+		if !WarpEnabled(genesis) {
+			return nil, fmt.Errorf("subnet %s does not enable warp; create it with --warp", name)
+		}
+		deployments[i] = d
+	}
+
+	api := node.NewAPIClient(opts.Config.Node.APIPort)
+	r := &Relayer{
+		opts:      opts,
+		source:    newEVMClient(api, deployments[0].BlockchainID),
+		dest:      newEVMClient(api, deployments[1].BlockchainID),
+		subnetID:  deployments[0].SubnetID,
+		nextBlock: opts.FromBlock,
+		delivered: make(map[string]bool),
+	}
+	if r.nextBlock == 0 {
+		latest, err := r.source.blockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reach %s: %w", opts.Source, err)
+		}
+		r.nextBlock = latest + 1
+	}
+	return r, nil
+}
+
+// Run relays messages until the context is cancelled. Failed polls are
+// reported and retried, so a message is delivered once its chains recover.
+func (r *Relayer) Run(ctx context.Context) []RelayedMessage {
+	var relayed []RelayedMessage
+	for {
+		batch, err := r.Poll(ctx)
+		relayed = append(relayed, batch...)
+		if ctx.Err() != nil {
+			return relayed
+		}
+		if err != nil {
+			fmt.Fprintf(r.opts.Out, "Error: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return relayed
+		case <-time.After(r.opts.PollInterval):
+		}
+	}
+}
+
+// Poll relays the messages sent in source blocks produced since the last poll
+func (r *Relayer) Poll(ctx context.Context) ([]RelayedMessage, error) {
+	latest, err := r.source.blockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s block number: %w", r.opts.Source, err)
+	}
+	if latest < r.nextBlock {
+		return nil, nil
+	}
+
+	logs, err := r.source.logs(ctx, r.nextBlock, latest, WarpPrecompileAddress, sendWarpMessageTopic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warp messages from %s: %w", r.opts.Source, err)
+	}
+
+	var relayed []RelayedMessage
+	for _, l := range logs {
+		msg, err := parseWarpLog(l)
+		if err != nil {
+			return relayed, err
+		}
+		if r.delivered[msg.ID] {
+			continue
+		}
+		delivered, err := r.deliver(ctx, msg)
+		if err != nil {
+			return relayed, fmt.Errorf("failed to relay warp message %s: %w", msg.ID, err)
+		}
+		r.delivered[msg.ID] = true
+		relayed = append(relayed, *delivered)
+	}
+	r.nextBlock = latest + 1
+	return relayed, nil
+}
+
+// deliver aggregates the validator signatures of a message on the source
+// chain and submits it to the destination chain
+func (r *Relayer) deliver(ctx context.Context, msg *WarpMessage) (*RelayedMessage, error) {
+	fmt.Fprintf(r.opts.Out, "Relaying warp message %s from %s...\n", msg.ID, msg.SourceAddress)
+
+	var signed string
+	params := []any{msg.ID, DefaultWarpQuorum, r.subnetID}
+	if err := r.source.api.Call(ctx, r.source.path, "warp_getMessageAggregateSignature", params, &signed); err != nil {
+		return nil, fmt.Errorf("failed to aggregate signatures (is %s enabled in the chain config?): %w", warpAPIKey, err)
+	}
+	signedBytes, err := hex.DecodeString(strings.TrimPrefix(signed, "0x"))
+	if err != nil || len(signedBytes) == 0 {
+		return nil, fmt.Errorf("invalid signed warp message")
+	}
+
+	to := r.opts.DestinationAddress
+	if to == "" {
+		to = msg.SourceAddress
+	}
+
+	// receiveCrossChainMessage(messageIndex 0, relayerRewardAddress)
+	relayer, _ := hex.DecodeString(strings.TrimPrefix(r.opts.Key.EVMAddress(), "0x"))
+	data := append([]byte{}, receiveMessageSelector...)
+	data = append(data, make([]byte, 32)...)
+	data = append(data, make([]byte, 12)...)
+	data = append(data, relayer...)
+
+	txHash, err := r.dest.sendTx(ctx, r.opts.Key, to, data, []accessTuple{warpPredicate(signedBytes)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to deliver to %s: %w", r.opts.Destination, err)
+	}
+	fmt.Fprintf(r.opts.Out, "Delivered to %s in %s\n", to, txHash)
+	return &RelayedMessage{WarpMessage: *msg, DestinationAddress: to, DeliveryTx: txHash}, nil
+}
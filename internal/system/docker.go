@@ -3,10 +3,14 @@ package system
 import (
 	"context"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/moby/term"
 )
 
 // DockerClient wraps the Docker API client
@@ -58,15 +62,80 @@ func (d *DockerClient) StopContainer(ctx context.Context, containerName string)
 	return nil
 }
 
-// PullImage pulls a Docker image
-func (d *DockerClient) PullImage(ctx context.Context, image string) error {
-	_, err := d.client.ImagePull(ctx, image, types.ImagePullOptions{})
+// PullImage pulls a Docker image, rendering per-layer progress to out
+func (d *DockerClient) PullImage(ctx context.Context, image string, out io.Writer) error {
+	reader, err := d.client.ImagePull(ctx, image, types.ImagePullOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to pull image %s: %w", image, err)
 	}
+	defer reader.Close()
+
+	// The pull only completes once the progress stream has been fully consumed
+	fd, isTerminal := term.GetFdInfo(out)
+	if err := jsonmessage.DisplayJSONMessagesStream(reader, out, fd, isTerminal, nil); err != nil {
+		return fmt.Errorf("failed to pull image %s: %w", image, err)
+	}
 	return nil
 }
 
+// ImageExists checks if an image is present locally
+func (d *DockerClient) ImageExists(ctx context.Context, image string) (bool, error) {
+	_, _, err := d.client.ImageInspectWithRaw(ctx, image)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to inspect image %s: %w", image, err)
+	}
+	return true, nil
+}
+
+// ImageDigest returns the repository digest reference (repo@sha256:...) of a local image
+func (d *DockerClient) ImageDigest(ctx context.Context, image string) (string, error) {
+	inspect, _, err := d.client.ImageInspectWithRaw(ctx, image)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect image %s: %w", image, err)
+	}
+
+	repo := ImageRepository(image)
+	for _, digest := range inspect.RepoDigests {
+		if ImageRepository(digest) == repo {
+			return digest, nil
+		}
+	}
+	return "", fmt.Errorf("image %s has no repository digest", image)
+}
+
+// ServerVersion returns the version of the container engine; creating a
+// client does not contact the daemon, so this is how to check it is reachable
+func (d *DockerClient) ServerVersion(ctx context.Context) (string, error) {
+	v, err := d.client.ServerVersion(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach the container daemon: %w", err)
+	}
+	return v.Version, nil
+}
+
+// RemoveContainer removes a stopped container by name, ignoring missing containers
+func (d *DockerClient) RemoveContainer(ctx context.Context, containerName string) error {
+	err := d.client.ContainerRemove(ctx, containerName, types.ContainerRemoveOptions{})
+	if err != nil && !client.IsErrNotFound(err) {
+		return fmt.Errorf("failed to remove container %s: %w", containerName, err)
+	}
+	return nil
+}
+
+// ImageRepository strips the tag or digest from an image reference
+func ImageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
 // CreateContainer creates a new container
 func (d *DockerClient) CreateContainer(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, name string) error {
 	_, err := d.client.ContainerCreate(ctx, config, hostConfig, nil, nil, name)
@@ -0,0 +1,153 @@
+package system
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// FakeContainer is a container tracked by FakeRuntime
+type FakeContainer struct {
+	Config     *container.Config
+	HostConfig *container.HostConfig
+	Running    bool
+}
+
+// FakeRuntime is an in-memory ContainerRuntime for testing node lifecycle
+// logic without a container daemon
+type FakeRuntime struct {
+	mu         sync.Mutex
+	Images     map[string]string
+	Containers map[string]*FakeContainer
+	Pulls      []string
+	// DaemonErr, when set, is returned by ServerVersion and IsRunning as if
+	// the daemon were unreachable
+	DaemonErr error
+}
+
+// NewFakeRuntime creates an empty in-memory runtime
+func NewFakeRuntime() *FakeRuntime {
+	return &FakeRuntime{
+		Images:     make(map[string]string),
+		Containers: make(map[string]*FakeContainer),
+	}
+}
+
+// IsRunning checks if a container is running
+func (f *FakeRuntime) IsRunning(ctx context.Context, containerName string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.DaemonErr != nil {
+		return false, f.DaemonErr
+	}
+	c, ok := f.Containers[containerName]
+	return ok && c.Running, nil
+}
+
+// CreateContainer creates a new container
+func (f *FakeRuntime) CreateContainer(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.Containers[name]; ok {
+		return fmt.Errorf("failed to create container %s: name already in use", name)
+	}
+	if _, ok := f.Images[config.Image]; !ok {
+		return fmt.Errorf("failed to create container %s: no such image %s", name, config.Image)
+	}
+	f.Containers[name] = &FakeContainer{Config: config, HostConfig: hostConfig}
+	return nil
+}
+
+// StartContainer starts a container by name
+func (f *FakeRuntime) StartContainer(ctx context.Context, containerName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, ok := f.Containers[containerName]
+	if !ok {
+		return fmt.Errorf("failed to start container %s: no such container", containerName)
+	}
+	c.Running = true
+	return nil
+}
+
+// StopContainer stops a container by name
+func (f *FakeRuntime) StopContainer(ctx context.Context, containerName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, ok := f.Containers[containerName]
+	if !ok {
+		return fmt.Errorf("failed to stop container %s: no such container", containerName)
+	}
+	c.Running = false
+	return nil
+}
+
+// RemoveContainer removes a stopped container by name, ignoring missing containers
+func (f *FakeRuntime) RemoveContainer(ctx context.Context, containerName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, ok := f.Containers[containerName]
+	if !ok {
+		return nil
+	}
+	if c.Running {
+		return fmt.Errorf("failed to remove container %s: container is running", containerName)
+	}
+	delete(f.Containers, containerName)
+	return nil
+}
+
+// PullImage records the pull and makes the image available locally
+func (f *FakeRuntime) PullImage(ctx context.Context, image string, out io.Writer) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.Pulls = append(f.Pulls, image)
+	digest := fmt.Sprintf("%s@sha256:%x", ImageRepository(image), sha256.Sum256([]byte(image)))
+	f.Images[image] = digest
+	f.Images[digest] = digest
+	return nil
+}
+
+// ImageExists checks if an image is present locally
+func (f *FakeRuntime) ImageExists(ctx context.Context, image string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.Images[image]
+	return ok, nil
+}
+
+// ImageDigest returns the repository digest reference of a local image
+func (f *FakeRuntime) ImageDigest(ctx context.Context, image string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	digest, ok := f.Images[image]
+	if !ok {
+		return "", fmt.Errorf("failed to inspect image %s: no such image", image)
+	}
+	return digest, nil
+}
+
+// Close is a no-op for the in-memory runtime
+func (f *FakeRuntime) Close() error {
+	return nil
+}
+
+// ServerVersion returns a fixed engine version, or DaemonErr
+func (f *FakeRuntime) ServerVersion(ctx context.Context) (string, error) {
+	if f.DaemonErr != nil {
+		return "", f.DaemonErr
+	}
+	return "fake", nil
+}
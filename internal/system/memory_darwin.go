@@ -0,0 +1,20 @@
+package system
+
+import (
+	"encoding/binary"
+	"fmt"
+	"syscall"
+)
+
+// TotalMemory returns the physical memory of the host in bytes
+func TotalMemory() (uint64, error) {
+	s, err := syscall.Sysctl("hw.memsize")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read memory size: %w", err)
+	}
+	// Sysctl returns the raw little-endian value with trailing zero bytes
+	// trimmed
+	b := make([]byte, 8)
+	copy(b, s)
+	return binary.LittleEndian.Uint64(b), nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+package system
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TotalMemory returns the physical memory of the host in bytes
+func TotalMemory() (uint64, error) {
+	return 0, fmt.Errorf("failed to read memory size: %w", errors.ErrUnsupported)
+}
@@ -1,12 +1,15 @@
 package system
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 )
 
-// GetUserConfigDir returns the path to the user's configuration directory
+// GetUserConfigDir returns the path to the user's configuration directory,
+// under $XDG_CONFIG_HOME on Linux when it is set
 func GetUserConfigDir() (string, error) {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
@@ -22,8 +25,24 @@ func GetUserConfigDir() (string, error) {
 	return kineticDir, nil
 }
 
-// GetDataDir returns the path to the data directory
+// GetDataDir returns the path to the data directory, under $XDG_DATA_HOME on
+// Linux when it is set, creating it if needed
 func GetDataDir() (string, error) {
+	baseDir, err := DataDirPath()
+	if err != nil {
+		return "", err
+	}
+
+	// Create the directory if it doesn't exist
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return "", err
+	}
+
+	return baseDir, nil
+}
+
+// DataDirPath returns the path GetDataDir uses, without creating it
+func DataDirPath() (string, error) {
 	var baseDir string
 	var err error
 
@@ -41,28 +60,27 @@ func GetDataDir() (string, error) {
 		}
 		baseDir = filepath.Join(baseDir, "Library", "Application Support", "Kinetic")
 	default: // linux and others
+		// Relative XDG paths are invalid and ignored, as the spec requires
+		if xdg := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(xdg) {
+			baseDir = filepath.Join(xdg, "kinetic")
+			break
+		}
 		baseDir, err = os.UserHomeDir()
 		if err != nil {
 			return "", err
 		}
 		baseDir = filepath.Join(baseDir, ".local", "share", "kinetic")
 	}
-
-	// Create the directory if it doesn't exist
-	if err := os.MkdirAll(baseDir, 0755); err != nil {
-		return "", err
-	}
-
 	return baseDir, nil
 }
 
-// GetNodeDataDir returns the path to the Avalanche node data directory
+// GetNodeDataDir returns the path to the Avalanche node data directory,
+// creating it if needed
 func GetNodeDataDir() (string, error) {
-	dataDir, err := GetDataDir()
+	nodeDir, err := NodeDataDirPath()
 	if err != nil {
 		return "", err
 	}
-	nodeDir := filepath.Join(dataDir, "node")
 
 	// Create the directory if it doesn't exist
 	if err := os.MkdirAll(nodeDir, 0755); err != nil {
@@ -72,6 +90,28 @@ func GetNodeDataDir() (string, error) {
 	return nodeDir, nil
 }
 
+// NodeDataDirPath returns the path GetNodeDataDir uses, without creating it
+func NodeDataDirPath() (string, error) {
+	dataDir, err := DataDirPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "node"), nil
+}
+
+// ExpandPath expands environment variables and a leading ~ in a path
+func ExpandPath(path string) (string, error) {
+	path = os.ExpandEnv(path)
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to expand %s: %w", path, err)
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
 // EnsureDir ensures a directory exists, creating it if necessary
 func EnsureDir(path string) error {
 	return os.MkdirAll(path, 0755)
@@ -0,0 +1,43 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/client"
+)
+
+// NewPodmanClient creates a client for Podman's Docker-compatible API socket
+func NewPodmanClient() (*DockerClient, error) {
+	host, err := podmanHost()
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := client.NewClientWithOpts(client.WithHost(host), client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Podman client: %w", err)
+	}
+	return &DockerClient{client: cli}, nil
+}
+
+// podmanHost locates the Podman API socket, preferring CONTAINER_HOST
+func podmanHost() (string, error) {
+	if host := os.Getenv("CONTAINER_HOST"); host != "" {
+		return host, nil
+	}
+
+	var candidates []string
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		candidates = append(candidates, filepath.Join(runtimeDir, "podman", "podman.sock"))
+	}
+	candidates = append(candidates, "/run/podman/podman.sock")
+
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return "unix://" + path, nil
+		}
+	}
+	return "", fmt.Errorf("podman socket not found; start it with 'podman system service' or set CONTAINER_HOST")
+}
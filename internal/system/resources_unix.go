@@ -0,0 +1,18 @@
+//go:build !windows
+
+package system
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// DiskFree returns the bytes available to unprivileged users on the
+// filesystem holding path
+func DiskFree(path string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, fmt.Errorf("failed to get free space of %s: %w", path, err)
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil
+}
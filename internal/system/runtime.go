@@ -0,0 +1,35 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainerRuntime abstracts the container engine used to run the node
+type ContainerRuntime interface {
+	IsRunning(ctx context.Context, containerName string) (bool, error)
+	CreateContainer(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, name string) error
+	StartContainer(ctx context.Context, containerName string) error
+	StopContainer(ctx context.Context, containerName string) error
+	RemoveContainer(ctx context.Context, containerName string) error
+	PullImage(ctx context.Context, image string, out io.Writer) error
+	ImageExists(ctx context.Context, image string) (bool, error)
+	ImageDigest(ctx context.Context, image string) (string, error)
+	ServerVersion(ctx context.Context) (string, error)
+	Close() error
+}
+
+// NewContainerRuntime creates the container runtime with the given name
+func NewContainerRuntime(name string) (ContainerRuntime, error) {
+	switch name {
+	case "", "docker":
+		return NewDockerClient()
+	case "podman":
+		return NewPodmanClient()
+	default:
+		return nil, fmt.Errorf("unsupported container runtime %q (supported: docker, podman)", name)
+	}
+}
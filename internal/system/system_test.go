@@ -34,6 +34,7 @@ func TestGetUserConfigDir(t *testing.T) {
 }
 
 func TestGetDataDir(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "")
 	// Create a temporary directory for test
 	tmpDir, err := os.MkdirTemp("", "data-test")
 	if err != nil {
@@ -69,6 +70,61 @@ func TestGetDataDir(t *testing.T) {
 	}
 }
 
+func TestGetDataDirXDG(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG directories are only used on Linux")
+	}
+	xdg := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", xdg)
+
+	dataDir, err := GetDataDir()
+	if err != nil {
+		t.Fatalf("Failed to get data dir: %v", err)
+	}
+	if want := filepath.Join(xdg, "kinetic"); dataDir != want {
+		t.Errorf("GetDataDir() = %s, want %s", dataDir, want)
+	}
+
+	// Relative values are ignored
+	t.Setenv("XDG_DATA_HOME", "relative")
+	if dataDir, err = GetDataDir(); err != nil || !strings.Contains(dataDir, ".local/share/kinetic") {
+		t.Errorf("GetDataDir() = %s, %v; want the default directory", dataDir, err)
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KINETIC_TEST_DIR", "/srv/kinetic")
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "home", path: "~", want: home},
+		{name: "under home", path: "~/avalanche/db", want: filepath.Join(home, "avalanche", "db")},
+		{name: "environment variable", path: "$KINETIC_TEST_DIR/logs", want: "/srv/kinetic/logs"},
+		{name: "braced variable", path: "${KINETIC_TEST_DIR}/db", want: "/srv/kinetic/db"},
+		{name: "other user", path: "~alice/db", want: "~alice/db"},
+		{name: "plain", path: "/var/lib/kinetic", want: "/var/lib/kinetic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandPath(tt.path)
+			if err != nil {
+				t.Fatalf("ExpandPath() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetNodeDataDir(t *testing.T) {
 	// Create a temporary directory for test
 	tmpDir, err := os.MkdirTemp("", "node-test")
@@ -158,3 +214,40 @@ func TestDockerClient(t *testing.T) {
 		t.Error("Docker client connection is nil")
 	}
 }
+
+func TestImageRepository(t *testing.T) {
+	tests := []struct {
+		name  string
+		image string
+		want  string
+	}{
+		{
+			name:  "tagged image",
+			image: "avaplatform/avalanchego:latest",
+			want:  "avaplatform/avalanchego",
+		},
+		{
+			name:  "digest pinned image",
+			image: "avaplatform/avalanchego@sha256:0123456789abcdef",
+			want:  "avaplatform/avalanchego",
+		},
+		{
+			name:  "registry with port",
+			image: "localhost:5000/avalanchego:v1.11.3",
+			want:  "localhost:5000/avalanchego",
+		},
+		{
+			name:  "untagged image",
+			image: "localhost:5000/avalanchego",
+			want:  "localhost:5000/avalanchego",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ImageRepository(tt.image); got != tt.want {
+				t.Errorf("ImageRepository(%q) = %q, want %q", tt.image, got, tt.want)
+			}
+		})
+	}
+}
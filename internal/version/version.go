@@ -0,0 +1,90 @@
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Build metadata, set by release builds with
+//
+//	go build -ldflags "-X github.com/kinetic-dev/kinetic/internal/version.Version=v0.4.0
+//	  -X github.com/kinetic-dev/kinetic/internal/version.Commit=$(git rev-parse HEAD)
+//	  -X github.com/kinetic-dev/kinetic/internal/version.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Values left empty are read from the Go build info where possible.
+var (
+	Version string
+	Commit  string
+	Date    string
+)
+
+// devVersion is reported by builds without a release version
+const devVersion = "dev"
+
+// Info describes the running Kinetic build
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// Get returns the build info, preferring the ldflags values over the module
+// version and VCS stamps recorded by the Go toolchain
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		// go install module@version records the module version
+		if info.Version == "" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = devVersion
+	}
+	return info
+}
+
+// String returns the version with its short commit and build date
+func (i Info) String() string {
+	s := i.Version
+	commit := i.Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	if i.Modified {
+		commit += "-dirty"
+	}
+	switch {
+	case commit != "" && i.Date != "":
+		s += fmt.Sprintf(" (commit %s, built %s)", commit, i.Date)
+	case commit != "":
+		s += fmt.Sprintf(" (commit %s)", commit)
+	case i.Date != "":
+		s += fmt.Sprintf(" (built %s)", i.Date)
+	}
+	return s
+}
@@ -0,0 +1,52 @@
+package version
+
+import "testing"
+
+func TestInfoString(t *testing.T) {
+	tests := []struct {
+		name string
+		info Info
+		want string
+	}{
+		{
+			name: "release",
+			info: Info{Version: "v0.4.0", Commit: "0123456789abcdef0123", Date: "2024-05-01T12:00:00Z"},
+			want: "v0.4.0 (commit 0123456789ab, built 2024-05-01T12:00:00Z)",
+		},
+		{
+			name: "modified",
+			info: Info{Version: "dev", Commit: "0123456789ab", Modified: true},
+			want: "dev (commit 0123456789ab-dirty)",
+		},
+		{
+			name: "no metadata",
+			info: Info{Version: "dev"},
+			want: "dev",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.info.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	defer func(v, c, d string) { Version, Commit, Date = v, c, d }(Version, Commit, Date)
+	Version, Commit, Date = "v0.4.0", "abc", "2024-05-01"
+
+	info := Get()
+	if info.Version != "v0.4.0" || info.Commit != "abc" || info.Date != "2024-05-01" {
+		t.Errorf("ldflags values not used: %+v", info)
+	}
+	if info.GoVersion == "" || info.Platform == "" {
+		t.Errorf("missing toolchain info: %+v", info)
+	}
+
+	Version = ""
+	if info := Get(); info.Version == "" {
+		t.Error("expected a fallback version")
+	}
+}
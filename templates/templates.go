@@ -0,0 +1,9 @@
+package templates
+
+import "embed"
+
+// Contracts holds the contract templates and their config.json under
+// contracts/, embedded so Kinetic finds them wherever it runs
+//
+//go:embed contracts
+var Contracts embed.FS